@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/leeovery/portal/internal/alias"
 	"github.com/leeovery/portal/internal/resolver"
@@ -60,17 +62,33 @@ var aliasListCmd = &cobra.Command{
 var aliasSetCmd = &cobra.Command{
 	Use:   "set [name] [path]",
 	Short: "Set a path alias",
-	Args:  cobra.ExactArgs(2),
+	Long: `Set a path alias. Relative paths and a leading ~ are made absolute, unless the
+path starts with another alias (work/api) or uses $VARS, which are kept as
+given and expanded whenever the alias is used.
+
+A warning is printed when the alias does not resolve to an existing directory
+or uses an unset variable.`,
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
-		normalised := resolver.NormalisePath(args[1])
 
 		store, err := loadAliasStore()
 		if err != nil {
 			return err
 		}
 
-		store.Set(name, normalised)
+		store.Set(name, aliasValue(store, args[1]))
+
+		path, _, err := resolver.ExpandAlias(store, name)
+		var unset *resolver.UnsetVariableError
+		switch {
+		case errors.As(err, &unset):
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+		case err != nil:
+			return err
+		case !(&resolver.OSDirValidator{}).Exists(path):
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: alias %s resolves to %s, which is not an existing directory\n", name, path)
+		}
 
 		if err := store.Save(); err != nil {
 			return fmt.Errorf("failed to save aliases: %w", err)
@@ -80,6 +98,50 @@ var aliasSetCmd = &cobra.Command{
 	},
 }
 
+// aliasValue returns the value stored for an alias set to value. Values that
+// reference another alias or an environment variable are kept as given, so
+// they follow later changes; other paths are normalised to absolute.
+func aliasValue(aliases resolver.AliasLookup, value string) string {
+	if strings.Contains(value, "$") {
+		return value
+	}
+	if _, ok, _ := resolver.ExpandAlias(aliases, value); ok {
+		return value
+	}
+	return resolver.NormalisePath(value)
+}
+
+var aliasLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Report problems in the aliases file",
+	Long: `Report problems in the aliases file with their line numbers: lines that are
+not name=path, empty names or paths, dangling backslashes, duplicate names,
+and aliases that fail to resolve because of a cycle or an unset variable.
+Malformed lines are otherwise ignored, and kept as they are when the file is
+saved.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := loadAliasStore()
+		if err != nil {
+			return err
+		}
+
+		w := cmd.OutOrStdout()
+		problems := store.Problems()
+		if len(problems) == 0 {
+			_, err := fmt.Fprintf(w, "%s is valid\n", store.Path())
+			return err
+		}
+
+		for _, p := range problems {
+			if _, err := fmt.Fprintf(w, "%s:%d: %s\n", store.Path(), p.Line, p.Message); err != nil {
+				return err
+			}
+		}
+		return fmt.Errorf("%s has problems", store.Path())
+	},
+}
+
 // loadAliasStore creates and loads an alias store from the configured file path.
 func loadAliasStore() (*alias.Store, error) {
 	aliasFile, err := aliasFilePath()
@@ -106,5 +168,6 @@ func init() {
 	aliasCmd.AddCommand(aliasSetCmd)
 	aliasCmd.AddCommand(aliasRmCmd)
 	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasLintCmd)
 	rootCmd.AddCommand(aliasCmd)
 }
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/leeovery/portal/internal/alias"
 )
 
 func TestAliasSetCommand(t *testing.T) {
@@ -26,7 +28,7 @@ func TestAliasSetCommand(t *testing.T) {
 		}
 
 		got := string(data)
-		want := "myproject=/Users/lee/Code/project\n"
+		want := alias.Header + "\nmyproject=/Users/lee/Code/project\n"
 		if got != want {
 			t.Errorf("aliases file content = %q, want %q", got, want)
 		}
@@ -55,7 +57,7 @@ func TestAliasSetCommand(t *testing.T) {
 		}
 
 		got := string(data)
-		want := "m2api=" + filepath.Join(home, "Code/mac2/api") + "\n"
+		want := alias.Header + "\nm2api=" + filepath.Join(home, "Code/mac2/api") + "\n"
 		if got != want {
 			t.Errorf("aliases file content = %q, want %q", got, want)
 		}
@@ -84,7 +86,7 @@ func TestAliasSetCommand(t *testing.T) {
 		}
 
 		got := string(data)
-		want := "proj=" + filepath.Join(cwd, "relative/path") + "\n"
+		want := alias.Header + "\nproj=" + filepath.Join(cwd, "relative/path") + "\n"
 		if got != want {
 			t.Errorf("aliases file content = %q, want %q", got, want)
 		}
@@ -120,7 +122,7 @@ func TestAliasSetCommand(t *testing.T) {
 		}
 
 		got := string(data)
-		want := "proj=/second/path\n"
+		want := alias.Header + "\nproj=/second/path\n"
 		if got != want {
 			t.Errorf("aliases file content = %q, want %q", got, want)
 		}
@@ -149,7 +151,7 @@ func TestAliasSetCommand(t *testing.T) {
 		}
 
 		got := string(data)
-		want := "work=" + filepath.Join(home, "Code/work") + "\n"
+		want := alias.Header + "\nwork=" + filepath.Join(home, "Code/work") + "\n"
 		if got != want {
 			t.Errorf("aliases file content = %q, want %q", got, want)
 		}
@@ -185,6 +187,108 @@ func TestAliasSetCommand(t *testing.T) {
 	})
 }
 
+func TestAliasSetResolution(t *testing.T) {
+	run := func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+		stderr := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetErr(stderr)
+		rootCmd.SetArgs(append([]string{"alias", "set"}, args...))
+		err := rootCmd.Execute()
+		return stderr.String(), err
+	}
+	setup := func(t *testing.T, content string) string {
+		t.Helper()
+		aliasFile := filepath.Join(t.TempDir(), "aliases")
+		if err := os.WriteFile(aliasFile, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("PORTAL_ALIASES_FILE", aliasFile)
+		return aliasFile
+	}
+
+	t.Run("keeps alias references as given", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.Mkdir(filepath.Join(dir, "api"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		aliasFile := setup(t, "work="+dir+"\n")
+
+		stderr, err := run(t, "api", "work/api")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, _ := os.ReadFile(aliasFile)
+		if want := alias.Header + "\nwork=" + dir + "\napi=work/api\n"; string(data) != want {
+			t.Errorf("aliases file content = %q, want %q", data, want)
+		}
+		if stderr != "" {
+			t.Errorf("stderr = %q, want no warning", stderr)
+		}
+	})
+
+	t.Run("keeps environment variables as given", func(t *testing.T) {
+		t.Setenv("PORTAL_TEST_CODE", t.TempDir())
+		aliasFile := setup(t, "")
+
+		if _, err := run(t, "code", "$PORTAL_TEST_CODE"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, _ := os.ReadFile(aliasFile)
+		if string(data) != alias.Header+"\ncode=$PORTAL_TEST_CODE\n" {
+			t.Errorf("aliases file content = %q, want code=$PORTAL_TEST_CODE", data)
+		}
+	})
+
+	t.Run("warns when the alias does not resolve to a directory", func(t *testing.T) {
+		setup(t, "")
+		missing := filepath.Join(t.TempDir(), "missing")
+
+		stderr, err := run(t, "gone", missing)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := "Warning: alias gone resolves to " + missing + ", which is not an existing directory\n"
+		if stderr != want {
+			t.Errorf("stderr = %q, want %q", stderr, want)
+		}
+	})
+
+	t.Run("warns about an unset variable and saves", func(t *testing.T) {
+		aliasFile := setup(t, "")
+
+		stderr, err := run(t, "code", "$PORTAL_TEST_UNSET/code")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := "Warning: alias code uses unset variable $PORTAL_TEST_UNSET\n"; stderr != want {
+			t.Errorf("stderr = %q, want %q", stderr, want)
+		}
+		data, _ := os.ReadFile(aliasFile)
+		if string(data) != alias.Header+"\ncode=$PORTAL_TEST_UNSET/code\n" {
+			t.Errorf("aliases file content = %q, want code saved", data)
+		}
+	})
+
+	t.Run("rejects a cycle without saving", func(t *testing.T) {
+		aliasFile := setup(t, alias.Header+"\na=b/x\n")
+
+		_, err := run(t, "b", "a")
+		if err == nil || err.Error() != "alias cycle: b -> a -> b" {
+			t.Fatalf("err = %v, want alias cycle: b -> a -> b", err)
+		}
+
+		data, _ := os.ReadFile(aliasFile)
+		if string(data) != alias.Header+"\na=b/x\n" {
+			t.Errorf("aliases file content = %q, want it unchanged", data)
+		}
+	})
+}
+
 func TestAliasRmCommand(t *testing.T) {
 	t.Run("removes existing alias", func(t *testing.T) {
 		dir := t.TempDir()
@@ -209,7 +313,7 @@ func TestAliasRmCommand(t *testing.T) {
 		}
 
 		got := string(data)
-		want := "work=/Users/lee/Code/work\n"
+		want := alias.Header + "\nwork=/Users/lee/Code/work\n"
 		if got != want {
 			t.Errorf("aliases file content = %q, want %q", got, want)
 		}
@@ -346,3 +450,56 @@ func TestAliasListCommand(t *testing.T) {
 		}
 	})
 }
+
+func TestAliasLintCommand(t *testing.T) {
+	run := func(t *testing.T, content string) (string, string, error) {
+		t.Helper()
+		aliasFile := filepath.Join(t.TempDir(), "aliases")
+		if err := os.WriteFile(aliasFile, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("PORTAL_ALIASES_FILE", aliasFile)
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"alias", "lint"})
+		err := rootCmd.Execute()
+		return aliasFile, buf.String(), err
+	}
+
+	t.Run("reports malformed lines with line numbers", func(t *testing.T) {
+		aliasFile, out, err := run(t, alias.Header+"\n# comment\nwork=/code/work\noops\n")
+
+		if want := aliasFile + ":4: expected name=path\n"; out != want {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+		if err == nil || err.Error() != aliasFile+" has problems" {
+			t.Errorf("err = %v, want problems reported", err)
+		}
+	})
+
+	t.Run("reports aliases that fail to resolve", func(t *testing.T) {
+		aliasFile, out, err := run(t, alias.Header+"\na=b\nb=a\ncode=$PORTAL_TEST_UNSET/code\n")
+
+		want := aliasFile + ":2: alias cycle: a -> b -> a\n" +
+			aliasFile + ":3: alias cycle: b -> a -> b\n" +
+			aliasFile + ":4: alias code uses unset variable $PORTAL_TEST_UNSET\n"
+		if out != want {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("reports a valid file", func(t *testing.T) {
+		aliasFile, out, err := run(t, alias.Header+"\nwork=/code/work\n")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != aliasFile+" is valid\n" {
+			t.Errorf("output = %q, want valid", out)
+		}
+	})
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/leeovery/portal/internal/tmux"
 	"github.com/spf13/cobra"
 )
 
@@ -11,7 +10,7 @@ import (
 // When nil, real implementations are used.
 var attachDeps *AttachDeps
 
-// SessionValidator checks whether a tmux session exists by name.
+// SessionValidator checks whether a session exists by name.
 type SessionValidator interface {
 	HasSession(name string) bool
 }
@@ -24,7 +23,7 @@ type AttachDeps struct {
 
 var attachCmd = &cobra.Command{
 	Use:   "attach [name]",
-	Short: "Attach to a tmux session by name",
+	Short: "Attach to a session by name",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
@@ -41,15 +40,13 @@ var attachCmd = &cobra.Command{
 
 // buildAttachDeps returns the appropriate connector and validator for the attach command.
 // When attachDeps is set (testing), uses injected dependencies.
-// Otherwise, builds real implementations based on inside/outside tmux detection.
+// Otherwise, builds real implementations based on inside/outside detection.
 func buildAttachDeps() (SessionConnector, SessionValidator) {
 	if attachDeps != nil {
 		return attachDeps.Connector, attachDeps.Validator
 	}
 
-	client := tmux.NewClient(&tmux.RealCommander{})
-	connector := buildSessionConnector()
-	return connector, client
+	return buildSessionConnector(), backend()
 }
 
 func init() {
@@ -1,36 +1,396 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/registry"
+	"github.com/leeovery/portal/internal/tmux"
 	"github.com/spf13/cobra"
 )
 
+// cleanDeps holds injectable dependencies for the clean command.
+// When nil, real implementations are used.
+var cleanDeps *CleanDeps
+
+// Confirmer asks the user a yes/no question.
+type Confirmer interface {
+	Confirm(question string) (bool, error)
+}
+
+// CleanDeps allows injecting dependencies for testing.
+type CleanDeps struct {
+	Lister    SessionLister
+	Killer    SessionKiller
+	Confirmer Confirmer
+	// Current returns the name of the session Portal runs in, or "" outside it.
+	Current func() (string, error)
+}
+
+// cleanCategories are the kinds of stale state clean looks for, in the order
+// they are cleaned. Each has a flag of the same name.
+var cleanCategories = []struct {
+	flag  string
+	label string
+	usage string
+}{
+	{"projects", "stale projects", "Remove projects whose directories no longer exist"},
+	{"duplicates", "duplicate projects", "Remove projects resolving to the same real path as another"},
+	{"aliases", "stale aliases", "Remove aliases pointing to directories that no longer exist"},
+	{"sessions", "orphaned sessions", "Kill sessions whose start directory no longer exists"},
+	{"records", "session records", "Forget records of sessions that are no longer running"},
+}
+
+// cleanResult counts what clean found and cleaned in one category.
+type cleanResult struct {
+	label   string
+	found   int
+	cleaned int
+}
+
+// cleaner runs clean's categories, printing a line per item.
+type cleaner struct {
+	w, errW io.Writer
+	deps    *CleanDeps
+	dryRun  bool
+	yes     bool
+	results []cleanResult
+}
+
 var cleanCmd = &cobra.Command{
 	Use:   "clean",
-	Short: "Remove stale projects whose directories no longer exist",
-	Args:  cobra.NoArgs,
+	Short: "Remove stale projects, aliases and session records, and kill orphaned sessions",
+	Long: `Remove projects and aliases whose directories no longer exist, duplicate
+projects resolving to the same real path, and records of sessions that are no
+longer running, and kill sessions whose start directory no longer exists.
+
+Directories that cannot be read, for example because of permissions, are kept.
+Killing orphaned sessions asks first; --yes answers for you and is required
+when clean is not run interactively. The category flags restrict clean to the
+categories given; without any, every category is cleaned.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		store, err := loadProjectStore()
+		c := &cleaner{w: cmd.OutOrStdout(), errW: cmd.ErrOrStderr(), deps: buildCleanDeps(cmd)}
+		c.dryRun, _ = cmd.Flags().GetBool("dry-run")
+		c.yes, _ = cmd.Flags().GetBool("yes")
+
+		selected := make(map[string]bool)
+		for _, cat := range cleanCategories {
+			if on, _ := cmd.Flags().GetBool(cat.flag); on {
+				selected[cat.flag] = true
+			}
+		}
+		all := len(selected) == 0
+
+		sessions, err := c.deps.Lister.ListSessions()
 		if err != nil {
 			return err
 		}
+		live := make(map[string]bool, len(sessions))
+		for _, s := range sessions {
+			live[s.Name] = true
+		}
 
-		removed, err := store.CleanStale()
-		if err != nil {
+		for _, cat := range cleanCategories {
+			if !all && !selected[cat.flag] {
+				continue
+			}
+			var err error
+			switch cat.flag {
+			case "projects":
+				err = c.projects(cat.label)
+			case "duplicates":
+				err = c.duplicates(cat.label)
+			case "aliases":
+				err = c.aliases(cat.label)
+			case "sessions":
+				err = c.sessions(cat.label, sessions, live)
+			case "records":
+				err = c.records(cat.label, live)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		return c.summary()
+	},
+}
+
+// report prints one cleaned item, or the item that would be cleaned.
+func (c *cleaner) report(done, would, what, detail string) error {
+	verb := done
+	if c.dryRun {
+		verb = would
+	}
+	_, err := fmt.Fprintf(c.w, "%s %s: %s\n", verb, what, detail)
+	return err
+}
+
+func (c *cleaner) projects(label string) error {
+	store, err := loadProjectStore()
+	if err != nil {
+		return err
+	}
+
+	var stale []project.Project
+	if c.dryRun {
+		stale, err = store.Stale()
+	} else {
+		stale, err = store.CleanStale()
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, p := range stale {
+		if err := c.report("Removed", "Would remove", "stale project", fmt.Sprintf("%s (%s)", p.Name, p.Path)); err != nil {
 			return err
 		}
+	}
+	c.results = append(c.results, c.result(label, len(stale), len(stale)))
+	return nil
+}
+
+func (c *cleaner) duplicates(label string) error {
+	store, err := loadProjectStore()
+	if err != nil {
+		return err
+	}
+
+	duplicates, err := store.Duplicates()
+	if err != nil {
+		return err
+	}
 
-		w := cmd.OutOrStdout()
-		for _, p := range removed {
-			if _, err := fmt.Fprintf(w, "Removed stale project: %s (%s)\n", p.Name, p.Path); err != nil {
+	for _, d := range duplicates {
+		if !c.dryRun {
+			if err := store.Remove(d.Project.Path); err != nil {
 				return err
 			}
 		}
+		detail := fmt.Sprintf("%s (%s, same as %s)", d.Project.Name, d.Project.Path, d.Kept.Path)
+		if err := c.report("Removed", "Would remove", "duplicate project", detail); err != nil {
+			return err
+		}
+	}
+	c.results = append(c.results, c.result(label, len(duplicates), len(duplicates)))
+	return nil
+}
 
+func (c *cleaner) aliases(label string) error {
+	store, err := loadAliasStore()
+	if err != nil {
+		return err
+	}
+
+	stale := store.Stale()
+	if !c.dryRun && len(stale) > 0 {
+		for _, a := range stale {
+			store.Delete(a.Name)
+		}
+		if err := store.Save(); err != nil {
+			return err
+		}
+	}
+
+	for _, a := range stale {
+		if err := c.report("Removed", "Would remove", "stale alias", fmt.Sprintf("%s (%s)", a.Name, a.Path)); err != nil {
+			return err
+		}
+	}
+	c.results = append(c.results, c.result(label, len(stale), len(stale)))
+	return nil
+}
+
+// sessions kills the sessions whose start directory no longer exists, once
+// the user agrees. The session clean runs in is never killed. Killed sessions
+// are dropped from live so their records are forgotten too.
+func (c *cleaner) sessions(label string, sessions []tmux.Session, live map[string]bool) error {
+	current, _ := c.deps.Current()
+
+	var orphaned []tmux.Session
+	for _, s := range sessions {
+		if s.Path != "" && s.Name != current && project.Missing(s.Path) {
+			orphaned = append(orphaned, s)
+		}
+	}
+
+	if c.dryRun || len(orphaned) == 0 {
+		for _, s := range orphaned {
+			if err := c.report("Killed", "Would kill", "orphaned session", fmt.Sprintf("%s (%s)", s.Name, s.Path)); err != nil {
+				return err
+			}
+		}
+		c.results = append(c.results, c.result(label, len(orphaned), 0))
 		return nil
-	},
+	}
+
+	if !c.yes {
+		question := fmt.Sprintf("Kill %d orphaned sessions whose directories no longer exist?", len(orphaned))
+		ok, err := c.deps.Confirmer.Confirm(question)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			_, _ = fmt.Fprintf(c.errW, "Skipped %d orphaned sessions; pass --yes to kill them\n", len(orphaned))
+			c.results = append(c.results, c.result(label, len(orphaned), 0))
+			return nil
+		}
+	}
+
+	killed := 0
+	for _, s := range orphaned {
+		if err := c.deps.Killer.KillSession(s.Name); err != nil {
+			_, _ = fmt.Fprintf(c.errW, "Error: %v\n", err)
+			continue
+		}
+		killed++
+		delete(live, s.Name)
+		if err := c.report("Killed", "Would kill", "orphaned session", fmt.Sprintf("%s (%s)", s.Name, s.Path)); err != nil {
+			return err
+		}
+	}
+	c.results = append(c.results, c.result(label, len(orphaned), killed))
+	return nil
+}
+
+func (c *cleaner) records(label string, live map[string]bool) error {
+	reg, err := loadSessionRegistry()
+	if err != nil {
+		return err
+	}
+
+	var stale []registry.Entry
+	if c.dryRun {
+		entries, err := reg.Load()
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if !live[e.Name] {
+				stale = append(stale, e)
+			}
+		}
+	} else {
+		names := make([]string, 0, len(live))
+		for name := range live {
+			names = append(names, name)
+		}
+		if stale, err = reg.Prune(names); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range stale {
+		if err := c.report("Removed", "Would remove", "stale session record", fmt.Sprintf("%s (%s)", e.Name, e.ProjectPath)); err != nil {
+			return err
+		}
+	}
+	c.results = append(c.results, c.result(label, len(stale), len(stale)))
+	return nil
+}
+
+// result records a category's counts; nothing is cleaned in a dry run.
+func (c *cleaner) result(label string, found, cleaned int) cleanResult {
+	if c.dryRun {
+		cleaned = 0
+	}
+	return cleanResult{label: label, found: found, cleaned: cleaned}
+}
+
+// summary prints a table of what each category found and cleaned, after a
+// blank line. Nothing is printed when nothing was found.
+func (c *cleaner) summary() error {
+	found := 0
+	for _, r := range c.results {
+		found += r.found
+	}
+	if found == 0 {
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(c.w, 0, 0, 2, ' ', 0)
+	header := "\nCATEGORY\tFOUND\tCLEANED\n"
+	if c.dryRun {
+		header = "\nCATEGORY\tFOUND\n"
+	}
+	if _, err := fmt.Fprint(tw, header); err != nil {
+		return err
+	}
+	for _, r := range c.results {
+		var err error
+		if c.dryRun {
+			_, err = fmt.Fprintf(tw, "%s\t%d\n", r.label, r.found)
+		} else {
+			_, err = fmt.Fprintf(tw, "%s\t%d\t%d\n", r.label, r.found, r.cleaned)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// buildCleanDeps returns the dependencies for the clean command.
+// Clean does not require a multiplexer: without a server, no sessions are live.
+func buildCleanDeps(cmd *cobra.Command) *CleanDeps {
+	if cleanDeps != nil {
+		deps := *cleanDeps
+		if deps.Current == nil {
+			deps.Current = func() (string, error) { return "", nil }
+		}
+		return &deps
+	}
+
+	client := backend()
+	deps := &CleanDeps{
+		Lister:    client,
+		Killer:    client,
+		Confirmer: &promptConfirmer{in: cmd.InOrStdin(), out: cmd.ErrOrStderr()},
+		Current: func() (string, error) {
+			if !client.Inside() {
+				return "", nil
+			}
+			return client.CurrentSessionName()
+		},
+	}
+	if reg, err := loadSessionRegistry(); err == nil {
+		deps.Killer = registry.NewTracker(client, reg)
+	}
+	return deps
+}
+
+// promptConfirmer asks on out and reads the answer from in. When in is not a
+// terminal nobody can answer, so it declines without asking.
+type promptConfirmer struct {
+	in  io.Reader
+	out io.Writer
+}
+
+// Confirm reports whether the user answered y or yes.
+func (p *promptConfirmer) Confirm(question string) (bool, error) {
+	if f, ok := p.in.(*os.File); ok {
+		stat, err := f.Stat()
+		if err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+			return false, nil
+		}
+	}
+
+	if _, err := fmt.Fprintf(p.out, "%s [y/N] ", question); err != nil {
+		return false, err
+	}
+	answer, err := bufio.NewReader(p.in).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, nil
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
 }
 
 // loadProjectStore creates a project store from the configured file path.
@@ -51,6 +411,27 @@ func projectsFilePath() (string, error) {
 	return configFilePath("PORTAL_PROJECTS_FILE", "projects.json")
 }
 
+// loadSessionRegistry creates a session registry store from the configured file path.
+func loadSessionRegistry() (*registry.Store, error) {
+	path, err := sessionsFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return registry.NewStore(path), nil
+}
+
+// sessionsFilePath returns the path to the sessions.json registry file.
+// Uses PORTAL_SESSIONS_FILE env var if set (for testing), otherwise
+// defaults to ~/.config/portal/sessions.json.
+func sessionsFilePath() (string, error) {
+	return configFilePath("PORTAL_SESSIONS_FILE", "sessions.json")
+}
+
 func init() {
+	cleanCmd.Flags().BoolP("dry-run", "n", false, "Print what would be cleaned without changing anything")
+	cleanCmd.Flags().BoolP("yes", "y", false, "Kill orphaned sessions without asking")
+	for _, cat := range cleanCategories {
+		cleanCmd.Flags().Bool(cat.flag, false, cat.usage)
+	}
 	rootCmd.AddCommand(cleanCmd)
 }
@@ -2,16 +2,45 @@ package cmd
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/leeovery/portal/internal/alias"
+	"github.com/leeovery/portal/internal/registry"
+	"github.com/leeovery/portal/internal/tmux"
 )
 
+// isolateClean points clean at an empty aliases file in dir and a server
+// with no sessions, so tests never touch the real ones.
+func isolateClean(t *testing.T, dir string) {
+	t.Helper()
+	t.Setenv("PORTAL_ALIASES_FILE", filepath.Join(dir, "aliases"))
+	cleanDeps = &CleanDeps{Lister: &mockSessionLister{}}
+	t.Cleanup(func() { cleanDeps = nil })
+}
+
+// cleanSummary is the table clean prints after cleaning the given number of
+// stale projects, duplicate projects, stale aliases, orphaned sessions and
+// session records.
+func cleanSummary(projects, duplicates, aliases, sessions, records int) string {
+	return fmt.Sprintf("\nCATEGORY            FOUND  CLEANED\n"+
+		"stale projects      %[1]d      %[1]d\n"+
+		"duplicate projects  %[2]d      %[2]d\n"+
+		"stale aliases       %[3]d      %[3]d\n"+
+		"orphaned sessions   %[4]d      %[4]d\n"+
+		"session records     %[5]d      %[5]d\n", projects, duplicates, aliases, sessions, records)
+}
+
 func TestCleanCommand(t *testing.T) {
 	t.Run("removes stale project and prints removal message", func(t *testing.T) {
 		dir := t.TempDir()
 		projectsFile := filepath.Join(dir, "projects.json")
 		t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+		t.Setenv("PORTAL_SESSIONS_FILE", filepath.Join(dir, "sessions.json"))
+		isolateClean(t, dir)
 
 		stalePath := filepath.Join(dir, "gone")
 		content := `{"projects":[{"path":"` + stalePath + `","name":"stale","last_used":"2026-01-01T00:00:00Z"}]}`
@@ -30,7 +59,7 @@ func TestCleanCommand(t *testing.T) {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		want := "Removed stale project: stale (" + stalePath + ")\n"
+		want := "Removed stale project: stale (" + stalePath + ")\n" + cleanSummary(1, 0, 0, 0, 0)
 		if buf.String() != want {
 			t.Errorf("output = %q, want %q", buf.String(), want)
 		}
@@ -49,6 +78,8 @@ func TestCleanCommand(t *testing.T) {
 		dir := t.TempDir()
 		projectsFile := filepath.Join(dir, "projects.json")
 		t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+		t.Setenv("PORTAL_SESSIONS_FILE", filepath.Join(dir, "sessions.json"))
+		isolateClean(t, dir)
 
 		existingDir := t.TempDir()
 		content := `{"projects":[{"path":"` + existingDir + `","name":"exists","last_used":"2026-01-01T00:00:00Z"}]}`
@@ -85,6 +116,8 @@ func TestCleanCommand(t *testing.T) {
 		dir := t.TempDir()
 		projectsFile := filepath.Join(dir, "projects.json")
 		t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+		t.Setenv("PORTAL_SESSIONS_FILE", filepath.Join(dir, "sessions.json"))
+		isolateClean(t, dir)
 
 		// Create a parent dir, then a child inside it, then remove perms on parent
 		parentDir := filepath.Join(dir, "restricted")
@@ -134,6 +167,8 @@ func TestCleanCommand(t *testing.T) {
 		dir := t.TempDir()
 		projectsFile := filepath.Join(dir, "projects.json")
 		t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+		t.Setenv("PORTAL_SESSIONS_FILE", filepath.Join(dir, "sessions.json"))
+		isolateClean(t, dir)
 
 		existingDir1 := t.TempDir()
 		existingDir2 := t.TempDir()
@@ -165,6 +200,8 @@ func TestCleanCommand(t *testing.T) {
 		dir := t.TempDir()
 		projectsFile := filepath.Join(dir, "projects.json")
 		t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+		t.Setenv("PORTAL_SESSIONS_FILE", filepath.Join(dir, "sessions.json"))
+		isolateClean(t, dir)
 
 		stalePath1 := filepath.Join(dir, "gone1")
 		stalePath2 := filepath.Join(dir, "gone2")
@@ -187,7 +224,7 @@ func TestCleanCommand(t *testing.T) {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		want := "Removed stale project: stale1 (" + stalePath1 + ")\nRemoved stale project: stale2 (" + stalePath2 + ")\n"
+		want := "Removed stale project: stale1 (" + stalePath1 + ")\nRemoved stale project: stale2 (" + stalePath2 + ")\n" + cleanSummary(2, 0, 0, 0, 0)
 		if buf.String() != want {
 			t.Errorf("output = %q, want %q", buf.String(), want)
 		}
@@ -209,6 +246,8 @@ func TestCleanCommand(t *testing.T) {
 		dir := t.TempDir()
 		projectsFile := filepath.Join(dir, "projects.json")
 		t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+		t.Setenv("PORTAL_SESSIONS_FILE", filepath.Join(dir, "sessions.json"))
+		isolateClean(t, dir)
 
 		existingDir := t.TempDir()
 		stalePath1 := filepath.Join(dir, "gone1")
@@ -233,7 +272,7 @@ func TestCleanCommand(t *testing.T) {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		want := "Removed stale project: stale1 (" + stalePath1 + ")\nRemoved stale project: stale2 (" + stalePath2 + ")\n"
+		want := "Removed stale project: stale1 (" + stalePath1 + ")\nRemoved stale project: stale2 (" + stalePath2 + ")\n" + cleanSummary(2, 0, 0, 0, 0)
 		if buf.String() != want {
 			t.Errorf("output = %q, want %q", buf.String(), want)
 		}
@@ -259,6 +298,8 @@ func TestCleanCommand(t *testing.T) {
 		dir := t.TempDir()
 		projectsFile := filepath.Join(dir, "projects.json")
 		t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+		t.Setenv("PORTAL_SESSIONS_FILE", filepath.Join(dir, "sessions.json"))
+		isolateClean(t, dir)
 
 		buf := new(bytes.Buffer)
 		resetRootCmd()
@@ -272,3 +313,274 @@ func TestCleanCommand(t *testing.T) {
 		}
 	})
 }
+
+func TestCleanPrunesSessionRegistry(t *testing.T) {
+	t.Run("removes records of sessions that are no longer running", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("PORTAL_PROJECTS_FILE", filepath.Join(dir, "projects.json"))
+		sessionsFile := filepath.Join(dir, "sessions.json")
+		t.Setenv("PORTAL_SESSIONS_FILE", sessionsFile)
+		t.Setenv("PORTAL_ALIASES_FILE", filepath.Join(dir, "aliases"))
+
+		store := registry.NewStore(sessionsFile)
+		_ = store.Record("live", "/code/live", nil)
+		_ = store.Record("gone", "/code/gone", nil)
+
+		cleanDeps = &CleanDeps{Lister: &mockSessionLister{
+			sessions: []tmux.Session{{Name: "live", Windows: 1}},
+		}}
+		t.Cleanup(func() { cleanDeps = nil })
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"clean"})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := "Removed stale session record: gone (/code/gone)\n" + cleanSummary(0, 0, 0, 0, 1)
+		if buf.String() != want {
+			t.Errorf("output = %q, want %q", buf.String(), want)
+		}
+
+		byName, _ := store.ByName()
+		if _, ok := byName["live"]; !ok {
+			t.Error("live session record should be retained")
+		}
+		if _, ok := byName["gone"]; ok {
+			t.Error("gone session record should have been removed")
+		}
+	})
+}
+
+type mockConfirmer struct {
+	answer bool
+	asked  []string
+}
+
+func (m *mockConfirmer) Confirm(question string) (bool, error) {
+	m.asked = append(m.asked, question)
+	return m.answer, nil
+}
+
+func TestCleanExtended(t *testing.T) {
+	// setup writes a projects file with a stale project and a symlinked
+	// duplicate, an aliases file with one stale alias, and a server with one
+	// orphaned session.
+	setup := func(t *testing.T) (dir string, killer *mockSessionKiller, confirmer *mockConfirmer) {
+		t.Helper()
+		dir = t.TempDir()
+		projectsFile := filepath.Join(dir, "projects.json")
+		aliasesFile := filepath.Join(dir, "aliases")
+		t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+		t.Setenv("PORTAL_SESSIONS_FILE", filepath.Join(dir, "sessions.json"))
+		t.Setenv("PORTAL_ALIASES_FILE", aliasesFile)
+
+		apiDir := filepath.Join(dir, "api")
+		if err := os.Mkdir(apiDir, 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.Symlink(apiDir, filepath.Join(dir, "api-link")); err != nil {
+			t.Fatalf("failed to create symlink: %v", err)
+		}
+		content := `{"projects":[
+			{"path":"` + apiDir + `","name":"api","last_used":"2026-02-01T00:00:00Z"},
+			{"path":"` + filepath.Join(dir, "api-link") + `","name":"api-link","last_used":"2026-01-01T00:00:00Z"},
+			{"path":"` + filepath.Join(dir, "gone") + `","name":"gone","last_used":"2026-01-01T00:00:00Z"}
+		]}`
+		if err := os.WriteFile(projectsFile, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+		aliases := "api=" + apiDir + "\nold=" + filepath.Join(dir, "old") + "\n"
+		if err := os.WriteFile(aliasesFile, []byte(aliases), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+
+		killer = &mockSessionKiller{}
+		confirmer = &mockConfirmer{}
+		cleanDeps = &CleanDeps{
+			Lister: &mockSessionLister{sessions: []tmux.Session{
+				{Name: "api-x1", Path: apiDir},
+				{Name: "old-x2", Path: filepath.Join(dir, "old")},
+			}},
+			Killer:    killer,
+			Confirmer: confirmer,
+		}
+		t.Cleanup(func() { cleanDeps = nil })
+		return dir, killer, confirmer
+	}
+
+	run := func(t *testing.T, args ...string) (string, string) {
+		t.Helper()
+		out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(out)
+		rootCmd.SetErr(errOut)
+		rootCmd.SetArgs(append([]string{"clean"}, args...))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out.String(), errOut.String()
+	}
+
+	t.Run("cleans every category with --yes", func(t *testing.T) {
+		dir, killer, confirmer := setup(t)
+
+		out, _ := run(t, "--yes")
+
+		want := "Removed stale project: gone (" + filepath.Join(dir, "gone") + ")\n" +
+			"Removed duplicate project: api-link (" + filepath.Join(dir, "api-link") + ", same as " + filepath.Join(dir, "api") + ")\n" +
+			"Removed stale alias: old (" + filepath.Join(dir, "old") + ")\n" +
+			"Killed orphaned session: old-x2 (" + filepath.Join(dir, "old") + ")\n" +
+			cleanSummary(1, 1, 1, 1, 0)
+		if out != want {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+		if len(killer.killed) != 1 || killer.killed[0] != "old-x2" {
+			t.Errorf("killed %v, want [old-x2]", killer.killed)
+		}
+		if len(confirmer.asked) != 0 {
+			t.Errorf("asked %v, want no questions with --yes", confirmer.asked)
+		}
+
+		data, _ := os.ReadFile(filepath.Join(dir, "projects.json"))
+		if bytes.Contains(data, []byte("api-link")) || bytes.Contains(data, []byte(`"gone"`)) {
+			t.Errorf("stale and duplicate projects should have been removed:\n%s", data)
+		}
+		data, _ = os.ReadFile(filepath.Join(dir, "aliases"))
+		if string(data) != alias.Header+"\napi="+filepath.Join(dir, "api")+"\n" {
+			t.Errorf("aliases = %q, want only api", data)
+		}
+	})
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		dir, killer, _ := setup(t)
+		before, _ := os.ReadFile(filepath.Join(dir, "projects.json"))
+
+		out, _ := run(t, "--dry-run")
+
+		for _, line := range []string{
+			"Would remove stale project: gone",
+			"Would remove duplicate project: api-link",
+			"Would remove stale alias: old",
+			"Would kill orphaned session: old-x2",
+			"\nCATEGORY            FOUND\nstale projects      1\n",
+		} {
+			if !strings.Contains(out, line) {
+				t.Errorf("output missing %q:\n%s", line, out)
+			}
+		}
+		if len(killer.killed) != 0 {
+			t.Errorf("killed %v, want none", killer.killed)
+		}
+		after, _ := os.ReadFile(filepath.Join(dir, "projects.json"))
+		if !bytes.Equal(before, after) {
+			t.Error("projects file should be unchanged")
+		}
+	})
+
+	t.Run("declining keeps orphaned sessions", func(t *testing.T) {
+		_, killer, confirmer := setup(t)
+
+		out, errOut := run(t, "--sessions")
+
+		if len(confirmer.asked) != 1 {
+			t.Fatalf("asked %v, want one question", confirmer.asked)
+		}
+		if len(killer.killed) != 0 {
+			t.Errorf("killed %v, want none", killer.killed)
+		}
+		if errOut != "Skipped 1 orphaned sessions; pass --yes to kill them\n" {
+			t.Errorf("stderr = %q", errOut)
+		}
+		if want := "\nCATEGORY           FOUND  CLEANED\norphaned sessions  1      0\n"; out != want {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+	})
+
+	t.Run("category flags restrict what is cleaned", func(t *testing.T) {
+		dir, killer, _ := setup(t)
+
+		out, _ := run(t, "--aliases")
+
+		want := "Removed stale alias: old (" + filepath.Join(dir, "old") + ")\n" +
+			"\nCATEGORY       FOUND  CLEANED\nstale aliases  1      1\n"
+		if out != want {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+		if len(killer.killed) != 0 {
+			t.Errorf("killed %v, want none", killer.killed)
+		}
+		data, _ := os.ReadFile(filepath.Join(dir, "projects.json"))
+		if !bytes.Contains(data, []byte(`"gone"`)) {
+			t.Error("projects should be untouched")
+		}
+	})
+}
+
+func TestCleanResolvesAliases(t *testing.T) {
+	// setup writes an aliases file whose only stale alias is web, reached
+	// through the chained alias work; api, code and the unset variable alias
+	// must be kept.
+	setup := func(t *testing.T) (dir, aliasesFile string) {
+		t.Helper()
+		dir = t.TempDir()
+		if err := os.Mkdir(filepath.Join(dir, "api"), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		isolateClean(t, dir)
+		aliasesFile = filepath.Join(dir, "aliases")
+		t.Setenv("PORTAL_TEST_CODE", dir)
+		content := alias.Header + "\nwork=" + dir + "\napi=work/api\ncode=$PORTAL_TEST_CODE/api\nweb=work/web\nunset=$PORTAL_TEST_UNSET/x\n"
+		if err := os.WriteFile(aliasesFile, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+		return dir, aliasesFile
+	}
+
+	run := func(t *testing.T, args ...string) string {
+		t.Helper()
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs(append([]string{"clean", "--aliases"}, args...))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return buf.String()
+	}
+
+	t.Run("removes only aliases whose resolved path is gone", func(t *testing.T) {
+		dir, aliasesFile := setup(t)
+
+		out := run(t)
+
+		want := "Removed stale alias: web (work/web)\n" +
+			"\nCATEGORY       FOUND  CLEANED\nstale aliases  1      1\n"
+		if out != want {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+		data, _ := os.ReadFile(aliasesFile)
+		want = alias.Header + "\nwork=" + dir + "\napi=work/api\ncode=$PORTAL_TEST_CODE/api\nunset=$PORTAL_TEST_UNSET/x\n"
+		if string(data) != want {
+			t.Errorf("aliases = %q, want %q", data, want)
+		}
+	})
+
+	t.Run("dry run reports only aliases whose resolved path is gone", func(t *testing.T) {
+		_, aliasesFile := setup(t)
+		before, _ := os.ReadFile(aliasesFile)
+
+		out := run(t, "--dry-run")
+
+		if !strings.HasPrefix(out, "Would remove stale alias: web (work/web)\n\n") {
+			t.Errorf("output = %q, want only web reported", out)
+		}
+		after, _ := os.ReadFile(aliasesFile)
+		if !bytes.Equal(before, after) {
+			t.Error("aliases file should be unchanged")
+		}
+	})
+}
@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+
+	"github.com/leeovery/portal/internal/config"
+	"github.com/spf13/cobra"
 )
 
 // configFilePath returns a config file path by checking the given environment
@@ -21,3 +26,198 @@ func configFilePath(envVar, filename string) (string, error) {
 
 	return filepath.Join(configDir, "portal", filename), nil
 }
+
+// settingsFilePath returns the path to the central config file.
+// Uses PORTAL_CONFIG_FILE env var if set, otherwise
+// defaults to ~/.config/portal/config.json.
+func settingsFilePath() (string, error) {
+	return configFilePath("PORTAL_CONFIG_FILE", "config.json")
+}
+
+// loadConfig loads the central config file, printing any warnings about
+// unknown keys to cmd's error stream.
+func loadConfig(cmd *cobra.Command) (config.Config, error) {
+	path, err := settingsFilePath()
+	if err != nil {
+		return config.Config{}, err
+	}
+
+	cfg, warnings, err := config.Load(path)
+	printConfigWarnings(cmd, warnings)
+	if err != nil {
+		return config.Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// printConfigWarnings writes each config warning to cmd's error stream.
+func printConfigWarnings(cmd *cobra.Command, warnings []string) {
+	for _, w := range warnings {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: config: %s\n", w)
+	}
+}
+
+// configDeps holds injectable dependencies for the config command.
+// When nil, real implementations are used.
+var configDeps *ConfigDeps
+
+// ConfigDeps allows injecting dependencies for testing.
+type ConfigDeps struct {
+	Editor EditorRunner
+}
+
+// EditorRunner opens a file in the user's editor and waits for it to exit.
+type EditorRunner interface {
+	Edit(path string) error
+}
+
+// envEditor runs $VISUAL or $EDITOR, falling back to vi.
+type envEditor struct{}
+
+// Edit opens path in the user's editor attached to the current terminal.
+func (e *envEditor) Edit(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	c := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
+
+// buildEditorRunner returns the editor used by config edit.
+func buildEditorRunner() EditorRunner {
+	if configDeps != nil {
+		return configDeps.Editor
+	}
+	return &envEditor{}
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and edit the Portal config file",
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration, including defaults",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		data, err := cfg.Marshal()
+		if err != nil {
+			return err
+		}
+
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the config file path",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := settingsFilePath()
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), path)
+		return err
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in $EDITOR, creating it with defaults if missing",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := settingsFilePath()
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			if err := writeDefaultConfig(path); err != nil {
+				return err
+			}
+		}
+
+		if err := buildEditorRunner().Edit(path); err != nil {
+			return err
+		}
+
+		_, warnings, err := config.Load(path)
+		printConfigWarnings(cmd, warnings)
+		return err
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config file for errors",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := settingsFilePath()
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			_, err := fmt.Fprintf(cmd.OutOrStdout(), "No config file at %s; using defaults\n", path)
+			return err
+		}
+
+		_, warnings, err := config.Load(path)
+		printConfigWarnings(cmd, warnings)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s is valid\n", path)
+		return err
+	},
+}
+
+// writeDefaultConfig writes the default configuration to path,
+// creating the parent directory if needed.
+func writeDefaultConfig(path string) error {
+	data, err := config.Default().Marshal()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+}
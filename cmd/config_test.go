@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/leeovery/portal/internal/config"
 )
 
 func TestConfigFilePath(t *testing.T) {
@@ -40,3 +44,173 @@ func TestConfigFilePath(t *testing.T) {
 		}
 	})
 }
+
+// recordingEditor implements EditorRunner by recording the path and
+// optionally rewriting the file, standing in for an interactive editor.
+type recordingEditor struct {
+	path    string
+	content string
+}
+
+func (e *recordingEditor) Edit(path string) error {
+	e.path = path
+	if e.content != "" {
+		return os.WriteFile(path, []byte(e.content), 0o644)
+	}
+	return nil
+}
+
+func runConfigCmd(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	resetRootCmd()
+	rootCmd.SetOut(stdout)
+	rootCmd.SetErr(stderr)
+	rootCmd.SetArgs(append([]string{"config"}, args...))
+	err := rootCmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestConfigCommand(t *testing.T) {
+	t.Run("path prints the config file path", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		t.Setenv("PORTAL_CONFIG_FILE", path)
+
+		out, _, err := runConfigCmd(t, "path")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != path+"\n" {
+			t.Errorf("output = %q, want %q", out, path+"\n")
+		}
+	})
+
+	t.Run("show prints defaults when no file exists", func(t *testing.T) {
+		t.Setenv("PORTAL_CONFIG_FILE", filepath.Join(t.TempDir(), "config.json"))
+
+		out, _, err := runConfigCmd(t, "show")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want, _ := config.Default().Marshal()
+		if out != string(want) {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+	})
+
+	t.Run("show merges file settings and warns about unknown keys", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		t.Setenv("PORTAL_CONFIG_FILE", path)
+		if err := os.WriteFile(path, []byte(`{"browser": {"show_hidden": true}, "extra": 1}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		out, errOut, err := runConfigCmd(t, "show")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(out, `"show_hidden": true`) {
+			t.Errorf("output missing file setting:\n%s", out)
+		}
+		if !strings.Contains(out, `"name_format": "{project}-{id}"`) {
+			t.Errorf("output missing default setting:\n%s", out)
+		}
+		if errOut != "warning: config: unknown key \"extra\" (ignored)\n" {
+			t.Errorf("stderr = %q", errOut)
+		}
+	})
+
+	t.Run("validate reports missing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		t.Setenv("PORTAL_CONFIG_FILE", path)
+
+		out, _, err := runConfigCmd(t, "validate")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != "No config file at "+path+"; using defaults\n" {
+			t.Errorf("output = %q", out)
+		}
+	})
+
+	t.Run("validate accepts a valid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		t.Setenv("PORTAL_CONFIG_FILE", path)
+		if err := os.WriteFile(path, []byte(`{"git": {"resolve_root": false}}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		out, _, err := runConfigCmd(t, "validate")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != path+" is valid\n" {
+			t.Errorf("output = %q", out)
+		}
+	})
+
+	t.Run("validate fails on invalid settings", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		t.Setenv("PORTAL_CONFIG_FILE", path)
+		if err := os.WriteFile(path, []byte(`{"tui": {"colors": {"cursor": "pink"}}}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, _, err := runConfigCmd(t, "validate")
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "tui.colors.cursor") {
+			t.Errorf("error = %q, want it to name the setting", err)
+		}
+	})
+
+	t.Run("edit creates file with defaults and opens editor", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "portal", "config.json")
+		t.Setenv("PORTAL_CONFIG_FILE", path)
+		editor := &recordingEditor{}
+		configDeps = &ConfigDeps{Editor: editor}
+		t.Cleanup(func() { configDeps = nil })
+
+		if _, _, err := runConfigCmd(t, "edit"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if editor.path != path {
+			t.Errorf("editor opened %q, want %q", editor.path, path)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("config file not created: %v", err)
+		}
+		want, _ := config.Default().Marshal()
+		if string(data) != string(want) {
+			t.Errorf("created file = %q, want defaults %q", data, want)
+		}
+	})
+
+	t.Run("edit reports errors in the saved file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		t.Setenv("PORTAL_CONFIG_FILE", path)
+		configDeps = &ConfigDeps{Editor: &recordingEditor{content: `{"session": {"name_format": "{project}"}}`}}
+		t.Cleanup(func() { configDeps = nil })
+
+		_, _, err := runConfigCmd(t, "edit")
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "session.name_format") {
+			t.Errorf("error = %q", err)
+		}
+	})
+
+	t.Run("config works without tmux", func(t *testing.T) {
+		t.Setenv("PATH", "/nonexistent/path")
+		t.Setenv("PORTAL_CONFIG_FILE", filepath.Join(t.TempDir(), "config.json"))
+
+		if _, _, err := runConfigCmd(t, "path"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -1,9 +1,19 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/registry"
+	"github.com/leeovery/portal/internal/resolver"
 	"github.com/leeovery/portal/internal/tmux"
+	"github.com/leeovery/portal/internal/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -16,41 +26,373 @@ type SessionKiller interface {
 	KillSession(name string) error
 }
 
+// SessionPicker lets the user choose sessions interactively.
+// An empty result means the user cancelled.
+type SessionPicker interface {
+	Pick(sessions []tmux.Session) ([]string, error)
+}
+
 // KillDeps allows injecting dependencies for testing.
 type KillDeps struct {
 	Killer    SessionKiller
 	Validator SessionValidator
+	Lister    SessionLister
+	Registry  SessionRegistry
+	Picker    SessionPicker
+	// Current returns the name of the session Portal runs in, or "" outside tmux.
+	Current func() (string, error)
+}
+
+// killOptions holds the parsed kill flags.
+type killOptions struct {
+	all           bool
+	detached      bool
+	project       string
+	exceptCurrent bool
+	regex         bool
+	dryRun        bool
+}
+
+// selects reports whether the options select sessions without any names.
+func (o killOptions) selects() bool {
+	return o.all || o.detached || o.project != ""
+}
+
+// filters reports whether any option narrows the set of sessions to kill.
+func (o killOptions) filters() bool {
+	return o.selects() || o.exceptCurrent || o.regex
 }
 
 var killCmd = &cobra.Command{
-	Use:   "kill [name]",
-	Short: "Kill a tmux session",
-	Args:  cobra.ExactArgs(1),
+	Use:   "kill [name|pattern...]",
+	Short: "Kill sessions",
+	Long: `Kill tmux sessions by name, glob pattern or regular expression.
+
+Names containing *, ? or [ are glob patterns matched against the whole
+session name; with --regex every argument is a regular expression instead.
+--all, --detached and --project select sessions without naming them, and
+--except-current spares the session Portal runs in. With no names or
+selection flags, an interactive picker chooses the sessions to kill.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		name := args[0]
+		opts := killOptions{}
+		opts.all, _ = cmd.Flags().GetBool("all")
+		opts.detached, _ = cmd.Flags().GetBool("detached")
+		opts.project, _ = cmd.Flags().GetString("project")
+		opts.exceptCurrent, _ = cmd.Flags().GetBool("except-current")
+		opts.regex, _ = cmd.Flags().GetBool("regex")
+		opts.dryRun, _ = cmd.Flags().GetBool("dry-run")
 
-		killer, validator := buildKillDeps()
+		if opts.all && len(args) > 0 {
+			return NewUsageError("--all cannot be combined with session names")
+		}
+		if opts.regex && len(args) == 0 {
+			return NewUsageError("--regex requires at least one pattern")
+		}
 
-		if !validator.HasSession(name) {
-			return fmt.Errorf("No session found: %s", name) //nolint:staticcheck // user-facing message per spec
+		deps, err := buildKillDeps()
+		if err != nil {
+			return err
 		}
 
-		return killer.KillSession(name)
+		var targets []string
+		if len(args) > 0 && !opts.filters() && !hasGlob(args) {
+			targets, err = exactKillTargets(args, deps.Validator)
+		} else {
+			targets, err = selectKillTargets(cmd, args, opts, deps)
+		}
+		if err != nil || len(targets) == 0 {
+			return err
+		}
+
+		return killSessions(cmd.OutOrStdout(), cmd.ErrOrStderr(), deps, targets, opts.dryRun)
 	},
 }
 
-// buildKillDeps returns the appropriate killer and validator for the kill command.
+// hasGlob reports whether any argument is a glob pattern.
+func hasGlob(args []string) bool {
+	for _, a := range args {
+		if strings.ContainsAny(a, "*?[") {
+			return true
+		}
+	}
+	return false
+}
+
+// exactKillTargets checks that every named session exists.
+func exactKillTargets(names []string, validator SessionValidator) ([]string, error) {
+	for _, name := range names {
+		if !validator.HasSession(name) {
+			return nil, fmt.Errorf("No session found: %s", name) //nolint:staticcheck // user-facing message per spec
+		}
+	}
+	return names, nil
+}
+
+// selectKillTargets lists running sessions and narrows them down by args and
+// opts, falling back to the interactive picker when nothing selects sessions.
+func selectKillTargets(cmd *cobra.Command, args []string, opts killOptions, deps *KillDeps) ([]string, error) {
+	match, err := killMatcher(args, opts.regex)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := deps.Lister.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	var projectPath string
+	var entries map[string]registry.Entry
+	if opts.project != "" {
+		projectPath, err = resolveProjectFlag(cmd, opts.project)
+		if err != nil {
+			return nil, err
+		}
+		entries, err = deps.Registry.ByName()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var current string
+	if opts.exceptCurrent {
+		current, err = deps.Current()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var candidates []tmux.Session
+	for _, s := range sessions {
+		switch {
+		case match != nil && !match(s.Name):
+		case opts.detached && s.Attached:
+		case opts.project != "" && entries[s.Name].ProjectPath != projectPath:
+		case current != "" && s.Name == current:
+		default:
+			candidates = append(candidates, s)
+		}
+	}
+
+	if len(args) == 0 && !opts.selects() {
+		if len(candidates) == 0 {
+			return nil, errors.New("No sessions to kill") //nolint:staticcheck // user-facing message
+		}
+		return deps.Picker.Pick(candidates)
+	}
+
+	if len(candidates) == 0 {
+		return nil, errors.New("No sessions match") //nolint:staticcheck // user-facing message
+	}
+
+	names := make([]string, len(candidates))
+	for i, s := range candidates {
+		names[i] = s.Name
+	}
+	return names, nil
+}
+
+// killMatcher compiles args into a predicate on session names. Plain names
+// match exactly, names with *, ? or [ are globs, and with regex every arg is a
+// regular expression. It returns nil when there are no args.
+func killMatcher(args []string, regex bool) (func(string) bool, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	var preds []func(string) bool
+	for _, arg := range args {
+		switch {
+		case regex:
+			re, err := regexp.Compile(arg)
+			if err != nil {
+				return nil, NewUsageError(fmt.Sprintf("invalid pattern %q: %v", arg, err))
+			}
+			preds = append(preds, re.MatchString)
+		case strings.ContainsAny(arg, "*?["):
+			if _, err := path.Match(arg, ""); err != nil {
+				return nil, NewUsageError(fmt.Sprintf("invalid pattern %q: %v", arg, err))
+			}
+			preds = append(preds, func(name string) bool {
+				ok, _ := path.Match(arg, name)
+				return ok
+			})
+		default:
+			preds = append(preds, func(name string) bool { return name == arg })
+		}
+	}
+
+	return func(name string) bool {
+		for _, p := range preds {
+			if p(name) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// resolveProjectFlag turns a --project value into the project directory that
+// sessions are recorded under. The value may be an alias, optionally with a
+// subpath such as work/api, a saved project name or a path; paths are
+// resolved like session directories, except that a directory which no longer
+// exists is matched as given. When several saved projects share a name, the
+// highest ranked one wins.
+func resolveProjectFlag(cmd *cobra.Command, value string) (string, error) {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return "", err
+	}
+
+	aliases, err := loadAliasStore()
+	if err != nil {
+		return "", err
+	}
+	target, ok, err := resolver.ExpandAlias(aliases, value)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return resolver.NormalisePath(target), nil
+	}
+
+	if !resolver.IsPathArgument(value) {
+		store, err := loadProjectStore()
+		if err != nil {
+			return "", err
+		}
+		projects, err := store.WithOrder(project.Order(cfg.Projects.Order)).List()
+		if err != nil {
+			return "", err
+		}
+		for _, p := range projects {
+			if p.Name == value {
+				return p.Path, nil
+			}
+		}
+		return "", fmt.Errorf("No project found: %s", value) //nolint:staticcheck // user-facing message
+	}
+
+	dir := resolver.NormalisePath(value)
+	if resolved, err := resolver.ResolveProjectDir(dir, &resolver.RealCommandRunner{}, cfg.Git.ResolveRoot); err == nil {
+		return resolved, nil
+	}
+	return dir, nil
+}
+
+// killSessions kills each target, reporting failures per session on errW.
+// The current session is killed last so Portal is not terminated part way.
+// With dryRun it only prints what would be killed.
+func killSessions(w, errW io.Writer, deps *KillDeps, targets []string, dryRun bool) error {
+	if dryRun {
+		for _, name := range targets {
+			if _, err := fmt.Fprintf(w, "Would kill session: %s\n", name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if current, err := deps.Current(); err == nil && current != "" {
+		ordered := make([]string, 0, len(targets))
+		for _, name := range targets {
+			if name != current {
+				ordered = append(ordered, name)
+			}
+		}
+		if len(ordered) < len(targets) {
+			targets = append(ordered, current)
+		}
+	}
+
+	var lastErr error
+	failed := 0
+	for _, name := range targets {
+		if err := deps.Killer.KillSession(name); err != nil {
+			lastErr = err
+			failed++
+			if len(targets) > 1 {
+				_, _ = fmt.Fprintf(errW, "Error: %v\n", err)
+			}
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "Killed session: %s\n", name); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case failed == 0:
+		return nil
+	case len(targets) == 1:
+		return lastErr
+	default:
+		return fmt.Errorf("failed to kill %d of %d sessions", failed, len(targets))
+	}
+}
+
+// buildKillDeps returns the dependencies for the kill command.
 // When killDeps is set (testing), uses injected dependencies.
-// Otherwise, builds real implementations.
-func buildKillDeps() (SessionKiller, SessionValidator) {
+// Otherwise, builds real implementations that keep the session registry in sync.
+func buildKillDeps() (*KillDeps, error) {
 	if killDeps != nil {
-		return killDeps.Killer, killDeps.Validator
+		deps := *killDeps
+		if deps.Current == nil {
+			deps.Current = func() (string, error) { return "", nil }
+		}
+		return &deps, nil
+	}
+
+	client := backend()
+	reg, err := loadSessionRegistry()
+	if err != nil {
+		return nil, err
 	}
+	return &KillDeps{
+		Killer:    registry.NewTracker(client, reg),
+		Validator: client,
+		Lister:    client,
+		Registry:  reg,
+		Picker:    &tuiSessionPicker{},
+		Current: func() (string, error) {
+			if !client.Inside() {
+				return "", nil
+			}
+			return client.CurrentSessionName()
+		},
+	}, nil
+}
+
+// tuiSessionPicker runs ui.SessionPickerModel to choose sessions to kill.
+type tuiSessionPicker struct{}
 
-	client := tmux.NewClient(&tmux.RealCommander{})
-	return client, client
+// Pick shows the multi-select picker and returns the confirmed selection.
+func (p *tuiSessionPicker) Pick(sessions []tmux.Session) ([]string, error) {
+	if !isTTY() {
+		return nil, NewUsageError("no session specified; pass a name, a pattern or --all")
+	}
+
+	final, err := tea.NewProgram(ui.NewSessionPicker("Select sessions to kill:", sessions), tea.WithAltScreen()).Run()
+	if err != nil {
+		return nil, err
+	}
+
+	m, ok := final.(ui.SessionPickerModel)
+	if !ok {
+		return nil, fmt.Errorf("unexpected model type: %T", final)
+	}
+	if !m.Confirmed() {
+		return nil, nil
+	}
+	return m.Selected(), nil
 }
 
 func init() {
+	killCmd.Flags().Bool("all", false, "Kill every session")
+	killCmd.Flags().Bool("detached", false, "Only kill sessions with no attached client")
+	killCmd.Flags().String("project", "", "Only kill sessions of a project (path, alias or project name)")
+	killCmd.Flags().Bool("except-current", false, "Never kill the session Portal is running in")
+	killCmd.Flags().BoolP("regex", "E", false, "Treat arguments as regular expressions")
+	killCmd.Flags().BoolP("dry-run", "n", false, "Print the sessions that would be killed without killing them")
 	rootCmd.AddCommand(killCmd)
 }
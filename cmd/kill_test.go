@@ -1,21 +1,49 @@
 package cmd
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/leeovery/portal/internal/registry"
+	"github.com/leeovery/portal/internal/tmux"
 )
 
 // mockSessionKiller records KillSession calls for testing.
 type mockSessionKiller struct {
 	killedName string
+	killed     []string
 	err        error
+	failOn     map[string]error
 }
 
 func (m *mockSessionKiller) KillSession(name string) error {
 	m.killedName = name
+	if err := m.failOn[name]; err != nil {
+		return err
+	}
+	m.killed = append(m.killed, name)
 	return m.err
 }
 
+// mockSessionPicker implements SessionPicker for testing.
+type mockSessionPicker struct {
+	offered []string
+	pick    []string
+}
+
+func (m *mockSessionPicker) Pick(sessions []tmux.Session) ([]string, error) {
+	for _, s := range sessions {
+		m.offered = append(m.offered, s.Name)
+	}
+	return m.pick, nil
+}
+
 func TestKillCommand(t *testing.T) {
 	t.Run("existing session calls kill-session and exits 0", func(t *testing.T) {
 		killer := &mockSessionKiller{}
@@ -167,3 +195,225 @@ func TestKillCommand(t *testing.T) {
 		}
 	})
 }
+
+// bulkKillFixture sets up kill dependencies over a fixed set of sessions.
+func bulkKillFixture(t *testing.T) (*mockSessionKiller, *mockSessionPicker) {
+	t.Helper()
+	killer := &mockSessionKiller{}
+	picker := &mockSessionPicker{}
+	killDeps = &KillDeps{
+		Killer:    killer,
+		Validator: &mockSessionValidator{sessions: map[string]bool{}},
+		Lister: &mockSessionLister{sessions: []tmux.Session{
+			{Name: "api-01", Windows: 1, Attached: true},
+			{Name: "api-02", Windows: 2},
+			{Name: "web-01", Windows: 1},
+			{Name: "scratch", Windows: 1, Attached: true},
+		}},
+		Registry: &mockSessionRegistry{entries: map[string]registry.Entry{
+			"api-01": {Name: "api-01", ProjectPath: "/code/api"},
+			"api-02": {Name: "api-02", ProjectPath: "/code/api"},
+			"web-01": {Name: "web-01", ProjectPath: "/code/web"},
+		}},
+		Picker:  picker,
+		Current: func() (string, error) { return "scratch", nil },
+	}
+	t.Cleanup(func() { killDeps = nil })
+	return killer, picker
+}
+
+func runKillCmd(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+	resetRootCmd()
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	rootCmd.SetOut(stdout)
+	rootCmd.SetErr(stderr)
+	rootCmd.SetArgs(append([]string{"kill"}, args...))
+	err := rootCmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestKillCommandBulk(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "glob pattern", args: []string{"api-*"}, want: []string{"api-01", "api-02"}},
+		{name: "several patterns", args: []string{"web-?1", "scr*"}, want: []string{"web-01", "scratch"}},
+		{name: "regex", args: []string{"--regex", "^(web|api)-0[2-9]$"}, want: []string{"api-02"}},
+		{name: "all kills current session last", args: []string{"--all"}, want: []string{"api-01", "api-02", "web-01", "scratch"}},
+		{name: "detached", args: []string{"--detached"}, want: []string{"api-02", "web-01"}},
+		{name: "all except current", args: []string{"--all", "--except-current"}, want: []string{"api-01", "api-02", "web-01"}},
+		{name: "project path", args: []string{"--project", "/code/api"}, want: []string{"api-01", "api-02"}},
+		{name: "project with detached", args: []string{"--project", "/code/api", "--detached"}, want: []string{"api-02"}},
+		{name: "pattern with except current", args: []string{"*", "--except-current"}, want: []string{"api-01", "api-02", "web-01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			killer, _ := bulkKillFixture(t)
+			t.Setenv("PORTAL_CONFIG_FILE", filepath.Join(t.TempDir(), "config.json"))
+			t.Setenv("PORTAL_ALIASES_FILE", filepath.Join(t.TempDir(), "aliases"))
+
+			stdout, _, err := runKillCmd(t, tt.args...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(killer.killed, tt.want) {
+				t.Errorf("killed %v, want %v", killer.killed, tt.want)
+			}
+			for _, name := range tt.want {
+				if !strings.Contains(stdout, "Killed session: "+name+"\n") {
+					t.Errorf("output %q does not report %s", stdout, name)
+				}
+			}
+		})
+	}
+
+	t.Run("project alias resolves to its directory", func(t *testing.T) {
+		killer, _ := bulkKillFixture(t)
+		aliasFile := filepath.Join(t.TempDir(), "aliases")
+		if err := os.WriteFile(aliasFile, []byte("w=/code/web\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("PORTAL_ALIASES_FILE", aliasFile)
+
+		if _, _, err := runKillCmd(t, "--project", "w"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(killer.killed, []string{"web-01"}) {
+			t.Errorf("killed %v, want [web-01]", killer.killed)
+		}
+	})
+
+	t.Run("project alias with a subpath resolves through the alias", func(t *testing.T) {
+		killer, _ := bulkKillFixture(t)
+		aliasFile := filepath.Join(t.TempDir(), "aliases")
+		if err := os.WriteFile(aliasFile, []byte("code=/code\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("PORTAL_ALIASES_FILE", aliasFile)
+
+		if _, _, err := runKillCmd(t, "--project", "code/web"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(killer.killed, []string{"web-01"}) {
+			t.Errorf("killed %v, want [web-01]", killer.killed)
+		}
+	})
+
+	t.Run("unknown project is an error", func(t *testing.T) {
+		killer, _ := bulkKillFixture(t)
+		t.Setenv("PORTAL_ALIASES_FILE", filepath.Join(t.TempDir(), "aliases"))
+		t.Setenv("PORTAL_PROJECTS_FILE", filepath.Join(t.TempDir(), "projects.json"))
+
+		_, _, err := runKillCmd(t, "--project", "nope")
+		if err == nil || err.Error() != "No project found: nope" {
+			t.Errorf("error = %v, want No project found: nope", err)
+		}
+		if len(killer.killed) != 0 {
+			t.Errorf("killed %v, want none", killer.killed)
+		}
+	})
+
+	t.Run("dry run prints targets without killing", func(t *testing.T) {
+		killer, _ := bulkKillFixture(t)
+
+		stdout, _, err := runKillCmd(t, "--dry-run", "api-*")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(killer.killed) != 0 {
+			t.Errorf("killed %v, want none", killer.killed)
+		}
+		want := "Would kill session: api-01\nWould kill session: api-02\n"
+		if stdout != want {
+			t.Errorf("output = %q, want %q", stdout, want)
+		}
+	})
+
+	t.Run("pattern matching nothing is an error", func(t *testing.T) {
+		bulkKillFixture(t)
+
+		_, _, err := runKillCmd(t, "db-*")
+		if err == nil || err.Error() != "No sessions match" {
+			t.Errorf("error = %v, want No sessions match", err)
+		}
+	})
+
+	t.Run("failures are reported per session and exit non-zero", func(t *testing.T) {
+		killer, _ := bulkKillFixture(t)
+		killer.failOn = map[string]error{"api-01": fmt.Errorf("failed to kill tmux session \"api-01\": exit status 1")}
+
+		stdout, stderr, err := runKillCmd(t, "api-*")
+		if err == nil || err.Error() != "failed to kill 1 of 2 sessions" {
+			t.Errorf("error = %v, want failed to kill 1 of 2 sessions", err)
+		}
+		if !strings.Contains(stderr, `failed to kill tmux session "api-01"`) {
+			t.Errorf("stderr = %q, want the api-01 failure", stderr)
+		}
+		if stdout != "Killed session: api-02\n" {
+			t.Errorf("stdout = %q, want only api-02 killed", stdout)
+		}
+	})
+
+	t.Run("invalid glob is a usage error", func(t *testing.T) {
+		bulkKillFixture(t)
+
+		_, _, err := runKillCmd(t, "api-[")
+		var usageErr *UsageError
+		if !errors.As(err, &usageErr) {
+			t.Errorf("error = %v, want UsageError", err)
+		}
+	})
+
+	t.Run("all with names is a usage error", func(t *testing.T) {
+		bulkKillFixture(t)
+
+		_, _, err := runKillCmd(t, "--all", "api-01")
+		var usageErr *UsageError
+		if !errors.As(err, &usageErr) {
+			t.Errorf("error = %v, want UsageError", err)
+		}
+	})
+}
+
+func TestKillCommandPicker(t *testing.T) {
+	t.Run("no arguments kills the picked sessions", func(t *testing.T) {
+		killer, picker := bulkKillFixture(t)
+		picker.pick = []string{"api-02", "web-01"}
+
+		if _, _, err := runKillCmd(t); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(picker.offered, []string{"api-01", "api-02", "web-01", "scratch"}) {
+			t.Errorf("offered %v", picker.offered)
+		}
+		if !reflect.DeepEqual(killer.killed, picker.pick) {
+			t.Errorf("killed %v, want %v", killer.killed, picker.pick)
+		}
+	})
+
+	t.Run("except current hides the current session from the picker", func(t *testing.T) {
+		_, picker := bulkKillFixture(t)
+
+		if _, _, err := runKillCmd(t, "--except-current"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(picker.offered, []string{"api-01", "api-02", "web-01"}) {
+			t.Errorf("offered %v", picker.offered)
+		}
+	})
+
+	t.Run("cancelled picker kills nothing", func(t *testing.T) {
+		killer, _ := bulkKillFixture(t)
+
+		if _, _, err := runKillCmd(t); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(killer.killed) != 0 {
+			t.Errorf("killed %v, want none", killer.killed)
+		}
+	})
+}
@@ -1,10 +1,22 @@
 package cmd
 
 import (
+	"cmp"
+	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
 
+	"github.com/leeovery/portal/internal/fuzzy"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/registry"
 	"github.com/leeovery/portal/internal/tmux"
+	"github.com/leeovery/portal/internal/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -17,10 +29,24 @@ type SessionLister interface {
 	ListSessions() ([]tmux.Session, error)
 }
 
+// SessionRegistry looks up which project each session was created from.
+type SessionRegistry interface {
+	ByName() (map[string]registry.Entry, error)
+}
+
+// ProjectLister lists remembered projects.
+type ProjectLister interface {
+	List() ([]project.Project, error)
+}
+
 // ListDeps allows injecting dependencies for testing.
 type ListDeps struct {
-	Lister SessionLister
-	IsTTY  func() bool
+	Lister   SessionLister
+	IsTTY    func() bool
+	Registry SessionRegistry
+	Projects ProjectLister
+	// Now returns the current time for relative timestamps; nil means time.Now.
+	Now func() time.Time
 }
 
 // isTTY detects whether stdout is a terminal using os.Stdout.Stat().
@@ -33,40 +59,204 @@ func isTTY() bool {
 }
 
 // formatSessionLong formats a session in long (full details) format.
-func formatSessionLong(s tmux.Session) string {
+// When the session's project is known from the registry, its path is appended,
+// otherwise the session's working directory is. Creation and activity times
+// follow, relative to now, when tmux reported them.
+func formatSessionLong(s tmux.Session, entry registry.Entry, known bool, now time.Time) string {
 	status := "detached"
-	if s.Attached {
+	switch {
+	case s.Exited:
+		status = "exited"
+	case s.Attached:
 		status = "attached"
 	}
+	if s.Clients > 1 {
+		status += fmt.Sprintf(" (%d clients)", s.Clients)
+	}
 	windowWord := "windows"
 	if s.Windows == 1 {
 		windowWord = "window"
 	}
-	return fmt.Sprintf("%s    %s    %d %s", s.Name, status, s.Windows, windowWord)
+	line := fmt.Sprintf("%s    %s    %d %s", s.Name, status, s.Windows, windowWord)
+	if known {
+		line += "    " + entry.ProjectPath
+	} else if s.Path != "" {
+		line += "    " + s.Path
+	}
+	if !s.Created.IsZero() {
+		line += "    created " + ui.RelativeTime(s.Created, now)
+	}
+	if !s.Activity.IsZero() {
+		line += "    active " + ui.RelativeTime(s.Activity, now)
+	}
+	return line
+}
+
+// ListedSession is one session in `list --json` output and the data passed
+// to --format templates. Fields are only ever added, never renamed or
+// removed, so scripts can rely on them.
+type ListedSession struct {
+	Name         string     `json:"name"`
+	Windows      int        `json:"windows"`
+	Attached     bool       `json:"attached"`
+	ProjectName  string     `json:"project_name"`
+	ProjectPath  string     `json:"project_path"`
+	Command      []string   `json:"command"`
+	Clients      int        `json:"clients"`
+	Created      *time.Time `json:"created"`
+	LastAttached *time.Time `json:"last_attached"`
+	Activity     *time.Time `json:"activity"`
+	Path         string     `json:"path"`
+	Group        string     `json:"group"`
+	ActiveWindow string     `json:"active_window"`
+	Exited       bool       `json:"exited"`
+}
+
+// optionalTime returns nil for the zero time so it is encoded as null.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// newListedSession combines a tmux session with its registry entry, if any.
+func newListedSession(s tmux.Session, entry registry.Entry) ListedSession {
+	command := entry.Command
+	if command == nil {
+		command = []string{}
+	}
+	return ListedSession{
+		Name:         s.Name,
+		Windows:      s.Windows,
+		Attached:     s.Attached,
+		ProjectName:  entry.ProjectName,
+		ProjectPath:  entry.ProjectPath,
+		Command:      command,
+		Clients:      s.Clients,
+		Created:      optionalTime(s.Created),
+		LastAttached: optionalTime(s.LastAttached),
+		Activity:     optionalTime(s.Activity),
+		Path:         s.Path,
+		Group:        s.Group,
+		ActiveWindow: s.ActiveWindow,
+		Exited:       s.Exited,
+	}
+}
+
+// listSortKeys maps each --sort key to a comparison of two sessions.
+var listSortKeys = map[string]func(a, b ListedSession) int{
+	"name":    func(a, b ListedSession) int { return strings.Compare(a.Name, b.Name) },
+	"windows": func(a, b ListedSession) int { return cmp.Compare(a.Windows, b.Windows) },
+	// Attached sessions sort first.
+	"attached": func(a, b ListedSession) int { return compareBool(b.Attached, a.Attached) },
+	"project":  func(a, b ListedSession) int { return strings.Compare(a.ProjectName, b.ProjectName) },
+	"created":  func(a, b ListedSession) int { return compareTime(a.Created, b.Created) },
+	"activity": func(a, b ListedSession) int { return compareTime(a.Activity, b.Activity) },
+}
+
+// compareTime orders unknown times before known ones.
+func compareTime(a, b *time.Time) int {
+	switch {
+	case a == nil || b == nil:
+		return compareBool(a != nil, b != nil)
+	default:
+		return a.Compare(*b)
+	}
+}
+
+// compareBool orders false before true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// sortListedSessions sorts sessions by key, which may be prefixed with "-"
+// to reverse the order. Ties keep tmux's order.
+func sortListedSessions(sessions []ListedSession, key string) error {
+	field, reverse := strings.CutPrefix(key, "-")
+	compare, ok := listSortKeys[field]
+	if !ok {
+		keys := slices.Sorted(maps.Keys(listSortKeys))
+		return NewUsageError(fmt.Sprintf("invalid --sort key %q (valid: %s)", key, strings.Join(keys, ", ")))
+	}
+	slices.SortStableFunc(sessions, func(a, b ListedSession) int {
+		if reverse {
+			return compare(b, a)
+		}
+		return compare(a, b)
+	})
+	return nil
+}
+
+// writeListedJSON writes sessions as an indented JSON array.
+func writeListedJSON(w io.Writer, sessions []ListedSession) error {
+	if sessions == nil {
+		sessions = []ListedSession{}
+	}
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// parseListFormat parses a --format template. Templates see a ListedSession
+// and may use join, e.g. {{join .Command " "}}.
+func parseListFormat(format string) (*template.Template, error) {
+	tmpl, err := template.New("format").Funcs(template.FuncMap{"join": strings.Join}).Parse(format)
+	if err != nil {
+		return nil, NewUsageError(fmt.Sprintf("invalid --format template: %v", err))
+	}
+	return tmpl, nil
 }
 
 var listCmd = &cobra.Command{
 	Use:   "list",
-	Short: "List running tmux sessions",
+	Short: "List running sessions",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		shortFlag, _ := cmd.Flags().GetBool("short")
 		longFlag, _ := cmd.Flags().GetBool("long")
+		jsonFlag, _ := cmd.Flags().GetBool("json")
+		formatFlag, _ := cmd.Flags().GetString("format")
+		sortFlag, _ := cmd.Flags().GetString("sort")
+		filterFlag, _ := cmd.Flags().GetString("filter")
 
 		if shortFlag && longFlag {
 			return fmt.Errorf("--short and --long are mutually exclusive")
 		}
+		modes := 0
+		for _, set := range []bool{shortFlag || longFlag, jsonFlag, formatFlag != ""} {
+			if set {
+				modes++
+			}
+		}
+		if modes > 1 {
+			return NewUsageError("--json, --format and --short/--long are mutually exclusive")
+		}
 
-		lister, ttyDetect := buildListDeps()
+		var tmpl *template.Template
+		if formatFlag != "" {
+			var err error
+			if tmpl, err = parseListFormat(formatFlag); err != nil {
+				return err
+			}
+		}
+
+		lister, ttyDetect, reg, now := buildListDeps()
 
 		sessions, err := lister.ListSessions()
 		if err != nil {
 			return err
 		}
 
-		if len(sessions) == 0 {
-			return nil
-		}
-
 		// Determine output mode: long (full details) or short (names only)
 		useLong := ttyDetect()
 		if shortFlag {
@@ -76,13 +266,57 @@ var listCmd = &cobra.Command{
 			useLong = true
 		}
 
+		// Registry lookups are best-effort: an unreadable registry only
+		// hides project paths and tags, it never prevents listing.
+		entries := map[string]registry.Entry{}
+		if (useLong || jsonFlag || tmpl != nil || sortFlag != "" || filterFlag != "") && reg != nil {
+			if byName, err := reg.ByName(); err == nil {
+				entries = byName
+			}
+		}
+
+		if filterFlag != "" {
+			sessions = fuzzy.FilterTagged(sessions, filterFlag,
+				func(s tmux.Session) string { return s.Name },
+				sessionTags(entries, buildListProjects()))
+		}
+
+		if len(sessions) == 0 {
+			if jsonFlag {
+				return writeListedJSON(cmd.OutOrStdout(), nil)
+			}
+			return nil
+		}
+
+		listed := make([]ListedSession, len(sessions))
+		byName := make(map[string]tmux.Session, len(sessions))
+		for i, s := range sessions {
+			listed[i] = newListedSession(s, entries[s.Name])
+			byName[s.Name] = s
+		}
+		if sortFlag != "" {
+			if err := sortListedSessions(listed, sortFlag); err != nil {
+				return err
+			}
+		}
+
 		w := cmd.OutOrStdout()
-		for _, s := range sessions {
+		if jsonFlag {
+			return writeListedJSON(w, listed)
+		}
+
+		for _, ls := range listed {
 			var err error
-			if useLong {
-				_, err = fmt.Fprintln(w, formatSessionLong(s))
-			} else {
-				_, err = fmt.Fprintln(w, s.Name)
+			switch {
+			case tmpl != nil:
+				if err = tmpl.Execute(w, ls); err == nil {
+					_, err = fmt.Fprintln(w)
+				}
+			case useLong:
+				entry, known := entries[ls.Name]
+				_, err = fmt.Fprintln(w, formatSessionLong(byName[ls.Name], entry, known, now()))
+			default:
+				_, err = fmt.Fprintln(w, ls.Name)
 			}
 			if err != nil {
 				return err
@@ -93,17 +327,63 @@ var listCmd = &cobra.Command{
 	},
 }
 
+// sessionTags returns the tags of each session's project, matched like the
+// TUI does: by the project the registry recorded for the session, otherwise
+// by the session's directory. Unreadable projects leave sessions untagged.
+func sessionTags(entries map[string]registry.Entry, projects ProjectLister) func(tmux.Session) []string {
+	tags := map[string][]string{}
+	if projects != nil {
+		if list, err := projects.List(); err == nil {
+			for _, p := range list {
+				tags[p.Path] = p.Tags
+			}
+		}
+	}
+	return func(s tmux.Session) []string {
+		if entry, ok := entries[s.Name]; ok {
+			return tags[entry.ProjectPath]
+		}
+		return tags[s.Path]
+	}
+}
+
+// buildListProjects returns the projects whose tags --filter matches, or nil
+// when the projects file path cannot be determined.
+func buildListProjects() ProjectLister {
+	if listDeps != nil {
+		return listDeps.Projects
+	}
+	store, err := loadProjectStore()
+	if err != nil {
+		return nil
+	}
+	return store
+}
+
 // buildListDeps returns the appropriate dependencies for the list command.
-func buildListDeps() (SessionLister, func() bool) {
+// The registry is nil when its file path cannot be determined.
+func buildListDeps() (SessionLister, func() bool, SessionRegistry, func() time.Time) {
 	if listDeps != nil {
-		return listDeps.Lister, listDeps.IsTTY
+		now := listDeps.Now
+		if now == nil {
+			now = time.Now
+		}
+		return listDeps.Lister, listDeps.IsTTY, listDeps.Registry, now
+	}
+	client := backend()
+	reg, err := loadSessionRegistry()
+	if err != nil {
+		return client, isTTY, nil, time.Now
 	}
-	client := tmux.NewClient(&tmux.RealCommander{})
-	return client, isTTY
+	return client, isTTY, reg, time.Now
 }
 
 func init() {
 	listCmd.Flags().Bool("short", false, "Output session names only")
 	listCmd.Flags().Bool("long", false, "Output full session details")
+	listCmd.Flags().Bool("json", false, "Output sessions as a JSON array")
+	listCmd.Flags().String("format", "", "Output each session with a Go template, e.g. '{{.Name}} {{.ProjectPath}}'")
+	listCmd.Flags().String("sort", "", "Sort by name, windows, attached, project, created or activity; prefix with - to reverse")
+	listCmd.Flags().String("filter", "", "Only list sessions matching the filter, as in the TUI; #tag matches project tags")
 	rootCmd.AddCommand(listCmd)
 }
@@ -2,8 +2,16 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/registry"
 	"github.com/leeovery/portal/internal/tmux"
 )
 
@@ -17,6 +25,16 @@ func (m *mockSessionLister) ListSessions() ([]tmux.Session, error) {
 	return m.sessions, m.err
 }
 
+// mockSessionRegistry implements SessionRegistry for testing.
+type mockSessionRegistry struct {
+	entries map[string]registry.Entry
+	err     error
+}
+
+func (m *mockSessionRegistry) ByName() (map[string]registry.Entry, error) {
+	return m.entries, m.err
+}
+
 func TestListCommand(t *testing.T) {
 	t.Run("TTY output includes name status and window count", func(t *testing.T) {
 		lister := &mockSessionLister{
@@ -274,4 +292,236 @@ func TestListCommand(t *testing.T) {
 			t.Fatal("expected error for mutually exclusive flags, got nil")
 		}
 	})
+	t.Run("long output appends registered project path", func(t *testing.T) {
+		lister := &mockSessionLister{
+			sessions: []tmux.Session{
+				{Name: "api", Windows: 2, Attached: false},
+				{Name: "scratch", Windows: 1, Attached: false},
+			},
+		}
+		listDeps = &ListDeps{
+			Lister: lister,
+			IsTTY:  func() bool { return true },
+			Registry: &mockSessionRegistry{entries: map[string]registry.Entry{
+				"api": {Name: "api", ProjectPath: "/code/api", ProjectName: "api"},
+			}},
+		}
+		t.Cleanup(func() { listDeps = nil })
+
+		resetRootCmd()
+		buf := new(bytes.Buffer)
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"list"})
+
+		err := rootCmd.Execute()
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := "api    detached    2 windows    /code/api\nscratch    detached    1 window\n"
+		if buf.String() != want {
+			t.Errorf("output = %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("registry error does not prevent listing", func(t *testing.T) {
+		lister := &mockSessionLister{
+			sessions: []tmux.Session{
+				{Name: "api", Windows: 2, Attached: false},
+			},
+		}
+		listDeps = &ListDeps{
+			Lister:   lister,
+			IsTTY:    func() bool { return true },
+			Registry: &mockSessionRegistry{err: fmt.Errorf("unreadable")},
+		}
+		t.Cleanup(func() { listDeps = nil })
+
+		resetRootCmd()
+		buf := new(bytes.Buffer)
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"list"})
+
+		err := rootCmd.Execute()
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.String() != "api    detached    2 windows\n" {
+			t.Errorf("output = %q", buf.String())
+		}
+	})
+}
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// assertGolden compares got with testdata/<name>.golden, rewriting the file
+// when the -update flag is set.
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output does not match %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestListMachineOutput(t *testing.T) {
+	now := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+	sessions := []tmux.Session{
+		{
+			Name: "flowx-dev", Windows: 3, Attached: true, Clients: 2,
+			Created:      now.Add(-26 * time.Hour),
+			LastAttached: now.Add(-2 * time.Hour),
+			Activity:     now.Add(-5 * time.Minute),
+			Path:         "/code/flowx", ActiveWindow: "claude",
+		},
+		{Name: "claude-lab", Windows: 1, Attached: false},
+		{
+			Name: "scratch", Windows: 2, Attached: false,
+			Created:  now.Add(-3 * time.Hour),
+			Activity: now.Add(-3 * time.Hour),
+			Path:     "/tmp", Group: "misc", ActiveWindow: "zsh",
+		},
+	}
+	entries := map[string]registry.Entry{
+		"flowx-dev":  {Name: "flowx-dev", ProjectName: "flowx", ProjectPath: "/code/flowx", Command: []string{"claude", "--resume"}},
+		"claude-lab": {Name: "claude-lab", ProjectName: "lab", ProjectPath: "/code/lab"},
+	}
+
+	tests := []struct {
+		name     string
+		golden   string
+		args     []string
+		sessions []tmux.Session
+	}{
+		{name: "json includes registry fields", golden: "list/json", args: []string{"--json"}, sessions: sessions},
+		{name: "json with no sessions is an empty array", golden: "list/json_empty", args: []string{"--json"}, sessions: []tmux.Session{}},
+		{name: "format template", golden: "list/format", args: []string{"--format", "{{.Name}}\t{{.Windows}}\t{{.ProjectPath}}\t{{join .Command \" \"}}"}, sessions: sessions},
+		{name: "sort by windows descending", golden: "list/sort_windows_desc", args: []string{"--short", "--sort", "-windows"}, sessions: sessions},
+		{name: "sort by project with format", golden: "list/sort_project", args: []string{"--format", "{{.ProjectName}}:{{.Name}}", "--sort", "project"}, sessions: sessions},
+		{name: "filter uses fuzzy matching", golden: "list/filter", args: []string{"--long", "--filter", "cl"}, sessions: sessions},
+		{name: "long shows clients, path and relative times", golden: "list/long", args: []string{"--long"}, sessions: sessions},
+		{name: "sort by activity, most recent first", golden: "list/sort_activity_desc", args: []string{"--short", "--sort", "-activity"}, sessions: sessions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listDeps = &ListDeps{
+				Lister:   &mockSessionLister{sessions: tt.sessions},
+				IsTTY:    func() bool { return true },
+				Registry: &mockSessionRegistry{entries: entries},
+				Now:      func() time.Time { return now },
+			}
+			t.Cleanup(func() { listDeps = nil })
+
+			resetRootCmd()
+			buf := new(bytes.Buffer)
+			rootCmd.SetOut(buf)
+			rootCmd.SetArgs(append([]string{"list"}, tt.args...))
+
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertGolden(t, tt.golden, buf.String())
+		})
+	}
+
+	usageErrors := []struct {
+		name string
+		args []string
+	}{
+		{name: "json with format", args: []string{"--json", "--format", "{{.Name}}"}},
+		{name: "json with short", args: []string{"--json", "--short"}},
+		{name: "invalid template", args: []string{"--format", "{{.Name"}},
+		{name: "unknown sort key", args: []string{"--sort", "age"}},
+	}
+	for _, tt := range usageErrors {
+		t.Run(tt.name, func(t *testing.T) {
+			listDeps = &ListDeps{
+				Lister: &mockSessionLister{sessions: sessions},
+				IsTTY:  func() bool { return true },
+			}
+			t.Cleanup(func() { listDeps = nil })
+
+			resetRootCmd()
+			rootCmd.SetArgs(append([]string{"list"}, tt.args...))
+
+			err := rootCmd.Execute()
+			var usageErr *UsageError
+			if !errors.As(err, &usageErr) {
+				t.Errorf("error = %v, want UsageError", err)
+			}
+		})
+	}
+}
+
+// mockProjectLister implements ProjectLister for testing.
+type mockProjectLister struct {
+	projects []project.Project
+}
+
+func (m *mockProjectLister) List() ([]project.Project, error) {
+	return m.projects, nil
+}
+
+func TestListFilterTags(t *testing.T) {
+	sessions := []tmux.Session{
+		{Name: "flowx-dev", Windows: 1},
+		{Name: "lab", Windows: 1, Path: "/code/lab"},
+		{Name: "scratch", Windows: 1, Path: "/tmp"},
+	}
+	entries := map[string]registry.Entry{
+		"flowx-dev": {Name: "flowx-dev", ProjectName: "flowx", ProjectPath: "/code/flowx"},
+	}
+	projects := []project.Project{
+		{Path: "/code/flowx", Name: "flowx", Tags: []string{"work", "client"}},
+		{Path: "/code/lab", Name: "lab", Tags: []string{"work"}},
+	}
+
+	tests := []struct {
+		filter string
+		want   string
+	}{
+		{filter: "#work", want: "flowx-dev\nlab\n"},
+		{filter: "#client", want: "flowx-dev\n"},
+		{filter: "!#work", want: "scratch\n"},
+		{filter: "#work la", want: "lab\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			listDeps = &ListDeps{
+				Lister:   &mockSessionLister{sessions: sessions},
+				IsTTY:    func() bool { return false },
+				Registry: &mockSessionRegistry{entries: entries},
+				Projects: &mockProjectLister{projects: projects},
+			}
+			t.Cleanup(func() { listDeps = nil })
+
+			resetRootCmd()
+			buf := new(bytes.Buffer)
+			rootCmd.SetOut(buf)
+			rootCmd.SetArgs([]string{"list", "--filter", tt.filter})
+
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("output = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
 }
@@ -1,18 +1,28 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/leeovery/portal/internal/browser"
+	"github.com/leeovery/portal/internal/config"
+	"github.com/leeovery/portal/internal/gitstatus"
+	"github.com/leeovery/portal/internal/layout"
+	"github.com/leeovery/portal/internal/mux"
 	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/registry"
 	"github.com/leeovery/portal/internal/resolver"
 	"github.com/leeovery/portal/internal/session"
+	"github.com/leeovery/portal/internal/snapshot"
 	"github.com/leeovery/portal/internal/tmux"
 	"github.com/leeovery/portal/internal/tui"
+	"github.com/leeovery/portal/internal/worktree"
 	"github.com/spf13/cobra"
 )
 
@@ -27,49 +37,53 @@ type OpenDeps struct {
 	DirValidator resolver.DirValidator
 }
 
-// SessionConnector connects the user to a tmux session.
-// The implementation differs based on whether Portal is inside or outside tmux.
+// SessionConnector connects the user to a session.
+// The implementation differs based on whether Portal is inside or outside the
+// multiplexer.
 type SessionConnector interface {
 	Connect(name string) error
 }
 
-// SwitchClienter defines the interface for switching tmux clients.
+// SwitchClienter defines the interface for switching multiplexer clients.
 type SwitchClienter interface {
 	SwitchClient(name string) error
 }
 
-// SwitchConnector connects to a session by issuing tmux switch-client.
-// Used when Portal is running inside an existing tmux session.
+// SwitchConnector connects to a session by switching the current client.
+// Used when Portal is running inside an existing session.
 type SwitchConnector struct {
 	client SwitchClienter
 }
 
-// Connect switches the current tmux client to the named session.
+// Connect switches the current client to the named session.
 func (sc *SwitchConnector) Connect(name string) error {
 	return sc.client.SwitchClient(name)
 }
 
-// AttachConnector connects to a session by exec-ing tmux attach-session.
-// Used when Portal is running outside tmux (bare shell).
-type AttachConnector struct{}
+// AttachConnector connects to a session by exec-ing the backend's attach
+// command. Used when Portal is running outside the multiplexer (bare shell).
+type AttachConnector struct {
+	backend mux.Backend
+}
 
-// Connect replaces the current process with tmux attach-session.
+// Connect replaces the current process with a client attached to the session.
 func (ac *AttachConnector) Connect(name string) error {
-	tmuxPath, err := exec.LookPath("tmux")
+	argv := ac.backend.AttachArgs(name)
+	path, err := exec.LookPath(argv[0])
 	if err != nil {
-		return fmt.Errorf("tmux not found: %w", err)
+		return fmt.Errorf("%s not found: %w", argv[0], err)
 	}
-	return syscall.Exec(tmuxPath, []string{"tmux", "attach-session", "-t", name}, os.Environ())
+	return syscall.Exec(path, argv, os.Environ())
 }
 
 // buildSessionConnector returns the appropriate SessionConnector based on
-// whether Portal is running inside an existing tmux session.
+// whether Portal is running inside an existing session of the backend.
 func buildSessionConnector() SessionConnector {
-	if tmux.InsideTmux() {
-		client := tmux.NewClient(&tmux.RealCommander{})
-		return &SwitchConnector{client: client}
+	b := backend()
+	if b.Inside() {
+		return &SwitchConnector{client: b}
 	}
-	return &AttachConnector{}
+	return &AttachConnector{backend: b}
 }
 
 var openCmd = &cobra.Command{
@@ -82,8 +96,13 @@ var openCmd = &cobra.Command{
 			return err
 		}
 
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+
 		if destination == "" {
-			return openTUI("", command)
+			return openTUI("", command, cfg)
 		}
 
 		query := destination
@@ -100,9 +119,18 @@ var openCmd = &cobra.Command{
 
 		switch r := result.(type) {
 		case *resolver.PathResult:
-			return openPath(r.Path, command)
+			return openPath(r.Path, command, cfg)
+		case *resolver.WorktreeResult:
+			path, created, err := worktree.Ensure(r.Repo, r.Branch, &resolver.RealCommandRunner{})
+			if err != nil {
+				return err
+			}
+			if created {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Created worktree for %s at %s\n", r.Branch, path)
+			}
+			return openPath(path, command, cfg)
 		case *resolver.FallbackResult:
-			return openTUI(r.Query, command)
+			return openTUI(r.Query, command, cfg)
 		default:
 			return fmt.Errorf("unexpected resolution result: %T", result)
 		}
@@ -153,7 +181,7 @@ func parseCommandArgs(cmd *cobra.Command, args []string) ([]string, string, erro
 	return nil, dest, nil
 }
 
-// sessionCreatorIface creates a tmux session from a directory and returns the session name.
+// sessionCreatorIface creates a session from a directory and returns the session name.
 type sessionCreatorIface interface {
 	CreateFromDir(dir string, command []string) (string, error)
 }
@@ -186,23 +214,24 @@ func (a *quickStartAdapter) Run(path string, command []string) (*session.QuickSt
 	return a.qs.Run(path, command)
 }
 
-// PathOpener handles creating a new tmux session from a resolved path.
-// It branches on insideTmux: inside tmux creates detached then switches;
-// outside tmux uses exec handoff with -A flag.
+// PathOpener handles creating a new session from a resolved path.
+// It branches on insideMux: inside the multiplexer it creates the session
+// detached then switches; outside it uses an exec handoff that creates or
+// attaches in one step.
 type PathOpener struct {
-	insideTmux bool
-	creator    sessionCreatorIface
-	switcher   SwitchClienter
-	qs         quickStarter
-	execer     execer
-	tmuxPath   string
+	insideMux bool
+	creator   sessionCreatorIface
+	switcher  SwitchClienter
+	qs        quickStarter
+	execer    execer
+	muxPath   string
 }
 
 // Open creates a session at the given path and connects to it.
 // When command is non-nil, it is passed through to session creation
-// for execution as a tmux shell-command.
+// for execution as a shell-command.
 func (po *PathOpener) Open(resolvedPath string, command []string) error {
-	if po.insideTmux {
+	if po.insideMux {
 		sessionName, err := po.creator.CreateFromDir(resolvedPath, command)
 		if err != nil {
 			return err
@@ -215,49 +244,128 @@ func (po *PathOpener) Open(resolvedPath string, command []string) error {
 		return err
 	}
 
-	return po.execer.Exec(po.tmuxPath, result.ExecArgs, os.Environ())
+	return po.execer.Exec(po.muxPath, result.ExecArgs, os.Environ())
 }
 
-// openPath creates a new tmux session at the given resolved directory path.
-// When inside tmux, it creates the session detached and switches to it.
-// When outside tmux, it execs into tmux with the -A flag for atomic create-or-attach.
-func openPath(resolvedPath string, command []string) error {
-	client := tmux.NewClient(&tmux.RealCommander{})
-	gitResolver := &resolverAdapter{}
+// openPath creates a new session at the given resolved directory path.
+// When inside the multiplexer, it creates the session detached and switches to it.
+// When outside, it execs into the multiplexer, creating or attaching atomically
+// where the backend supports it.
+func openPath(resolvedPath string, command []string, cfg config.Config) error {
+	b := backend()
+	gitResolver := &resolverAdapter{useGitRoot: cfg.Git.ResolveRoot}
 	projectsPath, err := projectsFilePath()
 	if err != nil {
 		return err
 	}
 	store := project.NewStore(projectsPath)
 	gen := session.NewNanoIDGenerator()
+	reg, err := loadSessionRegistry()
+	if err != nil {
+		return err
+	}
 
-	insideTmux := tmux.InsideTmux()
+	insideMux := b.Inside()
+
+	creator := newSessionCreator(gitResolver, store, b, gen, cfg).WithRegistry(reg)
+	qs := session.NewQuickStart(gitResolver, store, b, gen).
+		WithNameFormats(nameFormats(cfg)).
+		WithDefaultCommand(cfg.Session.DefaultCommand).
+		WithHandoff(b).
+		WithRegistry(reg)
+	if client, ok := mux.TmuxClient(b); ok {
+		layouts, err := buildLayoutApplier(client)
+		if err != nil {
+			return err
+		}
+		creator.WithLayouts(layouts)
+		qs.WithLayouts(client, layouts)
+		autoSnapshot(cfg, client)
+	}
 
 	opener := &PathOpener{
-		insideTmux: insideTmux,
-		creator:    session.NewSessionCreator(gitResolver, store, client, gen),
-		switcher:   client,
-		qs:         &quickStartAdapter{qs: session.NewQuickStart(gitResolver, store, client, gen)},
-		execer:     &realExecer{},
+		insideMux: insideMux,
+		creator:   creator,
+		switcher:  b,
+		qs:        &quickStartAdapter{qs: qs},
+		execer:    &realExecer{},
 	}
 
-	if !insideTmux {
-		tmuxPath, err := exec.LookPath("tmux")
+	if !insideMux {
+		muxPath, err := exec.LookPath(b.Name())
 		if err != nil {
-			return fmt.Errorf("tmux not found: %w", err)
+			return fmt.Errorf("%s not found: %w", b.Name(), err)
 		}
-		opener.tmuxPath = tmuxPath
+		opener.muxPath = muxPath
 	}
 
 	return opener.Open(resolvedPath, command)
 }
 
-// resolverAdapter adapts resolver.ResolveGitRoot to the session.GitResolver interface.
-type resolverAdapter struct{}
+// newSessionCreator creates a session creator configured with the session settings from cfg.
+func newSessionCreator(git session.GitResolver, store session.ProjectStore, client session.TmuxClient, gen session.IDGenerator, cfg config.Config) *session.SessionCreator {
+	return session.NewSessionCreator(git, store, client, gen).
+		WithNameFormats(nameFormats(cfg)).
+		WithDefaultCommand(cfg.Session.DefaultCommand)
+}
+
+// nameFormats builds the session name templates from cfg. Per-project keys
+// that look like paths are normalised so they match resolved project
+// directories.
+func nameFormats(cfg config.Config) session.NameFormats {
+	projects := make(map[string]string, len(cfg.Session.ProjectNameFormats))
+	for key, tmpl := range cfg.Session.ProjectNameFormats {
+		if resolver.IsPathArgument(key) {
+			key = resolver.NormalisePath(key)
+		}
+		projects[key] = tmpl
+	}
+	return session.NameFormats{Default: cfg.Session.NameFormat, Projects: projects}
+}
+
+// buildLayoutApplier creates a layout applier that reads project layouts from
+// the configured layouts directory and replays them through the tmux client.
+func buildLayoutApplier(client *tmux.Client) (*layout.Applier, error) {
+	dir, err := layoutsDirPath()
+	if err != nil {
+		return nil, err
+	}
+	return layout.NewApplier(layout.NewStore(dir), client), nil
+}
+
+// layoutsDirPath returns the path to the layouts directory.
+// Uses PORTAL_LAYOUTS_DIR env var if set, otherwise
+// defaults to ~/.config/portal/layouts.
+func layoutsDirPath() (string, error) {
+	return configFilePath("PORTAL_LAYOUTS_DIR", "layouts")
+}
 
-// Resolve resolves a directory to its git repository root.
+// resolverAdapter adapts resolver.ResolveProjectDir to the session.GitResolver interface.
+type resolverAdapter struct {
+	useGitRoot bool
+}
+
+// Resolve resolves a directory to its git repository root, unless git root
+// resolution is disabled in the config.
 func (r *resolverAdapter) Resolve(dir string) (string, error) {
-	return resolver.ResolveGitRoot(dir, &resolver.RealCommandRunner{})
+	return resolver.ResolveProjectDir(dir, &resolver.RealCommandRunner{}, r.useGitRoot)
+}
+
+// Branch returns the git branch checked out in dir, for the {branch}
+// session name placeholder.
+func (r *resolverAdapter) Branch(dir string) (string, error) {
+	return resolver.CurrentBranch(dir, &resolver.RealCommandRunner{})
+}
+
+// ProjectName names a linked git worktree after its repository and branch,
+// e.g. api@feature, and any other directory after its basename.
+func (r *resolverAdapter) ProjectName(dir string) string {
+	repo, ok := worktree.Info(dir)
+	if !ok {
+		return filepath.Base(dir)
+	}
+	branch, _ := resolver.CurrentBranch(dir, &resolver.RealCommandRunner{})
+	return worktree.QualifiedName(repo, worktree.Worktree{Path: dir, Branch: branch})
 }
 
 // osDirLister adapts browser.ListDirectories to the tui.DirLister interface.
@@ -268,37 +376,86 @@ func (o *osDirLister) ListDirectories(path string, showHidden bool) ([]browser.D
 	return browser.ListDirectories(path, showHidden)
 }
 
+// tuiRefreshInterval is how often the TUI re-lists sessions to pick up
+// changes made outside Portal.
+const tuiRefreshInterval = 2 * time.Second
+
 // openTUI launches the interactive session picker with an optional initial filter.
-func openTUI(initialFilter string, command []string) error {
-	client := tmux.NewClient(&tmux.RealCommander{})
-	gitResolver := &resolverAdapter{}
+func openTUI(initialFilter string, command []string, cfg config.Config) error {
+	b := backend()
+	gitResolver := &resolverAdapter{useGitRoot: cfg.Git.ResolveRoot}
 	gen := session.NewNanoIDGenerator()
 
 	store, err := loadProjectStore()
 	if err != nil {
 		return err
 	}
+	store.WithOrder(project.Order(cfg.Projects.Order))
+
+	reg, err := loadSessionRegistry()
+	if err != nil {
+		return err
+	}
+	tracker := registry.NewTracker(b, reg)
+
+	aliases, err := loadAliasStore()
+	if err != nil {
+		return err
+	}
 
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to determine working directory: %w", err)
 	}
 
-	m := tui.New(client,
-		tui.WithKiller(client),
-		tui.WithRenamer(client),
+	scanCtx, cancelScan := context.WithCancel(context.Background())
+	defer cancelScan()
+
+	creator := newSessionCreator(gitResolver, store, b, gen, cfg).WithRegistry(reg)
+	opts := []tui.Option{
+		tui.WithKiller(tracker),
+		tui.WithRenamer(tracker),
+		tui.WithRefreshInterval(tuiRefreshInterval),
+		tui.WithSessionRegistry(reg),
 		tui.WithProjectStore(store),
-		tui.WithSessionCreator(session.NewSessionCreator(gitResolver, store, client, gen)),
+		tui.WithProjectEditor(store, aliases),
+		tui.WithSessionCreator(creator),
 		tui.WithDirLister(&osDirLister{}, cwd),
-	)
+		tui.WithShowHidden(cfg.Browser.ShowHidden),
+		tui.WithColors(cfg.TUI.Colors),
+	}
+	if client, ok := mux.TmuxClient(b); ok {
+		layouts, err := buildLayoutApplier(client)
+		if err != nil {
+			return err
+		}
+		creator.WithLayouts(layouts)
+		opts = append(opts, tui.WithDetacher(client), tui.WithPaneCapturer(client))
+
+		snapshots, err := loadSnapshotStore()
+		if err != nil {
+			return err
+		}
+		autoSnapshot(cfg, client)
+		opts = append(opts, tui.WithRestorer(snapshot.NewRestorer(snapshots, client, cfg.Snapshot.RestoreCommands)))
+	}
+	opts = append(opts, tui.WithWorktrees(worktree.NewManager(&resolver.RealCommandRunner{})))
+	if cfg.Git.ShowStatus {
+		opts = append(opts, tui.WithGitStatus(gitstatus.NewCache(&resolver.RealCommandRunner{})))
+	}
+	if cfg.Discovery.OnStartup && len(cfg.Discovery.Roots) > 0 {
+		opts = append(opts, tui.WithProjectScanner(&backgroundScanner{ctx: scanCtx, opts: scanOptions(cfg, nil), store: store}))
+	}
+
+	m := tui.New(b, opts...)
 	if len(command) > 0 {
 		m = m.WithCommand(command)
 	}
 	if initialFilter != "" {
 		m = m.WithInitialFilter(initialFilter)
 	}
-	if tmux.InsideTmux() {
-		sessionName, err := client.CurrentSessionName()
+	if b.Inside() {
+		sessionName, err := b.CurrentSessionName()
 		if err == nil && sessionName != "" {
 			m = m.WithInsideTmux(sessionName)
 		}
@@ -306,6 +463,7 @@ func openTUI(initialFilter string, command []string) error {
 	p := tea.NewProgram(m, tea.WithAltScreen())
 
 	finalModel, err := p.Run()
+	cancelScan()
 	if err != nil {
 		return err
 	}
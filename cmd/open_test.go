@@ -247,11 +247,11 @@ func TestPathOpener(t *testing.T) {
 		execer := &mockExecer{}
 
 		opener := &PathOpener{
-			insideTmux: true,
-			creator:    creator,
-			switcher:   switcher,
-			qs:         qs,
-			execer:     execer,
+			insideMux: true,
+			creator:   creator,
+			switcher:  switcher,
+			qs:        qs,
+			execer:    execer,
 		}
 
 		err := opener.Open("/home/user/project", nil)
@@ -289,12 +289,12 @@ func TestPathOpener(t *testing.T) {
 		execer := &mockExecer{}
 
 		opener := &PathOpener{
-			insideTmux: false,
-			creator:    creator,
-			switcher:   switcher,
-			qs:         qs,
-			execer:     execer,
-			tmuxPath:   "/usr/bin/tmux",
+			insideMux: false,
+			creator:   creator,
+			switcher:  switcher,
+			qs:        qs,
+			execer:    execer,
+			muxPath:   "/usr/bin/tmux",
 		}
 
 		err := opener.Open("/home/user/project", nil)
@@ -333,11 +333,11 @@ func TestPathOpener(t *testing.T) {
 		switcher := &mockSwitchClient{}
 
 		opener := &PathOpener{
-			insideTmux: true,
-			creator:    creator,
-			switcher:   switcher,
-			qs:         &mockQuickStarter{},
-			execer:     &mockExecer{},
+			insideMux: true,
+			creator:   creator,
+			switcher:  switcher,
+			qs:        &mockQuickStarter{},
+			execer:    &mockExecer{},
 		}
 
 		err := opener.Open("/some/dir", nil)
@@ -356,11 +356,11 @@ func TestPathOpener(t *testing.T) {
 		switcher := &mockSwitchClient{}
 
 		opener := &PathOpener{
-			insideTmux: true,
-			creator:    creator,
-			switcher:   switcher,
-			qs:         &mockQuickStarter{},
-			execer:     &mockExecer{},
+			insideMux: true,
+			creator:   creator,
+			switcher:  switcher,
+			qs:        &mockQuickStarter{},
+			execer:    &mockExecer{},
 		}
 
 		err := opener.Open("/some/dir", nil)
@@ -380,11 +380,11 @@ func TestPathOpener(t *testing.T) {
 		switcher := &mockSwitchClient{err: fmt.Errorf("switch failed")}
 
 		opener := &PathOpener{
-			insideTmux: true,
-			creator:    creator,
-			switcher:   switcher,
-			qs:         &mockQuickStarter{},
-			execer:     &mockExecer{},
+			insideMux: true,
+			creator:   creator,
+			switcher:  switcher,
+			qs:        &mockQuickStarter{},
+			execer:    &mockExecer{},
 		}
 
 		err := opener.Open("/some/dir", nil)
@@ -399,11 +399,11 @@ func TestPathOpener(t *testing.T) {
 		switcher := &mockSwitchClient{}
 
 		opener := &PathOpener{
-			insideTmux: true,
-			creator:    creator,
-			switcher:   switcher,
-			qs:         &mockQuickStarter{},
-			execer:     &mockExecer{},
+			insideMux: true,
+			creator:   creator,
+			switcher:  switcher,
+			qs:        &mockQuickStarter{},
+			execer:    &mockExecer{},
 		}
 
 		command := []string{"claude", "--resume"}
@@ -434,12 +434,12 @@ func TestPathOpener(t *testing.T) {
 		execer := &mockExecer{}
 
 		opener := &PathOpener{
-			insideTmux: false,
-			creator:    &mockSessionCreator{},
-			switcher:   &mockSwitchClient{},
-			qs:         qs,
-			execer:     execer,
-			tmuxPath:   "/usr/bin/tmux",
+			insideMux: false,
+			creator:   &mockSessionCreator{},
+			switcher:  &mockSwitchClient{},
+			qs:        qs,
+			execer:    execer,
+			muxPath:   "/usr/bin/tmux",
 		}
 
 		command := []string{"claude", "--resume"}
@@ -463,12 +463,12 @@ func TestPathOpener(t *testing.T) {
 		qs := &mockQuickStarter{err: fmt.Errorf("git error")}
 
 		opener := &PathOpener{
-			insideTmux: false,
-			creator:    &mockSessionCreator{},
-			switcher:   &mockSwitchClient{},
-			qs:         qs,
-			execer:     &mockExecer{},
-			tmuxPath:   "/usr/bin/tmux",
+			insideMux: false,
+			creator:   &mockSessionCreator{},
+			switcher:  &mockSwitchClient{},
+			qs:        qs,
+			execer:    &mockExecer{},
+			muxPath:   "/usr/bin/tmux",
 		}
 
 		err := opener.Open("/some/dir", nil)
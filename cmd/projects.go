@@ -0,0 +1,613 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/leeovery/portal/internal/config"
+	"github.com/leeovery/portal/internal/discovery"
+	"github.com/leeovery/portal/internal/fuzzy"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/resolver"
+	"github.com/leeovery/portal/internal/session"
+	"github.com/leeovery/portal/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+// projectsDeps holds injectable dependencies for the projects commands.
+// When nil, real implementations are used.
+var projectsDeps *ProjectsDeps
+
+// ProjectsDeps allows injecting dependencies for testing.
+type ProjectsDeps struct {
+	Git session.GitResolver
+}
+
+// buildProjectsGitResolver returns the resolver projects add uses to find a
+// directory's project root, honouring the git.resolve_root setting.
+func buildProjectsGitResolver(cfg config.Config) session.GitResolver {
+	if projectsDeps != nil {
+		return projectsDeps.Git
+	}
+	return &resolverAdapter{useGitRoot: cfg.Git.ResolveRoot}
+}
+
+// ListedProject is the JSON representation of a project in projects list
+// and projects show.
+type ListedProject struct {
+	Name     string    `json:"name"`
+	Path     string    `json:"path"`
+	Tags     []string  `json:"tags"`
+	LastUsed time.Time `json:"last_used"`
+	UseCount int       `json:"use_count"`
+}
+
+// newListedProject converts a stored project for output.
+func newListedProject(p project.Project) ListedProject {
+	tags := p.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return ListedProject{
+		Name:     p.Name,
+		Path:     p.Path,
+		Tags:     tags,
+		LastUsed: p.LastUsed,
+		UseCount: p.UseCount,
+	}
+}
+
+// writeJSON writes v as indented JSON followed by a newline.
+func writeJSON(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+var projectsCmd = &cobra.Command{
+	Use:   "projects",
+	Short: "Manage remembered projects",
+	Long: `Manage remembered projects. Commands that take a project accept its path,
+its name, or a fuzzy match for its name when only one project matches best.`,
+}
+
+var projectsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List remembered projects",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonFlag, _ := cmd.Flags().GetBool("json")
+
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		store, err := loadProjectStore()
+		if err != nil {
+			return err
+		}
+
+		projects, err := store.WithOrder(project.Order(cfg.Projects.Order)).List()
+		if err != nil {
+			return fmt.Errorf("failed to load projects: %w", err)
+		}
+
+		w := cmd.OutOrStdout()
+		if jsonFlag {
+			listed := make([]ListedProject, len(projects))
+			for i, p := range projects {
+				listed[i] = newListedProject(p)
+			}
+			return writeJSON(w, listed)
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, p := range projects {
+			if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\n", p.Name, p.Path, project.FormatTags(p.Tags)); err != nil {
+				return err
+			}
+		}
+		return tw.Flush()
+	},
+}
+
+var projectsShowCmd = &cobra.Command{
+	Use:   "show [project]",
+	Short: "Show a project's details",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonFlag, _ := cmd.Flags().GetBool("json")
+
+		_, p, err := findProjectArg(args[0])
+		if err != nil {
+			return err
+		}
+
+		w := cmd.OutOrStdout()
+		if jsonFlag {
+			return writeJSON(w, newListedProject(p))
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+		for _, row := range [][2]string{
+			{"name:", p.Name},
+			{"path:", p.Path},
+			{"tags:", project.FormatTags(p.Tags)},
+			{"last used:", p.LastUsed.Local().Format(time.RFC3339)},
+			{"uses:", fmt.Sprint(p.UseCount)},
+		} {
+			if _, err := fmt.Fprintf(tw, "%s\t%s\n", row[0], row[1]); err != nil {
+				return err
+			}
+		}
+		return tw.Flush()
+	},
+}
+
+var projectsAddCmd = &cobra.Command{
+	Use:   "add [path...]",
+	Short: "Remember directories as projects",
+	Long: `Remember each path, or the current directory, as a project. Paths resolve
+to their git repository root as when opening a session, unless
+git.resolve_root is off. The name defaults to the directory's base name;
+a project that is already remembered keeps its name. Adding a project does
+not count as opening it, so it does not change the frecency order.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		nameFlag, _ := cmd.Flags().GetString("name")
+		tagsFlag, _ := cmd.Flags().GetStringSlice("tag")
+
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+		if nameFlag != "" && len(args) > 1 {
+			return NewUsageError("--name can only be used with a single path")
+		}
+
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		git := buildProjectsGitResolver(cfg)
+
+		store, err := loadProjectStore()
+		if err != nil {
+			return err
+		}
+
+		projects, err := store.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load projects: %w", err)
+		}
+
+		w := cmd.OutOrStdout()
+		for _, arg := range args {
+			dir, err := resolver.ResolvePath(arg)
+			if err != nil {
+				return err
+			}
+			root, err := git.Resolve(dir)
+			if err != nil {
+				return fmt.Errorf("failed to resolve directory: %w", err)
+			}
+
+			name := nameFlag
+			var tags []string
+			if i := slices.IndexFunc(projects, func(p project.Project) bool { return p.Path == root }); i >= 0 {
+				tags = projects[i].Tags
+				if name == "" {
+					name = projects[i].Name
+				}
+			}
+			if name == "" {
+				name = filepath.Base(root)
+			}
+
+			if err := store.Add(root, name); err != nil {
+				return err
+			}
+			if len(tagsFlag) > 0 {
+				if err := store.SetTags(root, append(slices.Clone(tags), tagsFlag...)); err != nil {
+					return err
+				}
+			}
+
+			if _, err := fmt.Fprintf(w, "Added project: %s (%s)\n", name, root); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
+var projectsRmCmd = &cobra.Command{
+	Use:   "rm [project...]",
+	Short: "Forget projects",
+	Long: `Forget projects. Their directories are left alone, except that --worktree
+also deletes projects that are linked git worktrees with git worktree remove.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		worktreeFlag, _ := cmd.Flags().GetBool("worktree")
+
+		store, err := loadProjectStore()
+		if err != nil {
+			return err
+		}
+
+		projects, err := store.List()
+		if err != nil {
+			return fmt.Errorf("failed to load projects: %w", err)
+		}
+
+		// Find every project before removing any, so a typo removes nothing.
+		var targets []project.Project
+		for _, arg := range args {
+			p, err := findProject(projects, arg)
+			if err != nil {
+				return err
+			}
+			targets = append(targets, p)
+		}
+
+		w := cmd.OutOrStdout()
+		for _, p := range targets {
+			// Delete the worktree first, so a project is only forgotten once
+			// its directory is gone.
+			_, isWorktree := worktree.Info(p.Path)
+			deleteWorktree := isWorktree && worktreeFlag
+			if deleteWorktree {
+				if err := worktree.Remove(p.Path, &resolver.RealCommandRunner{}); err != nil {
+					return err
+				}
+			}
+
+			if err := store.Remove(p.Path); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "Removed project: %s (%s)\n", p.Name, p.Path); err != nil {
+				return err
+			}
+
+			switch {
+			case deleteWorktree:
+				if _, err := fmt.Fprintf(w, "Deleted worktree: %s\n", p.Path); err != nil {
+					return err
+				}
+			case isWorktree:
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%s is a git worktree; pass --worktree to delete it too\n", p.Name)
+			}
+		}
+
+		return nil
+	},
+}
+
+var projectsRenameCmd = &cobra.Command{
+	Use:   "rename [project] [new-name]",
+	Short: "Rename a project",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		newName := strings.TrimSpace(args[1])
+		if newName == "" {
+			return NewUsageError("project name cannot be empty")
+		}
+
+		store, p, err := findProjectArg(args[0])
+		if err != nil {
+			return err
+		}
+
+		return store.Rename(p.Path, newName)
+	},
+}
+
+var projectsTouchCmd = &cobra.Command{
+	Use:   "touch [project...]",
+	Short: "Record a use of projects now, as if they were opened",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, arg := range args {
+			store, p, err := findProjectArg(arg)
+			if err != nil {
+				return err
+			}
+			if err := store.Upsert(p.Path, p.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var projectsScanCmd = &cobra.Command{
+	Use:   "scan [root...]",
+	Short: "Discover projects under source roots and remember them",
+	Long: `Search each root, or the configured discovery.roots, for git repositories,
+worktrees, bare repositories and directories holding a project marker file
+such as go.mod or package.json, and remember the ones not already known.
+Directories matching discovery.ignore are skipped and the search stops at
+discovery.max_depth levels below each root. Press Ctrl+C to stop early;
+projects found so far are still added.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		depthFlag, _ := cmd.Flags().GetInt("depth")
+		dryRunFlag, _ := cmd.Flags().GetBool("dry-run")
+		timeoutFlag, _ := cmd.Flags().GetDuration("timeout")
+
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 && len(cfg.Discovery.Roots) == 0 {
+			return NewUsageError("no roots to scan: pass a directory or set discovery.roots in the config")
+		}
+		if depthFlag < 0 {
+			return NewUsageError("--depth must not be negative")
+		}
+
+		opts := scanOptions(cfg, args)
+		if depthFlag > 0 {
+			opts.MaxDepth = depthFlag
+		}
+
+		store, err := loadProjectStore()
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		if timeoutFlag > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeoutFlag)
+			defer cancel()
+		}
+
+		res, scanErr := discovery.Scan(ctx, opts)
+
+		candidates := discoveredProjects(res.Projects)
+		var added []project.Project
+		if dryRunFlag {
+			added, err = unknownProjects(store, candidates)
+		} else {
+			added, err = store.Import(candidates)
+		}
+		if err != nil {
+			return err
+		}
+
+		w := cmd.OutOrStdout()
+		verb := "Added"
+		if dryRunFlag {
+			verb = "Would add"
+		}
+		for _, p := range added {
+			if _, err := fmt.Fprintf(w, "%s project: %s (%s)\n", verb, p.Name, p.Path); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "Scanned %d directories: found %d projects, %d new\n", res.Visited, len(res.Projects), len(added)); err != nil {
+			return err
+		}
+
+		errOut := cmd.ErrOrStderr()
+		if res.Truncated {
+			_, _ = fmt.Fprintf(errOut, "warning: scan stopped after %d directories (discovery.max_dirs)\n", res.Visited)
+		}
+		switch {
+		case errors.Is(scanErr, context.DeadlineExceeded):
+			_, _ = fmt.Fprintf(errOut, "warning: scan stopped after %s (--timeout)\n", timeoutFlag)
+		case scanErr != nil:
+			return fmt.Errorf("scan interrupted: %w", scanErr)
+		}
+
+		return nil
+	},
+}
+
+// scanOptions returns the discovery options from cfg, searching roots
+// instead of the configured roots when any are given.
+func scanOptions(cfg config.Config, roots []string) discovery.Options {
+	if len(roots) == 0 {
+		roots = cfg.Discovery.Roots
+	}
+	normalised := make([]string, len(roots))
+	for i, root := range roots {
+		normalised[i] = resolver.NormalisePath(root)
+	}
+	return discovery.Options{
+		Roots:    normalised,
+		MaxDepth: cfg.Discovery.MaxDepth,
+		MaxDirs:  cfg.Discovery.MaxDirs,
+		Markers:  cfg.Discovery.Markers,
+		Ignore:   cfg.Discovery.Ignore,
+	}
+}
+
+// discoveredProjects converts discovered directories to projects named
+// after the directory, without the .git suffix of bare repositories.
+func discoveredProjects(found []discovery.Found) []project.Project {
+	projects := make([]project.Project, len(found))
+	for i, f := range found {
+		name := filepath.Base(f.Path)
+		if trimmed := strings.TrimSuffix(name, ".git"); trimmed != "" {
+			name = trimmed
+		}
+		projects[i] = project.Project{Path: f.Path, Name: name}
+	}
+	return projects
+}
+
+// unknownProjects returns the candidates not already in store.
+func unknownProjects(store *project.Store, candidates []project.Project) ([]project.Project, error) {
+	projects, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load projects: %w", err)
+	}
+	return slices.DeleteFunc(slices.Clone(candidates), func(c project.Project) bool {
+		return slices.ContainsFunc(projects, func(p project.Project) bool { return p.Path == c.Path })
+	}), nil
+}
+
+// backgroundScanTimeout bounds the TUI's startup scan.
+const backgroundScanTimeout = 30 * time.Second
+
+// backgroundScanner adapts a discovery scan to tui.ProjectScanner for the
+// TUI's startup scan. It stops when ctx is cancelled or after
+// backgroundScanTimeout, still adding whatever it found.
+type backgroundScanner struct {
+	ctx   context.Context
+	opts  discovery.Options
+	store *project.Store
+}
+
+// Scan discovers projects and adds the new ones to the store.
+func (s *backgroundScanner) Scan() (int, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, backgroundScanTimeout)
+	defer cancel()
+
+	res, _ := discovery.Scan(ctx, s.opts)
+	added, err := s.store.Import(discoveredProjects(res.Projects))
+	return len(added), err
+}
+
+var projectsTagCmd = &cobra.Command{
+	Use:   "tag [project] [tags...]",
+	Short: "Add tags to a project, or list its tags",
+	Long: `Add tags to a project. Without tags, prints the project's tags one per
+line. Tags may be separated by spaces or commas and a leading # is optional.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, p, err := findProjectArg(args[0])
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			for _, tag := range p.Tags {
+				if _, err := fmt.Fprintln(cmd.OutOrStdout(), tag); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		return store.SetTags(p.Path, append(slices.Clone(p.Tags), args[1:]...))
+	},
+}
+
+var projectsUntagCmd = &cobra.Command{
+	Use:   "untag [project] [tags...]",
+	Short: "Remove tags from a project",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, p, err := findProjectArg(args[0])
+		if err != nil {
+			return err
+		}
+
+		remove := project.NormalizeTags(args[1:])
+		kept := slices.DeleteFunc(slices.Clone(p.Tags), func(tag string) bool {
+			return slices.Contains(remove, tag)
+		})
+		return store.SetTags(p.Path, kept)
+	},
+}
+
+// findProjectArg loads the project store and finds the project named by arg.
+func findProjectArg(arg string) (*project.Store, project.Project, error) {
+	store, err := loadProjectStore()
+	if err != nil {
+		return nil, project.Project{}, err
+	}
+
+	projects, err := store.List()
+	if err != nil {
+		return nil, project.Project{}, fmt.Errorf("failed to load projects: %w", err)
+	}
+
+	p, err := findProject(projects, arg)
+	if err != nil {
+		return nil, project.Project{}, err
+	}
+	return store, p, nil
+}
+
+// findProject returns the project whose path or name is arg. Path-like
+// arguments are normalised and matched against project paths. Other
+// arguments match a project's exact name, or failing that the best fuzzy
+// match for its name; a tie for best is ambiguous.
+func findProject(projects []project.Project, arg string) (project.Project, error) {
+	if resolver.IsPathArgument(arg) {
+		path := resolver.NormalisePath(arg)
+		for _, p := range projects {
+			if p.Path == path {
+				return p, nil
+			}
+		}
+		return project.Project{}, fmt.Errorf("project not found: %s", path)
+	}
+
+	var matches []project.Project
+	for _, p := range projects {
+		if p.Name == arg {
+			matches = append(matches, p)
+		}
+	}
+	if len(matches) == 0 {
+		ranked := fuzzy.Rank(projects, arg, func(p project.Project) string { return p.Name })
+		for _, r := range ranked {
+			if r.Score == ranked[0].Score {
+				matches = append(matches, r.Item)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return project.Project{}, fmt.Errorf("project not found: %s", arg)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, len(matches))
+		for i, p := range matches {
+			names[i] = fmt.Sprintf("%s (%s)", p.Name, p.Path)
+		}
+		return project.Project{}, fmt.Errorf("project %q is ambiguous; matches %s; use its path", arg, strings.Join(names, ", "))
+	}
+}
+
+func init() {
+	projectsListCmd.Flags().Bool("json", false, "Output projects as a JSON array")
+	projectsShowCmd.Flags().Bool("json", false, "Output the project as a JSON object")
+	projectsAddCmd.Flags().String("name", "", "Name for the project (single path only)")
+	projectsAddCmd.Flags().StringSlice("tag", nil, "Tag to add to each project; repeatable")
+	projectsRmCmd.Flags().Bool("worktree", false, "Also delete projects that are git worktrees")
+	projectsScanCmd.Flags().Int("depth", 0, "Levels below each root to search (default discovery.max_depth)")
+	projectsScanCmd.Flags().BoolP("dry-run", "n", false, "Print the projects that would be added without adding them")
+	projectsScanCmd.Flags().Duration("timeout", 0, "Stop scanning after this long, e.g. 30s")
+
+	projectsCmd.AddCommand(projectsListCmd)
+	projectsCmd.AddCommand(projectsShowCmd)
+	projectsCmd.AddCommand(projectsAddCmd)
+	projectsCmd.AddCommand(projectsRmCmd)
+	projectsCmd.AddCommand(projectsRenameCmd)
+	projectsCmd.AddCommand(projectsTouchCmd)
+	projectsCmd.AddCommand(projectsScanCmd)
+	projectsCmd.AddCommand(projectsTagCmd)
+	projectsCmd.AddCommand(projectsUntagCmd)
+	rootCmd.AddCommand(projectsCmd)
+}
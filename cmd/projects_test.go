@@ -0,0 +1,613 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/project"
+)
+
+// writeProjectsFile points PORTAL_PROJECTS_FILE at a temp file holding content.
+func writeProjectsFile(t *testing.T, content string) string {
+	t.Helper()
+	projectsFile := filepath.Join(t.TempDir(), "projects.json")
+	t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+	if err := os.WriteFile(projectsFile, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	return projectsFile
+}
+
+// loadTags returns the tags stored for the project at path.
+func loadTags(t *testing.T, projectsFile, path string) []string {
+	t.Helper()
+	projects, err := project.NewStore(projectsFile).Load()
+	if err != nil {
+		t.Fatalf("failed to load projects: %v", err)
+	}
+	for _, p := range projects {
+		if p.Path == path {
+			return p.Tags
+		}
+	}
+	t.Fatalf("project %s not found", path)
+	return nil
+}
+
+func TestProjectsTagCommand(t *testing.T) {
+	const content = `{"projects":[
+		{"path":"/code/api","name":"api","last_used":"2026-01-01T00:00:00Z","tags":["work"]},
+		{"path":"/code/web","name":"web","last_used":"2026-01-01T00:00:00Z"},
+		{"path":"/other/web","name":"web","last_used":"2026-01-01T00:00:00Z"}
+	]}`
+
+	t.Run("adds tags to a project by name", func(t *testing.T) {
+		projectsFile := writeProjectsFile(t, content)
+
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"projects", "tag", "api", "#Client,oss"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := loadTags(t, projectsFile, "/code/api"), []string{"client", "oss", "work"}; !slices.Equal(got, want) {
+			t.Errorf("tags = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("adds tags to a project by path", func(t *testing.T) {
+		projectsFile := writeProjectsFile(t, content)
+
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"projects", "tag", "/other/web", "client"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := loadTags(t, projectsFile, "/other/web"); !slices.Equal(got, []string{"client"}) {
+			t.Errorf("tags = %v, want [client]", got)
+		}
+		if got := loadTags(t, projectsFile, "/code/web"); got != nil {
+			t.Errorf("other project's tags = %v, want none", got)
+		}
+	})
+
+	t.Run("lists tags without tag arguments", func(t *testing.T) {
+		writeProjectsFile(t, content)
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"projects", "tag", "api"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if buf.String() != "work\n" {
+			t.Errorf("output = %q, want %q", buf.String(), "work\n")
+		}
+	})
+
+	t.Run("ambiguous name is an error", func(t *testing.T) {
+		writeProjectsFile(t, content)
+
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"projects", "tag", "web", "client"})
+		err := rootCmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), "ambiguous") {
+			t.Errorf("error = %v, want ambiguous name error", err)
+		}
+	})
+
+	t.Run("unknown project is an error", func(t *testing.T) {
+		writeProjectsFile(t, content)
+
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"projects", "tag", "missing", "client"})
+		err := rootCmd.Execute()
+		if err == nil || err.Error() != "project not found: missing" {
+			t.Errorf("error = %v, want project not found", err)
+		}
+	})
+}
+
+func TestProjectsUntagCommand(t *testing.T) {
+	projectsFile := writeProjectsFile(t, `{"projects":[
+		{"path":"/code/api","name":"api","last_used":"2026-01-01T00:00:00Z","tags":["client","oss","work"]}
+	]}`)
+
+	resetRootCmd()
+	rootCmd.SetArgs([]string{"projects", "untag", "api", "#oss", "missing"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := loadTags(t, projectsFile, "/code/api"), []string{"client", "work"}; !slices.Equal(got, want) {
+		t.Errorf("tags = %v, want %v", got, want)
+	}
+}
+
+// mockProjectsGit implements session.GitResolver, resolving directories to
+// the roots in its map and leaving others unchanged.
+type mockProjectsGit struct {
+	roots map[string]string
+}
+
+func (m *mockProjectsGit) Resolve(dir string) (string, error) {
+	if root, ok := m.roots[dir]; ok {
+		return root, nil
+	}
+	return dir, nil
+}
+
+// loadProjects returns every project in the store file.
+func loadProjects(t *testing.T, projectsFile string) []project.Project {
+	t.Helper()
+	projects, err := project.NewStore(projectsFile).Load()
+	if err != nil {
+		t.Fatalf("failed to load projects: %v", err)
+	}
+	return projects
+}
+
+// writeAlphabeticalConfig points PORTAL_CONFIG_FILE at a config listing
+// projects alphabetically.
+func writeAlphabeticalConfig(t *testing.T) {
+	t.Helper()
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configFile, []byte(`{"projects":{"order":"alphabetical"}}`), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("PORTAL_CONFIG_FILE", configFile)
+}
+
+const projectsListContent = `{"projects":[
+	{"path":"/code/web","name":"web","last_used":"2026-01-01T00:00:00Z"},
+	{"path":"/code/api","name":"api","last_used":"2026-02-01T00:00:00Z","use_count":3,"tags":["client"]}
+]}`
+
+func TestProjectsListCommand(t *testing.T) {
+	t.Run("lists projects in the configured order", func(t *testing.T) {
+		writeProjectsFile(t, projectsListContent)
+		writeAlphabeticalConfig(t)
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"projects", "list"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := "api  /code/api  #client\nweb  /code/web  \n"
+		if buf.String() != want {
+			t.Errorf("output = %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("outputs JSON", func(t *testing.T) {
+		writeProjectsFile(t, projectsListContent)
+		writeAlphabeticalConfig(t)
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"projects", "list", "--json"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var listed []ListedProject
+		if err := json.Unmarshal(buf.Bytes(), &listed); err != nil {
+			t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+		}
+		if len(listed) != 2 || listed[0].Name != "api" || listed[0].UseCount != 3 || !slices.Equal(listed[0].Tags, []string{"client"}) {
+			t.Errorf("listed = %+v", listed)
+		}
+		if listed[1].Tags == nil {
+			t.Error("untagged project should have an empty tags array, not null")
+		}
+	})
+
+	t.Run("empty store outputs an empty JSON array", func(t *testing.T) {
+		t.Setenv("PORTAL_PROJECTS_FILE", filepath.Join(t.TempDir(), "projects.json"))
+		t.Setenv("PORTAL_CONFIG_FILE", filepath.Join(t.TempDir(), "config.json"))
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"projects", "list", "--json"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.String() != "[]\n" {
+			t.Errorf("output = %q, want %q", buf.String(), "[]\n")
+		}
+	})
+}
+
+func TestProjectsShowCommand(t *testing.T) {
+	t.Run("shows details", func(t *testing.T) {
+		writeProjectsFile(t, projectsListContent)
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"projects", "show", "api"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, want := range []string{"name:      api\n", "path:      /code/api\n", "tags:      #client\n", "uses:      3\n"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("output missing %q:\n%s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("outputs JSON", func(t *testing.T) {
+		writeProjectsFile(t, projectsListContent)
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"projects", "show", "--json", "/code/web"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var p ListedProject
+		if err := json.Unmarshal(buf.Bytes(), &p); err != nil {
+			t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+		}
+		if p.Name != "web" || p.Path != "/code/web" || p.UseCount != 1 {
+			t.Errorf("project = %+v", p)
+		}
+	})
+}
+
+func TestProjectsAddCommand(t *testing.T) {
+	t.Run("adds each path at its git root", func(t *testing.T) {
+		projectsFile := filepath.Join(t.TempDir(), "projects.json")
+		t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+		t.Setenv("PORTAL_CONFIG_FILE", filepath.Join(t.TempDir(), "config.json"))
+
+		repo := t.TempDir()
+		sub := filepath.Join(repo, "src")
+		other := t.TempDir()
+		if err := os.Mkdir(sub, 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		projectsDeps = &ProjectsDeps{Git: &mockProjectsGit{roots: map[string]string{sub: repo}}}
+		t.Cleanup(func() { projectsDeps = nil })
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"projects", "add", sub, other, "--tag", "work"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := "Added project: " + filepath.Base(repo) + " (" + repo + ")\n" +
+			"Added project: " + filepath.Base(other) + " (" + other + ")\n"
+		if buf.String() != want {
+			t.Errorf("output = %q, want %q", buf.String(), want)
+		}
+
+		projects := loadProjects(t, projectsFile)
+		if len(projects) != 2 {
+			t.Fatalf("got %d projects, want 2", len(projects))
+		}
+		for _, p := range projects {
+			if !slices.Equal(p.Tags, []string{"work"}) {
+				t.Errorf("%s tags = %v, want [work]", p.Name, p.Tags)
+			}
+			if p.UseCount != 0 || !p.LastUsed.IsZero() || len(p.Visits) != 0 {
+				t.Errorf("%s should not be recorded as used: %+v", p.Name, p)
+			}
+		}
+	})
+
+	t.Run("existing project keeps its name and tags", func(t *testing.T) {
+		dir := t.TempDir()
+		projectsFile := writeProjectsFile(t, `{"projects":[{"path":"`+dir+`","name":"custom","last_used":"2026-01-01T00:00:00Z","tags":["oss"]}]}`)
+		t.Setenv("PORTAL_CONFIG_FILE", filepath.Join(t.TempDir(), "config.json"))
+		projectsDeps = &ProjectsDeps{Git: &mockProjectsGit{}}
+		t.Cleanup(func() { projectsDeps = nil })
+
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"projects", "add", dir, "--tag", "work"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		projects := loadProjects(t, projectsFile)
+		if len(projects) != 1 || projects[0].Name != "custom" || !slices.Equal(projects[0].Tags, []string{"oss", "work"}) {
+			t.Errorf("projects = %+v", projects)
+		}
+		if !projects[0].LastUsed.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) || projects[0].UseCount > 1 {
+			t.Errorf("existing project should keep its usage: %+v", projects[0])
+		}
+	})
+
+	t.Run("name flag names the project", func(t *testing.T) {
+		dir := t.TempDir()
+		projectsFile := filepath.Join(t.TempDir(), "projects.json")
+		t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+		t.Setenv("PORTAL_CONFIG_FILE", filepath.Join(t.TempDir(), "config.json"))
+		projectsDeps = &ProjectsDeps{Git: &mockProjectsGit{}}
+		t.Cleanup(func() { projectsDeps = nil })
+
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"projects", "add", dir, "--name", "named"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if projects := loadProjects(t, projectsFile); len(projects) != 1 || projects[0].Name != "named" {
+			t.Errorf("projects = %+v", projects)
+		}
+	})
+
+	t.Run("name flag with several paths is a usage error", func(t *testing.T) {
+		t.Setenv("PORTAL_PROJECTS_FILE", filepath.Join(t.TempDir(), "projects.json"))
+
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"projects", "add", t.TempDir(), t.TempDir(), "--name", "named"})
+		err := rootCmd.Execute()
+		var usageErr *UsageError
+		if !errors.As(err, &usageErr) {
+			t.Errorf("error = %v, want usage error", err)
+		}
+	})
+
+	t.Run("missing directory is an error", func(t *testing.T) {
+		projectsFile := filepath.Join(t.TempDir(), "projects.json")
+		t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+		t.Setenv("PORTAL_CONFIG_FILE", filepath.Join(t.TempDir(), "config.json"))
+
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"projects", "add", filepath.Join(t.TempDir(), "gone")})
+		if err := rootCmd.Execute(); err == nil {
+			t.Fatal("expected error for missing directory")
+		}
+		if projects := loadProjects(t, projectsFile); len(projects) != 0 {
+			t.Errorf("projects = %+v, want none", projects)
+		}
+	})
+}
+
+func TestProjectsRmCommand(t *testing.T) {
+	const content = `{"projects":[
+		{"path":"/code/api-gateway","name":"api-gateway","last_used":"2026-01-01T00:00:00Z"},
+		{"path":"/code/web","name":"web","last_used":"2026-01-01T00:00:00Z"},
+		{"path":"/code/docs","name":"docs","last_used":"2026-01-01T00:00:00Z"}
+	]}`
+
+	t.Run("removes projects by fuzzy name and path", func(t *testing.T) {
+		projectsFile := writeProjectsFile(t, content)
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"projects", "rm", "gw", "/code/docs"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := "Removed project: api-gateway (/code/api-gateway)\nRemoved project: docs (/code/docs)\n"
+		if buf.String() != want {
+			t.Errorf("output = %q, want %q", buf.String(), want)
+		}
+		if projects := loadProjects(t, projectsFile); len(projects) != 1 || projects[0].Name != "web" {
+			t.Errorf("projects = %+v, want only web", projects)
+		}
+	})
+
+	t.Run("unknown project removes nothing", func(t *testing.T) {
+		projectsFile := writeProjectsFile(t, content)
+
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"projects", "rm", "web", "missing"})
+		if err := rootCmd.Execute(); err == nil {
+			t.Fatal("expected error for unknown project")
+		}
+		if projects := loadProjects(t, projectsFile); len(projects) != 3 {
+			t.Errorf("got %d projects, want 3", len(projects))
+		}
+	})
+
+	t.Run("points out worktrees left on disk", func(t *testing.T) {
+		wt := t.TempDir()
+		if err := os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: /code/api/.git/worktrees/feature\n"), 0o644); err != nil {
+			t.Fatalf("failed to write .git: %v", err)
+		}
+		writeProjectsFile(t, `{"projects":[{"path":"`+wt+`","name":"api@feature","last_used":"2026-01-01T00:00:00Z"}]}`)
+
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(stdout)
+		rootCmd.SetErr(stderr)
+		rootCmd.SetArgs([]string{"projects", "rm", wt})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !strings.Contains(stderr.String(), "api@feature is a git worktree; pass --worktree to delete it too") {
+			t.Errorf("stderr = %q, want a worktree hint", stderr.String())
+		}
+		if _, err := os.Stat(wt); err != nil {
+			t.Errorf("worktree should be left on disk: %v", err)
+		}
+	})
+	t.Run("keeps the project when its worktree cannot be deleted", func(t *testing.T) {
+		wt := t.TempDir()
+		if err := os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: "+filepath.Join(wt, "missing", ".git", "worktrees", "feature")+"\n"), 0o644); err != nil {
+			t.Fatalf("failed to write .git: %v", err)
+		}
+		projectsFile := writeProjectsFile(t, `{"projects":[{"path":"`+wt+`","name":"api@feature","last_used":"2026-01-01T00:00:00Z"}]}`)
+
+		resetRootCmd()
+		rootCmd.SetOut(new(bytes.Buffer))
+		rootCmd.SetArgs([]string{"projects", "rm", "--worktree", wt})
+		if err := rootCmd.Execute(); err == nil {
+			t.Fatal("expected the worktree removal to fail")
+		}
+
+		if projects := loadProjects(t, projectsFile); len(projects) != 1 {
+			t.Errorf("got %d projects, want the project kept", len(projects))
+		}
+	})
+}
+
+func TestProjectsRenameCommand(t *testing.T) {
+	projectsFile := writeProjectsFile(t, projectsListContent)
+
+	resetRootCmd()
+	rootCmd.SetArgs([]string{"projects", "rename", "api", "backend"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	projects := loadProjects(t, projectsFile)
+	if i := slices.IndexFunc(projects, func(p project.Project) bool { return p.Path == "/code/api" }); projects[i].Name != "backend" {
+		t.Errorf("name = %q, want %q", projects[i].Name, "backend")
+	}
+}
+
+func TestProjectsTouchCommand(t *testing.T) {
+	projectsFile := writeProjectsFile(t, projectsListContent)
+
+	resetRootCmd()
+	rootCmd.SetArgs([]string{"projects", "touch", "api"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	projects := loadProjects(t, projectsFile)
+	i := slices.IndexFunc(projects, func(p project.Project) bool { return p.Path == "/code/api" })
+	if projects[i].UseCount != 4 || projects[i].Name != "api" {
+		t.Errorf("project = %+v, want use count 4 and name kept", projects[i])
+	}
+	if time.Since(projects[i].LastUsed) > time.Minute {
+		t.Errorf("LastUsed = %v, want now", projects[i].LastUsed)
+	}
+}
+
+func TestProjectsScanCommand(t *testing.T) {
+	// makeScanTree creates a source root holding two repositories, a Go module
+	// and a plain directory.
+	makeScanTree := func(t *testing.T) string {
+		t.Helper()
+		root := t.TempDir()
+		for _, dir := range []string{"api/.git", "web/.git", "tool", "notes"} {
+			if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+				t.Fatalf("failed to create dir: %v", err)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(root, "tool", "go.mod"), nil, 0o644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		return root
+	}
+
+	t.Run("adds discovered projects", func(t *testing.T) {
+		root := makeScanTree(t)
+		api := filepath.Join(root, "api")
+		projectsFile := writeProjectsFile(t, `{"projects":[{"path":"`+api+`","name":"backend","last_used":"2026-01-01T00:00:00Z","use_count":2}]}`)
+		t.Setenv("PORTAL_CONFIG_FILE", filepath.Join(t.TempDir(), "config.json"))
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"projects", "scan", root})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		projects := loadProjects(t, projectsFile)
+		var names []string
+		for _, p := range projects {
+			names = append(names, p.Name)
+		}
+		slices.Sort(names)
+		if want := []string{"backend", "tool", "web"}; !slices.Equal(names, want) {
+			t.Errorf("names = %v, want %v", names, want)
+		}
+		for _, p := range projects {
+			if p.Path != api && (p.UseCount != 0 || !p.LastUsed.IsZero()) {
+				t.Errorf("imported project %s has use count %d and last used %v, want none", p.Name, p.UseCount, p.LastUsed)
+			}
+		}
+
+		out := buf.String()
+		if !strings.Contains(out, "Added project: web ("+filepath.Join(root, "web")+")") {
+			t.Errorf("output missing added project:\n%s", out)
+		}
+		if !strings.Contains(out, "found 3 projects, 2 new") {
+			t.Errorf("output missing summary:\n%s", out)
+		}
+	})
+
+	t.Run("dry run adds nothing", func(t *testing.T) {
+		root := makeScanTree(t)
+		projectsFile := writeProjectsFile(t, `{"projects":[]}`)
+		t.Setenv("PORTAL_CONFIG_FILE", filepath.Join(t.TempDir(), "config.json"))
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"projects", "scan", "--dry-run", root})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := loadProjects(t, projectsFile); len(got) != 0 {
+			t.Errorf("projects = %+v, want none", got)
+		}
+		if !strings.Contains(buf.String(), "Would add project: api") {
+			t.Errorf("output missing dry-run line:\n%s", buf.String())
+		}
+	})
+
+	t.Run("scans configured roots", func(t *testing.T) {
+		root := makeScanTree(t)
+		projectsFile := writeProjectsFile(t, `{"projects":[]}`)
+		configFile := filepath.Join(t.TempDir(), "config.json")
+		t.Setenv("PORTAL_CONFIG_FILE", configFile)
+		if err := os.WriteFile(configFile, []byte(`{"discovery":{"roots":["`+root+`"]}}`), 0o644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"projects", "scan"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := loadProjects(t, projectsFile); len(got) != 3 {
+			t.Errorf("projects = %+v, want 3", got)
+		}
+	})
+
+	t.Run("no roots is a usage error", func(t *testing.T) {
+		writeProjectsFile(t, `{"projects":[]}`)
+		t.Setenv("PORTAL_CONFIG_FILE", filepath.Join(t.TempDir(), "config.json"))
+
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"projects", "scan"})
+		err := rootCmd.Execute()
+		var usageErr *UsageError
+		if !errors.As(err, &usageErr) {
+			t.Fatalf("err = %v, want a usage error", err)
+		}
+	})
+}
@@ -2,36 +2,83 @@
 package cmd
 
 import (
+	"github.com/leeovery/portal/internal/config"
+	"github.com/leeovery/portal/internal/mux"
 	"github.com/leeovery/portal/internal/tmux"
 	"github.com/spf13/cobra"
 )
 
-// skipTmuxCheck contains command names that do not require tmux.
-// If any command in the parent chain matches, the tmux check is skipped.
+// skipTmuxCheck contains command names that do not require a multiplexer.
+// If any command in the parent chain matches, the availability check is skipped.
 var skipTmuxCheck = map[string]bool{
-	"version": true,
-	"init":    true,
-	"help":    true,
-	"alias":   true,
-	"clean":   true,
+	"version":  true,
+	"init":     true,
+	"help":     true,
+	"alias":    true,
+	"clean":    true,
+	"config":   true,
+	"projects": true,
 }
 
 var rootCmd = &cobra.Command{
 	Use:   "portal",
-	Short: "An interactive session picker for tmux",
+	Short: "An interactive session picker for tmux and Zellij",
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		b, err := selectBackend(cmd)
+		if err != nil {
+			return err
+		}
+		activeBackend = b
+
 		for c := cmd; c != nil; c = c.Parent() {
 			if skipTmuxCheck[c.Name()] {
 				return nil
 			}
 		}
-		return tmux.CheckTmuxAvailable()
+		return b.Available()
 	},
 	SilenceUsage:  true,
 	SilenceErrors: true,
 }
 
+// activeBackend is the multiplexer commands drive, selected before each
+// command runs.
+var activeBackend mux.Backend
+
+// selectBackend returns the backend named by --backend, falling back to the
+// config file's multiplexer.backend. A config file that fails to load selects
+// the default backend; commands that read the config report its errors.
+func selectBackend(cmd *cobra.Command) (mux.Backend, error) {
+	name, _ := cmd.Flags().GetString("backend")
+	if name == "" {
+		if path, err := settingsFilePath(); err == nil {
+			if cfg, _, err := config.Load(path); err == nil {
+				name = cfg.Multiplexer.Backend
+			}
+		}
+	}
+
+	b, err := mux.New(name)
+	if err != nil {
+		return nil, NewUsageError(err.Error())
+	}
+	return b, nil
+}
+
+// backend returns the selected multiplexer backend, or tmux when none has
+// been selected.
+func backend() mux.Backend {
+	if activeBackend == nil {
+		return mux.NewTmux(&tmux.RealCommander{})
+	}
+	return activeBackend
+}
+
 // Execute runs the root command.
 func Execute() error {
 	return rootCmd.Execute()
 }
+
+func init() {
+	rootCmd.PersistentFlags().String("backend", "", "Multiplexer to drive: tmux or zellij (default from config, else tmux)")
+}
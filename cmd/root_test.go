@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -16,10 +17,42 @@ func resetRootCmd() {
 	_ = initCmd.Flags().Set("cmd", "x")     // reset to default; value is always valid
 	_ = listCmd.Flags().Set("short", "false") // reset list flags
 	_ = listCmd.Flags().Set("long", "false")
+	_ = listCmd.Flags().Set("json", "false")
+	_ = listCmd.Flags().Set("format", "")
+	_ = listCmd.Flags().Set("sort", "")
+	_ = listCmd.Flags().Set("filter", "")
+	for _, name := range []string{"all", "detached", "project", "except-current", "regex", "dry-run"} { // reset kill flags
+		if f := killCmd.Flags().Lookup(name); f != nil {
+			_ = f.Value.Set(f.DefValue)
+			f.Changed = false
+		}
+	}
+	_ = projectsListCmd.Flags().Set("json", "false") // reset projects flags
+	_ = projectsShowCmd.Flags().Set("json", "false")
+	_ = projectsAddCmd.Flags().Set("name", "")
+	if f := projectsAddCmd.Flags().Lookup("tag"); f != nil {
+		_ = f.Value.(interface{ Replace([]string) error }).Replace(nil)
+		f.Changed = false
+	}
+	_ = projectsRmCmd.Flags().Set("worktree", "false")
+	_ = projectsScanCmd.Flags().Set("depth", "0")
+	_ = projectsScanCmd.Flags().Set("dry-run", "false")
+	_ = projectsScanCmd.Flags().Set("timeout", "0s")
 	if f := openCmd.Flags().Lookup("exec"); f != nil { // reset exec flag
 		_ = f.Value.Set("")
 		f.Changed = false
 	}
+	for _, name := range []string{"dry-run", "yes", "projects", "duplicates", "aliases", "sessions", "records"} { // reset clean flags
+		_ = cleanCmd.Flags().Set(name, "false")
+	}
+	_ = snapshotCmd.Flags().Set("auto", "false") // reset snapshot flags
+	_ = restoreCmd.Flags().Set("only", "")
+	if f := restoreCmd.Flags().Lookup("commands"); f != nil {
+		_ = f.Value.Set("false")
+		f.Changed = false
+	}
+	_ = rootCmd.PersistentFlags().Set("backend", "") // reset backend selection
+	activeBackend = nil
 }
 
 func TestTmuxDependentCommandsFailWithoutTmux(t *testing.T) {
@@ -54,6 +87,52 @@ func TestTmuxDependentCommandsFailWithoutTmux(t *testing.T) {
 	}
 }
 
+func TestBackendSelection(t *testing.T) {
+	t.Cleanup(resetRootCmd)
+
+	t.Run("--backend checks for the selected multiplexer", func(t *testing.T) {
+		t.Setenv("PATH", "/nonexistent/path")
+
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"list", "--backend", "zellij"})
+		err := rootCmd.Execute()
+
+		want := "Portal requires zellij. Install with: brew install zellij"
+		if err == nil || err.Error() != want {
+			t.Errorf("error = %v, want %q", err, want)
+		}
+	})
+
+	t.Run("config file selects the backend", func(t *testing.T) {
+		t.Setenv("PATH", "/nonexistent/path")
+		configPath := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(configPath, []byte(`{"multiplexer": {"backend": "zellij"}}`), 0o644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		t.Setenv("PORTAL_CONFIG_FILE", configPath)
+
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"version"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if activeBackend == nil || activeBackend.Name() != "zellij" {
+			t.Errorf("active backend = %v, want zellij", activeBackend)
+		}
+	})
+
+	t.Run("unknown backend is a usage error", func(t *testing.T) {
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"list", "--backend", "screen"})
+		err := rootCmd.Execute()
+
+		var usageErr *UsageError
+		if !errors.As(err, &usageErr) {
+			t.Errorf("error = %v, want a usage error", err)
+		}
+	})
+}
+
 func TestNonTmuxCommandsWorkWithoutTmux(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/leeovery/portal/internal/config"
+	"github.com/leeovery/portal/internal/mux"
+	"github.com/leeovery/portal/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+// snapshotDeps holds injectable dependencies for the snapshot and restore
+// commands. When nil, real implementations are used.
+var snapshotDeps *SnapshotDeps
+
+// SnapshotDeps allows injecting dependencies for testing.
+type SnapshotDeps struct {
+	Panes  snapshot.PaneLister
+	Target snapshot.Target
+	Now    func() time.Time
+}
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save every session's windows, panes and directories for restore",
+	Long: `Save every running session's windows, panes, layouts, working directories
+and foreground commands, so they can be rebuilt with restore.
+
+With --auto, a snapshot is only taken when snapshot.interval is configured and
+the saved one is older than it, which suits a tmux hook or status line. It is
+also skipped when saved sessions are not running, so they are not lost before
+they are restored; a plain snapshot replaces them.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		store, err := loadSnapshotStore()
+		if err != nil {
+			return err
+		}
+
+		auto, _ := cmd.Flags().GetBool("auto")
+		if auto {
+			interval := cfg.Snapshot.AutoInterval()
+			if interval == 0 || !store.Stale(interval, snapshotNow()) {
+				return nil
+			}
+		}
+
+		panes, err := buildSnapshotPanes()
+		if err != nil {
+			return err
+		}
+
+		snap, err := snapshot.Capture(panes, snapshotNow())
+		if err != nil {
+			return err
+		}
+		if len(snap.Sessions) == 0 {
+			if auto {
+				return nil
+			}
+			return fmt.Errorf("no sessions to snapshot")
+		}
+		if auto && store.Drops(snap) {
+			return nil
+		}
+
+		if err := store.Save(snap); err != nil {
+			return err
+		}
+		if auto {
+			return nil
+		}
+
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "Saved snapshot of %d sessions to %s\n", len(snap.Sessions), store.Path())
+		return err
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Rebuild sessions from the saved snapshot",
+	Long: `Rebuild the sessions in the saved snapshot with their windows, panes,
+layouts and working directories. Sessions that are already running are skipped.
+
+--only restricts the restore to sessions whose names match a glob pattern.
+--commands re-runs each pane's recorded foreground command; it defaults to
+snapshot.restore_commands.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		only, _ := cmd.Flags().GetString("only")
+		if _, err := path.Match(only, ""); err != nil {
+			return NewUsageError(fmt.Sprintf("invalid --only pattern %q: %v", only, err))
+		}
+
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		runCommands := cfg.Snapshot.RestoreCommands
+		if cmd.Flags().Changed("commands") {
+			runCommands, _ = cmd.Flags().GetBool("commands")
+		}
+
+		store, err := loadSnapshotStore()
+		if err != nil {
+			return err
+		}
+		snap, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		sessions := snap.Match(only)
+		if len(sessions) == 0 {
+			if only != "" {
+				return fmt.Errorf("no saved sessions match %q", only)
+			}
+			return fmt.Errorf("snapshot %s has no sessions", store.Path())
+		}
+
+		target, err := buildSnapshotTarget()
+		if err != nil {
+			return err
+		}
+
+		w := cmd.OutOrStdout()
+		for _, s := range sessions {
+			err := snapshot.Restore(target, s, runCommands)
+			switch {
+			case errors.Is(err, snapshot.ErrRunning):
+				_, err = fmt.Fprintf(w, "Skipped session: %s (already running)\n", s.Name)
+			case err != nil:
+				return err
+			default:
+				_, err = fmt.Fprintf(w, "Restored session: %s\n", s.Name)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// buildSnapshotPanes returns the pane lister snapshots are captured from.
+func buildSnapshotPanes() (snapshot.PaneLister, error) {
+	if snapshotDeps != nil {
+		return snapshotDeps.Panes, nil
+	}
+	client, ok := mux.TmuxClient(backend())
+	if !ok {
+		return nil, fmt.Errorf("snapshots require the tmux backend")
+	}
+	return client, nil
+}
+
+// buildSnapshotTarget returns the tmux operations sessions are restored through.
+func buildSnapshotTarget() (snapshot.Target, error) {
+	if snapshotDeps != nil {
+		return snapshotDeps.Target, nil
+	}
+	client, ok := mux.TmuxClient(backend())
+	if !ok {
+		return nil, fmt.Errorf("snapshots require the tmux backend")
+	}
+	return client, nil
+}
+
+// snapshotNow returns the current time, or the injected clock in tests.
+func snapshotNow() time.Time {
+	if snapshotDeps != nil && snapshotDeps.Now != nil {
+		return snapshotDeps.Now()
+	}
+	return time.Now()
+}
+
+// autoSnapshot saves a snapshot when snapshot.interval is configured and the
+// saved one has gone stale. It never saves over sessions that are not running,
+// which would lose them before they are restored. Failures are ignored: a
+// missed snapshot must never stop Portal from opening.
+func autoSnapshot(cfg config.Config, panes snapshot.PaneLister) {
+	interval := cfg.Snapshot.AutoInterval()
+	if interval == 0 {
+		return
+	}
+	store, err := loadSnapshotStore()
+	if err != nil || !store.Stale(interval, snapshotNow()) {
+		return
+	}
+	snap, err := snapshot.Capture(panes, snapshotNow())
+	if err != nil || len(snap.Sessions) == 0 || store.Drops(snap) {
+		return
+	}
+	_ = store.Save(snap)
+}
+
+// loadSnapshotStore creates a snapshot store from the configured file path.
+func loadSnapshotStore() (*snapshot.Store, error) {
+	path, err := snapshotFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.NewStore(path), nil
+}
+
+// snapshotFilePath returns the path to the snapshot.json file.
+// Uses PORTAL_SNAPSHOT_FILE env var if set (for testing), otherwise
+// defaults to ~/.config/portal/snapshot.json.
+func snapshotFilePath() (string, error) {
+	return configFilePath("PORTAL_SNAPSHOT_FILE", "snapshot.json")
+}
+
+func init() {
+	snapshotCmd.Flags().Bool("auto", false, "Only snapshot when snapshot.interval has passed since the last one, silently")
+	restoreCmd.Flags().String("only", "", "Only restore sessions whose names match a glob pattern")
+	restoreCmd.Flags().Bool("commands", false, "Re-run each pane's recorded command (default: snapshot.restore_commands)")
+	rootCmd.AddCommand(snapshotCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// snapshotTmux fakes the tmux server behind snapshot and restore: list-panes
+// reports panes, has-session checks running, and new-session starts one.
+type snapshotTmux struct {
+	panes   string
+	running []string
+	created []string
+	calls   []string
+}
+
+func (s *snapshotTmux) Run(args ...string) (string, error) {
+	s.calls = append(s.calls, strings.Join(args, " "))
+	switch args[0] {
+	case "list-panes":
+		return s.panes, nil
+	case "has-session":
+		if !slices.Contains(s.running, args[2]) {
+			return "", fmt.Errorf("no such session")
+		}
+	case "new-session":
+		s.created = append(s.created, args[3])
+	case "display-message":
+		return "@0|%0", nil
+	case "new-window":
+		return "@1|%1", nil
+	case "split-window":
+		return "%2", nil
+	}
+	return "", nil
+}
+
+const snapshotPanes = "1\t1\t1\ta1,80x24,0,0,1\tnvim\teditor\t/code/api\tapi\n" +
+	"1\t1\t1\tb2,80x24,0,0,2\tzsh\tmain\t/code/web\tweb"
+
+func setupSnapshot(t *testing.T, server *snapshotTmux) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "snapshot.json")
+	t.Setenv("PORTAL_SNAPSHOT_FILE", path)
+	t.Setenv("PORTAL_CONFIG_FILE", filepath.Join(dir, "config.json"))
+
+	client := tmux.NewClient(server)
+	snapshotDeps = &SnapshotDeps{
+		Panes:  client,
+		Target: client,
+		Now:    func() time.Time { return time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC) },
+	}
+	t.Cleanup(func() { snapshotDeps = nil })
+	return path
+}
+
+func runSnapshotCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	resetRootCmd()
+	rootCmd.SetOut(buf)
+	rootCmd.SetArgs(args)
+	err := rootCmd.Execute()
+	return buf.String(), err
+}
+
+func TestSnapshotCommand(t *testing.T) {
+	t.Run("saves every session", func(t *testing.T) {
+		path := setupSnapshot(t, &snapshotTmux{panes: snapshotPanes})
+
+		out, err := runSnapshotCmd(t, "snapshot")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := "Saved snapshot of 2 sessions to " + path + "\n"; out != want {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read snapshot: %v", err)
+		}
+		if !bytes.Contains(data, []byte(`"name": "api"`)) || !bytes.Contains(data, []byte(`"command": "nvim"`)) {
+			t.Errorf("snapshot missing api and its command:\n%s", data)
+		}
+	})
+
+	t.Run("errors when no sessions are running", func(t *testing.T) {
+		setupSnapshot(t, &snapshotTmux{})
+
+		_, err := runSnapshotCmd(t, "snapshot")
+		if err == nil || err.Error() != "no sessions to snapshot" {
+			t.Errorf("err = %v, want no sessions to snapshot", err)
+		}
+	})
+
+	t.Run("auto does nothing without an interval", func(t *testing.T) {
+		server := &snapshotTmux{panes: snapshotPanes}
+		path := setupSnapshot(t, server)
+
+		out, err := runSnapshotCmd(t, "snapshot", "--auto")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if out != "" || len(server.calls) != 0 {
+			t.Errorf("output = %q, calls = %v, want nothing", out, server.calls)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("snapshot should not have been written")
+		}
+	})
+
+	t.Run("auto saves silently once the interval has passed", func(t *testing.T) {
+		path := setupSnapshot(t, &snapshotTmux{panes: snapshotPanes})
+		if err := os.WriteFile(os.Getenv("PORTAL_CONFIG_FILE"), []byte(`{"snapshot":{"interval":"15m"}}`), 0o644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+
+		out, err := runSnapshotCmd(t, "snapshot", "--auto")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if out != "" {
+			t.Errorf("output = %q, want none", out)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("snapshot should have been written: %v", err)
+		}
+	})
+}
+
+func TestAutoSnapshotKeepsSessionsThatAreNotRunning(t *testing.T) {
+	// The snapshot was saved with api and web, then the server restarted and
+	// only web came back before Portal opened.
+	setup := func(t *testing.T) string {
+		t.Helper()
+		path := setupSnapshot(t, &snapshotTmux{panes: snapshotPanes})
+		if _, err := runSnapshotCmd(t, "snapshot"); err != nil {
+			t.Fatalf("snapshot failed: %v", err)
+		}
+		old := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatalf("failed to age snapshot: %v", err)
+		}
+		snapshotDeps.Panes = tmux.NewClient(&snapshotTmux{panes: "1\t1\t1\tb2,80x24,0,0,2\tzsh\tmain\t/code/web\tweb"})
+		return path
+	}
+	assertKept := func(t *testing.T, path string) {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read snapshot: %v", err)
+		}
+		if !bytes.Contains(data, []byte(`"name": "api"`)) {
+			t.Errorf("snapshot lost api:\n%s", data)
+		}
+	}
+	interval := func(t *testing.T) {
+		t.Helper()
+		if err := os.WriteFile(os.Getenv("PORTAL_CONFIG_FILE"), []byte(`{"snapshot":{"interval":"15m"}}`), 0o644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+
+	t.Run("when opening", func(t *testing.T) {
+		path := setup(t)
+		interval(t)
+		cfg, err := loadConfig(rootCmd)
+		if err != nil {
+			t.Fatalf("failed to load config: %v", err)
+		}
+
+		autoSnapshot(cfg, snapshotDeps.Panes)
+
+		assertKept(t, path)
+	})
+
+	t.Run("with snapshot --auto", func(t *testing.T) {
+		path := setup(t)
+		interval(t)
+
+		if _, err := runSnapshotCmd(t, "snapshot", "--auto"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		assertKept(t, path)
+	})
+}
+
+func TestRestoreCommand(t *testing.T) {
+	save := func(t *testing.T) {
+		t.Helper()
+		if _, err := runSnapshotCmd(t, "snapshot"); err != nil {
+			t.Fatalf("snapshot failed: %v", err)
+		}
+	}
+
+	t.Run("restores saved sessions and skips running ones", func(t *testing.T) {
+		server := &snapshotTmux{panes: snapshotPanes, running: []string{"web"}}
+		setupSnapshot(t, server)
+		save(t)
+
+		out, err := runSnapshotCmd(t, "restore")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := "Restored session: api\nSkipped session: web (already running)\n"
+		if out != want {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+		if !slices.Equal(server.created, []string{"api"}) {
+			t.Errorf("created %v, want [api]", server.created)
+		}
+	})
+
+	t.Run("only restores matching sessions", func(t *testing.T) {
+		server := &snapshotTmux{panes: snapshotPanes}
+		setupSnapshot(t, server)
+		save(t)
+
+		out, err := runSnapshotCmd(t, "restore", "--only", "w*")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if out != "Restored session: web\n" {
+			t.Errorf("output = %q, want web restored", out)
+		}
+	})
+
+	t.Run("re-runs commands with --commands", func(t *testing.T) {
+		server := &snapshotTmux{panes: snapshotPanes}
+		setupSnapshot(t, server)
+		save(t)
+
+		if _, err := runSnapshotCmd(t, "restore", "--only", "api", "--commands"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !slices.Contains(server.calls, "send-keys -t %0 nvim Enter") {
+			t.Errorf("calls = %v, want nvim re-run", server.calls)
+		}
+	})
+
+	t.Run("reports a missing snapshot", func(t *testing.T) {
+		setupSnapshot(t, &snapshotTmux{})
+
+		_, err := runSnapshotCmd(t, "restore")
+		if err == nil || !strings.Contains(err.Error(), "no snapshot saved") {
+			t.Errorf("err = %v, want no snapshot saved", err)
+		}
+	})
+
+	t.Run("rejects an invalid pattern", func(t *testing.T) {
+		setupSnapshot(t, &snapshotTmux{})
+
+		_, err := runSnapshotCmd(t, "restore", "--only", "[")
+		if _, ok := err.(*UsageError); !ok {
+			t.Errorf("err = %v, want a usage error", err)
+		}
+	})
+
+	t.Run("errors when no sessions match", func(t *testing.T) {
+		setupSnapshot(t, &snapshotTmux{panes: snapshotPanes})
+		save(t)
+
+		_, err := runSnapshotCmd(t, "restore", "--only", "db*")
+		if err == nil || err.Error() != `no saved sessions match "db*"` {
+			t.Errorf("err = %v, want no match", err)
+		}
+	})
+}
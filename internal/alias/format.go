@@ -0,0 +1,136 @@
+package alias
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Header is the first line of an aliases file in the current format. Files
+// without it are in the original format and are migrated when loaded.
+const Header = "# portal aliases v2"
+
+// Problem is a malformed line of an aliases file.
+type Problem struct {
+	Line    int
+	Message string
+}
+
+// String formats the problem as "line N: message".
+func (p Problem) String() string {
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+// line is one line of an aliases file. Comments, blank lines and malformed
+// lines keep their text and have no name; entries keep their text until
+// they are changed.
+type line struct {
+	text string
+	name string
+	path string
+}
+
+// entryLine returns the line for an alias, escaped so it parses back intact.
+func entryLine(name, path string) line {
+	return line{text: escape(name, true) + "=" + escape(path, false), name: name, path: path}
+}
+
+// parseLine parses one line of the current format. A line is blank, a
+// comment starting with #, or name=path. A backslash escapes the character
+// after it, so names may contain \= and either side may keep \-escaped
+// leading or trailing spaces; unescaped surrounding spaces are trimmed.
+func parseLine(text string) (line, error) {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return line{text: text}, nil
+	}
+
+	rawName, rawPath, found := cutUnescaped(text, '=')
+	if !found {
+		return line{}, fmt.Errorf("expected name=path")
+	}
+	name, err := unescape(rawName)
+	if err != nil {
+		return line{}, err
+	}
+	path, err := unescape(rawPath)
+	if err != nil {
+		return line{}, err
+	}
+	if name == "" {
+		return line{}, fmt.Errorf("empty alias name")
+	}
+	if path == "" {
+		return line{}, fmt.Errorf("alias %q has an empty path", name)
+	}
+	return line{text: text, name: name, path: path}, nil
+}
+
+// parseOriginalLine parses one line of the original format, which split
+// name=path at the first = with no escaping and ignored everything else.
+// Comments and blank lines are kept; other lines without = stay as they are
+// and are reported as malformed once migrated.
+func parseOriginalLine(text string) line {
+	trimmed := strings.TrimSpace(text)
+	name, path, found := strings.Cut(trimmed, "=")
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") || !found || name == "" || path == "" {
+		return line{text: text}
+	}
+	return entryLine(name, path)
+}
+
+// cutUnescaped splits s around the first sep not preceded by a backslash.
+func cutUnescaped(s string, sep byte) (before, after string, found bool) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+// unescape trims unescaped surrounding whitespace from s and removes its
+// backslash escapes.
+func unescape(s string) (string, error) {
+	s = strings.TrimLeft(s, " \t")
+	var b strings.Builder
+	keep := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			if s[i] != ' ' && s[i] != '\t' {
+				keep = b.Len()
+			}
+			continue
+		}
+		if i == len(s)-1 {
+			return "", fmt.Errorf("trailing backslash")
+		}
+		i++
+		b.WriteByte(s[i])
+		keep = b.Len()
+	}
+	return b.String()[:keep], nil
+}
+
+// escape backslash-escapes s so unescape returns it unchanged: backslashes,
+// surrounding whitespace and, in names, = and a leading #.
+func escape(s string, name bool) string {
+	var b strings.Builder
+	last := len(strings.TrimRight(s, " \t"))
+	first := len(s) - len(strings.TrimLeft(s, " \t"))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\',
+			(c == ' ' || c == '\t') && (i < first || i >= last),
+			name && c == '=',
+			name && c == '#' && i == 0:
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
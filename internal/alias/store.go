@@ -1,14 +1,16 @@
-// Package alias provides persistence for path aliases in a flat key=value file.
+// Package alias provides persistence for path aliases in a flat name=path
+// file that keeps comments and ordering.
 package alias
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
+
+	"github.com/leeovery/portal/internal/resolver"
 )
 
 // Alias represents a single name-to-path mapping.
@@ -17,10 +19,13 @@ type Alias struct {
 	Path string
 }
 
-// Store manages persistence of alias data to a flat key=value file.
+// Store manages persistence of alias data to a flat name=path file. The file
+// keeps its comments, blank lines, order and malformed lines when saved.
 type Store struct {
-	path    string
-	aliases map[string]string
+	path     string
+	lines    []line
+	aliases  map[string]string
+	problems []Problem
 }
 
 // NewStore creates a Store that reads and writes to the given file path.
@@ -31,46 +36,110 @@ func NewStore(path string) *Store {
 	}
 }
 
-// Load reads aliases from the flat key=value file.
+// Path returns the path of the aliases file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load reads aliases from the file.
 // Returns an empty map when the file is missing or empty.
-// Duplicate keys are resolved with last-wins semantics.
+// Duplicate names are resolved with last-wins semantics. Malformed lines are
+// kept for Save and reported by Problems rather than failing the load.
+// A file in the original format is migrated: the original is copied to a .bak
+// file beside it and the file is rewritten in the current format. Migration
+// is retried on the next load if either write fails.
 func (s *Store) Load() (map[string]string, error) {
-	f, err := os.Open(s.path)
+	data, err := os.ReadFile(s.path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
+			s.lines = nil
 			s.aliases = make(map[string]string)
+			s.problems = nil
 			return s.aliases, nil
 		}
-		return nil, fmt.Errorf("failed to open aliases file: %w", err)
+		return nil, fmt.Errorf("failed to read aliases file: %w", err)
+	}
+
+	texts := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(data) == 0 {
+		texts = nil
 	}
-	defer func() { _ = f.Close() }()
+	original := len(texts) > 0 && strings.TrimSpace(texts[0]) != Header
 
-	aliases := make(map[string]string)
-	scanner := bufio.NewScanner(f)
+	s.lines = make([]line, 0, len(texts))
+	s.aliases = make(map[string]string)
+	s.problems = nil
+	if original {
+		s.lines = append(s.lines, line{text: Header})
+	}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
+	defined := make(map[string]int)
+	for i, text := range texts {
+		text = strings.TrimSuffix(text, "\r")
+		var l line
+		if original {
+			l = parseOriginalLine(text)
+		} else {
+			var parseErr error
+			if l, parseErr = parseLine(text); parseErr != nil {
+				l = line{text: text}
+				s.problems = append(s.problems, Problem{Line: i + 1, Message: parseErr.Error()})
+			}
 		}
-
-		name, path, found := strings.Cut(line, "=")
-		if !found {
-			continue
+		if l.name != "" {
+			if first, ok := defined[l.name]; ok && !original {
+				s.problems = append(s.problems, Problem{
+					Line:    i + 1,
+					Message: fmt.Sprintf("duplicate alias %q (first defined on line %d); the last one wins", l.name, first),
+				})
+			} else if !ok {
+				defined[l.name] = i + 1
+			}
+			s.aliases[l.name] = l.path
 		}
+		s.lines = append(s.lines, l)
+	}
 
-		aliases[name] = path
+	if original {
+		if err := s.migrate(data); err == nil {
+			return s.Load()
+		}
 	}
+	return s.aliases, nil
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to read aliases file: %w", err)
+// migrate backs up a file in the original format, then saves it in the
+// current one.
+func (s *Store) migrate(original []byte) error {
+	if err := os.WriteFile(s.path+".bak", original, 0o644); err != nil {
+		return fmt.Errorf("failed to back up aliases file: %w", err)
 	}
+	return s.Save()
+}
 
-	s.aliases = aliases
-	return s.aliases, nil
+// Problems returns the malformed lines found by the last Load and the
+// aliases that fail to resolve, such as cycles and unset variables, in file
+// order.
+func (s *Store) Problems() []Problem {
+	problems := slices.Clone(s.problems)
+	defining := make(map[string]int)
+	for i, l := range s.lines {
+		if l.name != "" {
+			defining[l.name] = i + 1
+		}
+	}
+	for name, lineNo := range defining {
+		if _, _, err := resolver.ExpandAlias(s, name); err != nil {
+			problems = append(problems, Problem{Line: lineNo, Message: err.Error()})
+		}
+	}
+	slices.SortStableFunc(problems, func(a, b Problem) int { return a.Line - b.Line })
+	return problems
 }
 
-// Save writes all aliases to the file in sorted key=value format.
+// Save writes the aliases file using atomic write (temp file + rename),
+// keeping the comments, order and malformed lines it was loaded with. New
+// aliases are appended at the end.
 // Creates the parent directory if it does not exist.
 func (s *Store) Save() error {
 	dir := filepath.Dir(s.path)
@@ -78,15 +147,34 @@ func (s *Store) Save() error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	sorted := s.List()
-
 	var b strings.Builder
-	for _, a := range sorted {
-		fmt.Fprintf(&b, "%s=%s\n", a.Name, a.Path)
+	if len(s.lines) == 0 || strings.TrimSpace(s.lines[0].text) != Header {
+		b.WriteString(Header + "\n")
+	}
+	for _, l := range s.lines {
+		b.WriteString(l.text + "\n")
+	}
+
+	tmp, err := os.CreateTemp(dir, "aliases-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.WriteString(b.String()); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to close temp file: %w", err)
 	}
 
-	if err := os.WriteFile(s.path, []byte(b.String()), 0o644); err != nil {
-		return fmt.Errorf("failed to write aliases file: %w", err)
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
 	}
 
 	return nil
@@ -99,16 +187,28 @@ func (s *Store) Get(name string) (string, bool) {
 }
 
 // Set adds or overwrites an alias. Each name maps to exactly one path.
+// An existing alias is updated where it is defined; a new one is appended.
 func (s *Store) Set(name, path string) {
 	s.aliases[name] = path
+	for i := len(s.lines) - 1; i >= 0; i-- {
+		if s.lines[i].name == name {
+			s.lines[i] = entryLine(name, path)
+			return
+		}
+	}
+	s.lines = append(s.lines, entryLine(name, path))
 }
 
-// Delete removes the alias with the given name.
+// Delete removes the alias with the given name, including every line that
+// defines it.
 // Returns true if the alias existed, false otherwise.
 func (s *Store) Delete(name string) bool {
 	_, ok := s.aliases[name]
 	if ok {
 		delete(s.aliases, name)
+		s.lines = slices.DeleteFunc(s.lines, func(l line) bool {
+			return l.name == name
+		})
 	}
 	return ok
 }
@@ -126,3 +226,24 @@ func (s *Store) List() []Alias {
 
 	return result
 }
+
+// Stale returns the aliases, sorted by name, whose resolved paths no longer
+// exist on disk. Aliases are resolved as they are when used, through other
+// aliases, ~ and $VARS; those that fail to resolve are reported by Problems
+// instead. Paths with permission errors are not stale.
+func (s *Store) Stale() []Alias {
+	var stale []Alias
+	for _, a := range s.List() {
+		path, ok, err := resolver.ExpandAlias(s, a.Name)
+		if err != nil {
+			continue
+		}
+		if !ok {
+			path = a.Path
+		}
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			stale = append(stale, a)
+		}
+	}
+	return stale
+}
@@ -287,8 +287,8 @@ func TestFileFormat(t *testing.T) {
 		}
 
 		content := string(data)
-		// File should be sorted by name, one per line
-		want := "aa=/Users/lee/Code/aerobid/api\nm2api=/Users/lee/Code/mac2/api\n"
+		// File starts with the format header, then one alias per line
+		want := alias.Header + "\naa=/Users/lee/Code/aerobid/api\nm2api=/Users/lee/Code/mac2/api\n"
 		if content != want {
 			t.Errorf("file content = %q, want %q", content, want)
 		}
@@ -326,3 +326,253 @@ func TestRoundTrip(t *testing.T) {
 		}
 	})
 }
+
+func TestStale(t *testing.T) {
+	t.Run("returns aliases whose paths no longer exist", func(t *testing.T) {
+		dir := t.TempDir()
+		store := alias.NewStore(filepath.Join(dir, "aliases"))
+		store.Set("home", dir)
+		store.Set("gone", filepath.Join(dir, "gone"))
+		store.Set("also-gone", filepath.Join(dir, "also-gone"))
+
+		stale := store.Stale()
+
+		if len(stale) != 2 || stale[0].Name != "also-gone" || stale[1].Name != "gone" {
+			t.Errorf("stale = %+v, want also-gone and gone", stale)
+		}
+	})
+
+	t.Run("returns nothing when every path exists", func(t *testing.T) {
+		dir := t.TempDir()
+		store := alias.NewStore(filepath.Join(dir, "aliases"))
+		store.Set("home", dir)
+
+		if stale := store.Stale(); len(stale) != 0 {
+			t.Errorf("stale = %+v, want none", stale)
+		}
+	})
+
+	t.Run("resolves chained aliases and variables before checking", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.Mkdir(filepath.Join(dir, "api"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("PORTAL_TEST_CODE", dir)
+		store := alias.NewStore(filepath.Join(dir, "aliases"))
+		store.Set("work", dir)
+		store.Set("api", "work/api")
+		store.Set("code", "$PORTAL_TEST_CODE")
+		store.Set("web", "work/web")
+
+		stale := store.Stale()
+
+		if len(stale) != 1 || stale[0].Name != "web" {
+			t.Errorf("stale = %+v, want only web", stale)
+		}
+	})
+
+	t.Run("skips aliases that fail to resolve", func(t *testing.T) {
+		dir := t.TempDir()
+		store := alias.NewStore(filepath.Join(dir, "aliases"))
+		store.Set("a", "b")
+		store.Set("b", "a")
+		store.Set("unset", "$PORTAL_TEST_UNSET/code")
+
+		if stale := store.Stale(); len(stale) != 0 {
+			t.Errorf("stale = %+v, want none", stale)
+		}
+	})
+}
+
+func TestPreservesFileLayout(t *testing.T) {
+	t.Run("keeps comments, blank lines and order when saving", func(t *testing.T) {
+		dir := t.TempDir()
+		filePath := filepath.Join(dir, "aliases")
+		content := alias.Header + "\n# work projects\nwork = /code/work\n\n# personal\nzz=/code/zz\naa=/code/aa\n"
+		if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+
+		store := alias.NewStore(filePath)
+		if _, err := store.Load(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		store.Set("zz", "/code/zz2")
+		store.Set("new", "/code/new")
+		store.Delete("aa")
+		if err := store.Save(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, _ := os.ReadFile(filePath)
+		want := alias.Header + "\n# work projects\nwork = /code/work\n\n# personal\nzz=/code/zz2\nnew=/code/new\n"
+		if string(data) != want {
+			t.Errorf("file content = %q, want %q", data, want)
+		}
+	})
+
+	t.Run("leaves no temp files behind", func(t *testing.T) {
+		dir := t.TempDir()
+		store := alias.NewStore(filepath.Join(dir, "aliases"))
+		store.Set("a", "/a")
+		if err := store.Save(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		entries, _ := os.ReadDir(dir)
+		if len(entries) != 1 {
+			t.Errorf("directory holds %d files, want only aliases", len(entries))
+		}
+	})
+}
+
+func TestEscaping(t *testing.T) {
+	names := map[string]string{
+		"a=b":      "/path/with=equals",
+		"#tag":     "/hash",
+		" padded ": ` /spaced\path `,
+	}
+
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "aliases")
+	store := alias.NewStore(filePath)
+	for name, path := range names {
+		store.Set(name, path)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reloaded := alias.NewStore(filePath)
+	aliases, err := reloaded.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, path := range names {
+		if aliases[name] != path {
+			t.Errorf("%q = %q, want %q", name, aliases[name], path)
+		}
+	}
+	if problems := reloaded.Problems(); len(problems) != 0 {
+		t.Errorf("problems = %v, want none", problems)
+	}
+}
+
+func TestProblems(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "aliases")
+	content := alias.Header + "\nok=/ok\nno equals here\n=/no/name\nempty=\nok=/again\nbad=/trailing\\\n"
+	if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	store := alias.NewStore(filePath)
+	aliases, err := store.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"line 3: expected name=path",
+		"line 4: empty alias name",
+		`line 5: alias "empty" has an empty path`,
+		`line 6: duplicate alias "ok" (first defined on line 2); the last one wins`,
+		"line 7: trailing backslash",
+	}
+	problems := store.Problems()
+	if len(problems) != len(want) {
+		t.Fatalf("problems = %v, want %v", problems, want)
+	}
+	for i, p := range problems {
+		if p.String() != want[i] {
+			t.Errorf("problem %d = %q, want %q", i, p.String(), want[i])
+		}
+	}
+	if len(aliases) != 1 || aliases["ok"] != "/again" {
+		t.Errorf("aliases = %v, want only ok=/again", aliases)
+	}
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(filePath); string(data) != content {
+		t.Errorf("malformed lines should be saved as they were:\n%s", data)
+	}
+}
+
+func TestProblemsReportsUnresolvableAliases(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "aliases")
+	content := alias.Header + "\nwork=/code/work\na=b\nb=a/x\nunset=$PORTAL_TEST_UNSET/code\n"
+	if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	store := alias.NewStore(filePath)
+	if _, err := store.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"line 3: alias cycle: a -> b -> a",
+		"line 4: alias cycle: b -> a -> b",
+		"line 5: alias unset uses unset variable $PORTAL_TEST_UNSET",
+	}
+	problems := store.Problems()
+	if len(problems) != len(want) {
+		t.Fatalf("problems = %v, want %v", problems, want)
+	}
+	for i, p := range problems {
+		if p.String() != want[i] {
+			t.Errorf("problem %d = %q, want %q", i, p.String(), want[i])
+		}
+	}
+}
+
+func TestMigration(t *testing.T) {
+	t.Run("rewrites an original file and keeps a backup", func(t *testing.T) {
+		dir := t.TempDir()
+		filePath := filepath.Join(dir, "aliases")
+		content := "# mine\nwin=C:\\code\nbroken\nwork=/code/work\n"
+		if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+
+		store := alias.NewStore(filePath)
+		aliases, err := store.Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if aliases["win"] != `C:\code` || aliases["work"] != "/code/work" {
+			t.Errorf("aliases = %v, want win and work", aliases)
+		}
+		backup, err := os.ReadFile(filePath + ".bak")
+		if err != nil || string(backup) != content {
+			t.Errorf("backup = %q (%v), want the original file", backup, err)
+		}
+		data, _ := os.ReadFile(filePath)
+		want := alias.Header + "\n# mine\nwin=C:\\\\code\nbroken\nwork=/code/work\n"
+		if string(data) != want {
+			t.Errorf("file content = %q, want %q", data, want)
+		}
+		if problems := store.Problems(); len(problems) != 1 || problems[0].Line != 4 {
+			t.Errorf("problems = %v, want the broken line reported as line 4", problems)
+		}
+	})
+
+	t.Run("leaves current files alone", func(t *testing.T) {
+		dir := t.TempDir()
+		filePath := filepath.Join(dir, "aliases")
+		if err := os.WriteFile(filePath, []byte(alias.Header+"\na=/a\n"), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+
+		if _, err := alias.NewStore(filePath).Load(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Stat(filePath + ".bak"); !os.IsNotExist(err) {
+			t.Error("no backup should be written for a current file")
+		}
+	})
+}
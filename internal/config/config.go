@@ -0,0 +1,333 @@
+// Package config loads Portal's central configuration file.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/leeovery/portal/internal/discovery"
+	"github.com/leeovery/portal/internal/mux"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/session"
+)
+
+// DefaultNameFormat is the session name format used when none is configured.
+const DefaultNameFormat = session.DefaultNameFormat
+
+// Config holds every typed setting read from config.json.
+type Config struct {
+	Multiplexer MultiplexerConfig `json:"multiplexer"`
+	Session     SessionConfig     `json:"session"`
+	Projects    ProjectsConfig    `json:"projects"`
+	Discovery   DiscoveryConfig   `json:"discovery"`
+	Browser     BrowserConfig     `json:"browser"`
+	Git         GitConfig         `json:"git"`
+	TUI         TUIConfig         `json:"tui"`
+	Snapshot    SnapshotConfig    `json:"snapshot"`
+}
+
+// MultiplexerConfig selects the terminal multiplexer Portal drives.
+type MultiplexerConfig struct {
+	// Backend is one of mux.Backends.
+	Backend string `json:"backend"`
+}
+
+// SessionConfig controls how new sessions are named and started.
+type SessionConfig struct {
+	// NameFormat is the session name template; see session.ValidateNameTemplate
+	// for the placeholders it accepts.
+	NameFormat string `json:"name_format"`
+	// ProjectNameFormats overrides NameFormat per project, keyed by project
+	// name or directory.
+	ProjectNameFormats map[string]string `json:"project_name_formats,omitempty"`
+	// DefaultCommand runs in new sessions when no command is given.
+	DefaultCommand []string `json:"default_command,omitempty"`
+}
+
+// ProjectsConfig controls how remembered projects are ranked.
+type ProjectsConfig struct {
+	// Order is one of project.Orders.
+	Order string `json:"order"`
+}
+
+// DiscoveryConfig controls scanning source roots for projects.
+type DiscoveryConfig struct {
+	// Roots are the directories xctl projects scan searches by default.
+	Roots []string `json:"roots"`
+	// MaxDepth is how many levels below each root are searched.
+	MaxDepth int `json:"max_depth"`
+	// MaxDirs bounds how many directories one scan reads.
+	MaxDirs int `json:"max_dirs"`
+	// Markers are file names identifying projects besides git repositories.
+	Markers []string `json:"markers"`
+	// Ignore holds glob patterns for directory names never descended into.
+	Ignore []string `json:"ignore"`
+	// OnStartup scans the roots in the background whenever the TUI opens.
+	OnStartup bool `json:"on_startup"`
+}
+
+// BrowserConfig controls the TUI file browser.
+type BrowserConfig struct {
+	ShowHidden bool `json:"show_hidden"`
+}
+
+// GitConfig controls how directories are resolved to projects and how their
+// repositories are shown.
+type GitConfig struct {
+	// ResolveRoot opens sessions at the enclosing git repository root.
+	ResolveRoot bool `json:"resolve_root"`
+	// ShowStatus shows each project's branch and working tree state in the TUI.
+	ShowStatus bool `json:"show_status"`
+}
+
+// SnapshotConfig controls saving and restoring tmux sessions.
+type SnapshotConfig struct {
+	// Interval is how often automatic snapshots are saved, as a Go duration
+	// such as "15m". Empty disables them.
+	Interval string `json:"interval"`
+	// RestoreCommands re-runs each pane's captured command when restoring.
+	RestoreCommands bool `json:"restore_commands"`
+}
+
+// AutoInterval returns the parsed snapshot interval, zero when automatic
+// snapshots are disabled.
+func (c SnapshotConfig) AutoInterval() time.Duration {
+	d, _ := time.ParseDuration(c.Interval)
+	return d
+}
+
+// TUIConfig controls the interactive picker.
+type TUIConfig struct {
+	Colors Colors `json:"colors"`
+}
+
+// Colors holds lipgloss colour values: an ANSI code (0-255) or a #rrggbb hex string.
+type Colors struct {
+	Cursor   string `json:"cursor"`
+	Detail   string `json:"detail"`
+	Attached string `json:"attached"`
+	Header   string `json:"header"`
+	Hint     string `json:"hint"`
+	Match    string `json:"match"`
+}
+
+// Default returns the configuration used when no config file exists.
+func Default() Config {
+	return Config{
+		Multiplexer: MultiplexerConfig{Backend: mux.DefaultBackend},
+		Session:     SessionConfig{NameFormat: DefaultNameFormat},
+		Projects:    ProjectsConfig{Order: string(project.DefaultOrder)},
+		Discovery: DiscoveryConfig{
+			Roots:    []string{},
+			MaxDepth: discovery.DefaultMaxDepth,
+			MaxDirs:  discovery.DefaultMaxDirs,
+			Markers:  slices.Clone(discovery.DefaultMarkers),
+			Ignore:   slices.Clone(discovery.DefaultIgnore),
+		},
+		Git: GitConfig{ResolveRoot: true, ShowStatus: true},
+		TUI: TUIConfig{Colors: Colors{
+			Cursor:   "212",
+			Detail:   "241",
+			Attached: "76",
+			Header:   "99",
+			Hint:     "241",
+			Match:    "214",
+		}},
+	}
+}
+
+// knownKeys lists the keys accepted in each object of the config file,
+// keyed by the dotted path of that object ("" is the top level).
+var knownKeys = map[string][]string{
+	"":            {"multiplexer", "session", "projects", "discovery", "browser", "git", "tui", "snapshot"},
+	"multiplexer": {"backend"},
+	"session":     {"name_format", "project_name_formats", "default_command"},
+	"projects":    {"order"},
+	"discovery":   {"roots", "max_depth", "max_dirs", "markers", "ignore", "on_startup"},
+	"browser":     {"show_hidden"},
+	"git":         {"resolve_root", "show_status"},
+	"tui":         {"colors"},
+	"snapshot":    {"interval", "restore_commands"},
+	"tui.colors":  {"cursor", "detail", "attached", "header", "hint", "match"},
+}
+
+// Load reads the config file at path on top of the defaults.
+// A missing file yields the defaults. Unknown keys are returned as warnings
+// rather than errors so one file can be shared across Portal versions.
+func Load(path string) (Config, []string, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil, nil
+		}
+		return cfg, nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	warnings, err := Parse(data, &cfg)
+	if err != nil {
+		return cfg, warnings, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return cfg, warnings, nil
+}
+
+// Parse decodes data into cfg and validates the result.
+// Fields absent from data keep their current values in cfg.
+func Parse(data []byte, cfg *Config) ([]string, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, describeDecodeError(data, err)
+	}
+	warnings := unknownKeys(raw, "")
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return warnings, describeDecodeError(data, err)
+	}
+
+	return warnings, cfg.Validate()
+}
+
+// unknownKeys walks obj and reports keys not listed in knownKeys.
+func unknownKeys(obj map[string]any, prefix string) []string {
+	known := knownKeys[prefix]
+
+	var warnings []string
+	for _, k := range slices.Sorted(maps.Keys(obj)) {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		if !slices.Contains(known, k) {
+			warnings = append(warnings, fmt.Sprintf("unknown key %q (ignored)", path))
+			continue
+		}
+		if child, ok := obj[k].(map[string]any); ok {
+			if _, nested := knownKeys[path]; nested {
+				warnings = append(warnings, unknownKeys(child, path)...)
+			}
+		}
+	}
+	return warnings
+}
+
+// describeDecodeError turns JSON decoding errors into messages that point at
+// the offending line or setting.
+func describeDecodeError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line := 1 + bytes.Count(data[:syntaxErr.Offset], []byte("\n"))
+		return fmt.Errorf("line %d: %v", line, syntaxErr)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if typeErr.Field == "" {
+			return fmt.Errorf("expected a JSON object, got %s", typeErr.Value)
+		}
+		return fmt.Errorf("%s: expected %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+
+	return err
+}
+
+var (
+	hexColorPattern  = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+	ansiColorPattern = regexp.MustCompile(`^(25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9]?[0-9])$`)
+)
+
+// Validate reports every invalid setting in c, one per line.
+func (c Config) Validate() error {
+	var errs []error
+
+	if _, err := mux.ParseBackend(c.Multiplexer.Backend); err != nil {
+		errs = append(errs, fmt.Errorf("multiplexer.backend: %w", err))
+	}
+
+	if err := session.ValidateNameTemplate(c.Session.NameFormat); err != nil {
+		errs = append(errs, fmt.Errorf("session.name_format: %w", err))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(c.Session.ProjectNameFormats)) {
+		if err := session.ValidateNameTemplate(c.Session.ProjectNameFormats[key]); err != nil {
+			errs = append(errs, fmt.Errorf("session.project_name_formats[%q]: %w", key, err))
+		}
+	}
+
+	for i, arg := range c.Session.DefaultCommand {
+		if strings.TrimSpace(arg) == "" {
+			errs = append(errs, fmt.Errorf("session.default_command[%d]: must not be empty", i))
+		}
+	}
+
+	if _, err := project.ParseOrder(c.Projects.Order); err != nil {
+		errs = append(errs, fmt.Errorf("projects.order: %w", err))
+	}
+
+	for i, root := range c.Discovery.Roots {
+		if strings.TrimSpace(root) == "" {
+			errs = append(errs, fmt.Errorf("discovery.roots[%d]: must not be empty", i))
+		}
+	}
+	if c.Discovery.MaxDepth < 1 {
+		errs = append(errs, errors.New("discovery.max_depth: must be at least 1"))
+	}
+	if c.Discovery.MaxDirs < 1 {
+		errs = append(errs, errors.New("discovery.max_dirs: must be at least 1"))
+	}
+	for i, pattern := range c.Discovery.Ignore {
+		if err := discovery.ValidatePattern(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("discovery.ignore[%d]: %w", i, err))
+		}
+	}
+
+	if c.Snapshot.Interval != "" {
+		if d, err := time.ParseDuration(c.Snapshot.Interval); err != nil {
+			errs = append(errs, fmt.Errorf("snapshot.interval: %q is not a duration such as 15m", c.Snapshot.Interval))
+		} else if d < time.Minute {
+			errs = append(errs, errors.New("snapshot.interval: must be at least 1m"))
+		}
+	}
+
+	colors := []struct {
+		key   string
+		value string
+	}{
+		{"cursor", c.TUI.Colors.Cursor},
+		{"detail", c.TUI.Colors.Detail},
+		{"attached", c.TUI.Colors.Attached},
+		{"header", c.TUI.Colors.Header},
+		{"hint", c.TUI.Colors.Hint},
+		{"match", c.TUI.Colors.Match},
+	}
+	for _, col := range colors {
+		if col.value == "" || hexColorPattern.MatchString(col.value) || ansiColorPattern.MatchString(col.value) {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("tui.colors.%s: %q is not an ANSI colour (0-255) or #rrggbb", col.key, col.value))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Marshal renders c as indented JSON, the format Load accepts.
+func (c Config) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
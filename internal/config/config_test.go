@@ -0,0 +1,326 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/config"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("missing file returns defaults", func(t *testing.T) {
+		cfg, warnings, err := config.Load(filepath.Join(t.TempDir(), "config.json"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("warnings = %v, want none", warnings)
+		}
+		if !reflect.DeepEqual(cfg, config.Default()) {
+			t.Errorf("Load() = %+v, want defaults %+v", cfg, config.Default())
+		}
+	})
+
+	t.Run("empty file returns defaults", func(t *testing.T) {
+		cfg, _, err := config.Load(writeConfig(t, "  \n"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(cfg, config.Default()) {
+			t.Errorf("Load() = %+v, want defaults", cfg)
+		}
+	})
+
+	t.Run("set fields override defaults and others are kept", func(t *testing.T) {
+		path := writeConfig(t, `{
+  "multiplexer": {"backend": "zellij"},
+  "session": {"default_command": ["claude", "--resume"], "project_name_formats": {"api": "{project}-{seq:2}"}},
+  "projects": {"order": "alphabetical"},
+  "discovery": {"roots": ["~/Code"], "max_depth": 2},
+  "browser": {"show_hidden": true},
+  "git": {"resolve_root": false, "show_status": false},
+  "tui": {"colors": {"cursor": "#ff8800"}},
+  "snapshot": {"interval": "15m", "restore_commands": true}
+}`)
+
+		cfg, warnings, err := config.Load(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("warnings = %v, want none", warnings)
+		}
+
+		if cfg.Multiplexer.Backend != "zellij" {
+			t.Errorf("Multiplexer.Backend = %q, want %q", cfg.Multiplexer.Backend, "zellij")
+		}
+		if cfg.Session.NameFormat != config.DefaultNameFormat {
+			t.Errorf("NameFormat = %q, want default %q", cfg.Session.NameFormat, config.DefaultNameFormat)
+		}
+		if !reflect.DeepEqual(cfg.Session.DefaultCommand, []string{"claude", "--resume"}) {
+			t.Errorf("DefaultCommand = %v", cfg.Session.DefaultCommand)
+		}
+		if cfg.Session.ProjectNameFormats["api"] != "{project}-{seq:2}" {
+			t.Errorf("ProjectNameFormats = %v", cfg.Session.ProjectNameFormats)
+		}
+		if cfg.Projects.Order != "alphabetical" {
+			t.Errorf("Projects.Order = %q, want %q", cfg.Projects.Order, "alphabetical")
+		}
+		if !reflect.DeepEqual(cfg.Discovery.Roots, []string{"~/Code"}) || cfg.Discovery.MaxDepth != 2 {
+			t.Errorf("Discovery = %+v, want roots [~/Code] and depth 2", cfg.Discovery)
+		}
+		if !reflect.DeepEqual(cfg.Discovery.Markers, config.Default().Discovery.Markers) {
+			t.Errorf("Discovery.Markers = %v, want defaults", cfg.Discovery.Markers)
+		}
+		if !cfg.Browser.ShowHidden {
+			t.Error("ShowHidden = false, want true")
+		}
+		if cfg.Git.ResolveRoot {
+			t.Error("ResolveRoot = true, want false")
+		}
+		if cfg.Git.ShowStatus {
+			t.Error("ShowStatus = true, want false")
+		}
+		if cfg.Snapshot.AutoInterval() != 15*time.Minute || !cfg.Snapshot.RestoreCommands {
+			t.Errorf("Snapshot = %+v, want 15m with commands", cfg.Snapshot)
+		}
+		if cfg.TUI.Colors.Cursor != "#ff8800" {
+			t.Errorf("Colors.Cursor = %q, want %q", cfg.TUI.Colors.Cursor, "#ff8800")
+		}
+		if cfg.TUI.Colors.Header != config.Default().TUI.Colors.Header {
+			t.Errorf("Colors.Header = %q, want default", cfg.TUI.Colors.Header)
+		}
+	})
+
+	t.Run("unknown keys warn rather than fail", func(t *testing.T) {
+		path := writeConfig(t, `{
+  "future": 1,
+  "session": {"name_format": "{project}-{id}", "flavour": "x"},
+  "tui": {"colors": {"border": "12"}}
+}`)
+
+		_, warnings, err := config.Load(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []string{
+			`unknown key "future" (ignored)`,
+			`unknown key "session.flavour" (ignored)`,
+			`unknown key "tui.colors.border" (ignored)`,
+		}
+		if !reflect.DeepEqual(warnings, want) {
+			t.Errorf("warnings = %v, want %v", warnings, want)
+		}
+	})
+
+	t.Run("syntax error reports line number", func(t *testing.T) {
+		path := writeConfig(t, "{\n  \"session\": {\n    \"name_format\": \"{id}\",\n  }\n}")
+
+		_, _, err := config.Load(path)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "line 4") {
+			t.Errorf("error = %q, want it to mention line 4", err)
+		}
+		if !strings.Contains(err.Error(), path) {
+			t.Errorf("error = %q, want it to mention the file path", err)
+		}
+	})
+
+	t.Run("type mismatch names the setting", func(t *testing.T) {
+		path := writeConfig(t, `{"browser": {"show_hidden": "yes"}}`)
+
+		_, _, err := config.Load(path)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "browser.show_hidden: expected bool, got string") {
+			t.Errorf("error = %q", err)
+		}
+	})
+
+	t.Run("non-object document is rejected", func(t *testing.T) {
+		_, _, err := config.Load(writeConfig(t, `["session"]`))
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "expected a JSON object") {
+			t.Errorf("error = %q", err)
+		}
+	})
+
+	t.Run("invalid values fail validation", func(t *testing.T) {
+		path := writeConfig(t, `{"session": {"name_format": "{project}"}}`)
+
+		_, _, err := config.Load(path)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "session.name_format: must contain {id}") {
+			t.Errorf("error = %q", err)
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("defaults are valid", func(t *testing.T) {
+		if err := config.Default().Validate(); err != nil {
+			t.Errorf("Default().Validate() = %v, want nil", err)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		modify  func(c *config.Config)
+		wantErr string
+	}{
+		{
+			name:    "unknown backend",
+			modify:  func(c *config.Config) { c.Multiplexer.Backend = "screen" },
+			wantErr: `multiplexer.backend: unknown backend "screen" (want one of: tmux, zellij)`,
+		},
+		{
+			name:    "malformed snapshot interval",
+			modify:  func(c *config.Config) { c.Snapshot.Interval = "often" },
+			wantErr: `snapshot.interval: "often" is not a duration such as 15m`,
+		},
+		{
+			name:    "snapshot interval too short",
+			modify:  func(c *config.Config) { c.Snapshot.Interval = "10s" },
+			wantErr: "snapshot.interval: must be at least 1m",
+		},
+		{
+			name:    "empty name format",
+			modify:  func(c *config.Config) { c.Session.NameFormat = "" },
+			wantErr: "session.name_format: must not be empty",
+		},
+		{
+			name:    "unknown placeholder",
+			modify:  func(c *config.Config) { c.Session.NameFormat = "{nope}-{id}" },
+			wantErr: "session.name_format: unknown placeholder {nope}",
+		},
+		{
+			name: "invalid per-project format",
+			modify: func(c *config.Config) {
+				c.Session.ProjectNameFormats = map[string]string{"api": "{project}-{seq:2}", "web": "{project}"}
+			},
+			wantErr: `session.project_name_formats["web"]: must contain {id} or {seq}`,
+		},
+		{
+			name:    "empty default command argument",
+			modify:  func(c *config.Config) { c.Session.DefaultCommand = []string{"claude", " "} },
+			wantErr: "session.default_command[1]: must not be empty",
+		},
+		{
+			name:    "unknown project order",
+			modify:  func(c *config.Config) { c.Projects.Order = "popularity" },
+			wantErr: `projects.order: unknown order "popularity"`,
+		},
+		{
+			name:    "empty discovery root",
+			modify:  func(c *config.Config) { c.Discovery.Roots = []string{"~/Code", ""} },
+			wantErr: "discovery.roots[1]: must not be empty",
+		},
+		{
+			name:    "zero discovery depth",
+			modify:  func(c *config.Config) { c.Discovery.MaxDepth = 0 },
+			wantErr: "discovery.max_depth: must be at least 1",
+		},
+		{
+			name:    "zero discovery directory limit",
+			modify:  func(c *config.Config) { c.Discovery.MaxDirs = 0 },
+			wantErr: "discovery.max_dirs: must be at least 1",
+		},
+		{
+			name:    "malformed ignore pattern",
+			modify:  func(c *config.Config) { c.Discovery.Ignore = []string{"[a-"} },
+			wantErr: `discovery.ignore[0]: "[a-": syntax error in pattern`,
+		},
+		{
+			name:    "ansi colour out of range",
+			modify:  func(c *config.Config) { c.TUI.Colors.Hint = "256" },
+			wantErr: `tui.colors.hint: "256" is not an ANSI colour (0-255) or #rrggbb`,
+		},
+		{
+			name:    "malformed hex colour",
+			modify:  func(c *config.Config) { c.TUI.Colors.Cursor = "#fff" },
+			wantErr: `tui.colors.cursor: "#fff" is not an ANSI colour (0-255) or #rrggbb`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %q, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("reports every problem", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Session.NameFormat = "{nope}"
+		cfg.TUI.Colors.Detail = "grey"
+
+		err := cfg.Validate()
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if got := len(strings.Split(err.Error(), "\n")); got != 2 {
+			t.Errorf("got %d problems, want 2: %q", got, err)
+		}
+	})
+
+	t.Run("empty colour falls back to terminal default", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.TUI.Colors.Cursor = ""
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Validate() = %v, want nil", err)
+		}
+	})
+}
+
+func TestMarshal(t *testing.T) {
+	t.Run("output round-trips through Load", func(t *testing.T) {
+		want := config.Default()
+		want.Session.DefaultCommand = []string{"nvim"}
+		want.Browser.ShowHidden = true
+
+		data, err := want.Marshal()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, warnings, err := config.Load(writeConfig(t, string(data)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("warnings = %v, want none", warnings)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("round trip = %+v, want %+v", got, want)
+		}
+	})
+}
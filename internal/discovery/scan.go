@@ -0,0 +1,275 @@
+// Package discovery finds project directories by walking source roots.
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Defaults for Options fields left at their zero value.
+const (
+	DefaultMaxDepth = 3
+	DefaultMaxDirs  = 20000
+	DefaultWorkers  = 8
+)
+
+// DefaultMarkers are the files that identify a project that is not a git
+// repository.
+var DefaultMarkers = []string{"go.mod", "package.json", "Cargo.toml", "pyproject.toml", "composer.json", "Gemfile", "mix.exs", "build.gradle", "pom.xml"}
+
+// DefaultIgnore are the directory name patterns never descended into.
+var DefaultIgnore = []string{".*", "node_modules", "vendor", "target", "dist", "build", "Library"}
+
+// Kind is how a project directory was identified.
+type Kind string
+
+const (
+	// KindRepo is a git working tree with a .git directory.
+	KindRepo Kind = "repo"
+	// KindWorktree is a linked git worktree or submodule, whose .git is a file.
+	KindWorktree Kind = "worktree"
+	// KindBare is a bare git repository.
+	KindBare Kind = "bare"
+	// KindMarker is a directory holding one of the configured marker files.
+	KindMarker Kind = "marker"
+)
+
+// Found is a discovered project directory.
+type Found struct {
+	Path string `json:"path"`
+	Kind Kind   `json:"kind"`
+	// Marker is the marker file that identified a KindMarker project.
+	Marker string `json:"marker,omitempty"`
+}
+
+// Options controls a scan.
+type Options struct {
+	// Roots are the directories to search. They are not projects themselves
+	// unless they are identified as one.
+	Roots []string
+	// MaxDepth is how many levels below each root are searched.
+	MaxDepth int
+	// Markers are file names identifying projects besides git repositories.
+	Markers []string
+	// Ignore holds filepath.Match patterns for directory names that are not
+	// descended into.
+	Ignore []string
+	// MaxDirs bounds the number of directories read, so a huge tree cannot
+	// stall the scan.
+	MaxDirs int
+	// Workers is the number of directories read concurrently.
+	Workers int
+}
+
+// Result is the outcome of a scan.
+type Result struct {
+	// Projects are the discovered projects, sorted by path.
+	Projects []Found
+	// Visited is the number of directories read.
+	Visited int
+	// Truncated reports that the scan stopped at MaxDirs before the whole
+	// tree was searched.
+	Truncated bool
+}
+
+// withDefaults fills in zero-valued options.
+func (o Options) withDefaults() Options {
+	if o.MaxDepth <= 0 {
+		o.MaxDepth = DefaultMaxDepth
+	}
+	if o.MaxDirs <= 0 {
+		o.MaxDirs = DefaultMaxDirs
+	}
+	if o.Workers <= 0 {
+		o.Workers = DefaultWorkers
+	}
+	if o.Markers == nil {
+		o.Markers = DefaultMarkers
+	}
+	if o.Ignore == nil {
+		o.Ignore = DefaultIgnore
+	}
+	return o
+}
+
+// dir is a directory waiting to be read.
+type dir struct {
+	path  string
+	depth int
+}
+
+// Scan searches opts.Roots breadth first, one level at a time, reading the
+// directories of each level with a pool of workers. A directory identified as
+// a project is not searched further. Unreadable directories are skipped.
+//
+// Scan stops early when ctx is done, returning what it found so far along
+// with ctx's error.
+func Scan(ctx context.Context, opts Options) (Result, error) {
+	opts = opts.withDefaults()
+
+	var res Result
+	seen := make(map[string]bool)
+	var level []dir
+	for _, root := range opts.Roots {
+		abs, err := filepath.Abs(root)
+		if err != nil || seen[abs] {
+			continue
+		}
+		seen[abs] = true
+		level = append(level, dir{path: abs})
+	}
+
+	for len(level) > 0 {
+		if remaining := opts.MaxDirs - res.Visited; len(level) > remaining {
+			level = level[:remaining]
+			res.Truncated = true
+		}
+
+		found, next, err := scanLevel(ctx, level, opts)
+		res.Visited += len(level)
+		res.Projects = append(res.Projects, found...)
+		if err != nil {
+			sortFound(res.Projects)
+			return res, err
+		}
+		if res.Truncated {
+			break
+		}
+		level = next
+	}
+
+	sortFound(res.Projects)
+	return res, nil
+}
+
+// sortFound orders projects by path.
+func sortFound(found []Found) {
+	slices.SortFunc(found, func(a, b Found) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+}
+
+// scanLevel reads every directory in level concurrently, returning the
+// projects found and the subdirectories to search next.
+func scanLevel(ctx context.Context, level []dir, opts Options) ([]Found, []dir, error) {
+	jobs := make(chan dir)
+	var (
+		mu    sync.Mutex
+		found []Found
+		next  []dir
+		wg    sync.WaitGroup
+	)
+
+	for range min(opts.Workers, len(level)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := range jobs {
+				f, children := visit(d, opts)
+				mu.Lock()
+				if f != nil {
+					found = append(found, *f)
+				}
+				next = append(next, children...)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var err error
+send:
+	for _, d := range level {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			break send
+		case jobs <- d:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return found, next, err
+}
+
+// visit reads d and reports whether it is a project. When it is not, and
+// the depth limit allows, its subdirectories are returned for searching.
+func visit(d dir, opts Options) (*Found, []dir) {
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		return nil, nil
+	}
+
+	if f := identify(d.path, entries, opts.Markers); f != nil {
+		return f, nil
+	}
+	if d.depth >= opts.MaxDepth {
+		return nil, nil
+	}
+
+	var children []dir
+	for _, e := range entries {
+		// Symlinked directories are not followed, which also avoids cycles.
+		if !e.IsDir() || ignored(e.Name(), opts.Ignore) {
+			continue
+		}
+		children = append(children, dir{path: filepath.Join(d.path, e.Name()), depth: d.depth + 1})
+	}
+	return nil, children
+}
+
+// identify reports whether a directory with entries is a project.
+func identify(path string, entries []os.DirEntry, markers []string) *Found {
+	names := make(map[string]os.DirEntry, len(entries))
+	for _, e := range entries {
+		names[e.Name()] = e
+	}
+
+	if git, ok := names[".git"]; ok {
+		if git.IsDir() {
+			return &Found{Path: path, Kind: KindRepo}
+		}
+		return &Found{Path: path, Kind: KindWorktree}
+	}
+	if isBareRepo(names) {
+		return &Found{Path: path, Kind: KindBare}
+	}
+	for _, marker := range markers {
+		if _, ok := names[marker]; ok {
+			return &Found{Path: path, Kind: KindMarker, Marker: marker}
+		}
+	}
+	return nil
+}
+
+// isBareRepo reports whether a directory's entries look like a bare git
+// repository: a HEAD file alongside objects and refs directories.
+func isBareRepo(names map[string]os.DirEntry) bool {
+	head, objects, refs := names["HEAD"], names["objects"], names["refs"]
+	return head != nil && !head.IsDir() &&
+		objects != nil && objects.IsDir() &&
+		refs != nil && refs.IsDir()
+}
+
+// ignored reports whether a directory name matches one of the patterns.
+func ignored(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidatePattern reports whether pattern is a well-formed ignore pattern.
+func ValidatePattern(pattern string) error {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("%q: %w", pattern, err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package discovery_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/leeovery/portal/internal/discovery"
+)
+
+// makeTree creates each path under root. Paths ending in / are directories;
+// others are empty files.
+func makeTree(t *testing.T, root string, paths ...string) {
+	t.Helper()
+	for _, p := range paths {
+		full := filepath.Join(root, p)
+		if p[len(p)-1] == '/' {
+			if err := os.MkdirAll(full, 0o755); err != nil {
+				t.Fatalf("failed to create dir: %v", err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(full, nil, 0o644); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root,
+		"repo/.git/",
+		"repo/nested/.git/",
+		"worktree/.git",
+		"bare.git/HEAD",
+		"bare.git/objects/",
+		"bare.git/refs/",
+		"group/tool/go.mod",
+		"group/notes/readme.md",
+		"node_modules/dep/.git/",
+		".hidden/dotfiles/.git/",
+		"a/b/c/too-deep/.git/",
+	)
+
+	res, err := discovery.Scan(context.Background(), discovery.Options{Roots: []string{root}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []discovery.Found{
+		{Path: filepath.Join(root, "bare.git"), Kind: discovery.KindBare},
+		{Path: filepath.Join(root, "group/tool"), Kind: discovery.KindMarker, Marker: "go.mod"},
+		{Path: filepath.Join(root, "repo"), Kind: discovery.KindRepo},
+		{Path: filepath.Join(root, "worktree"), Kind: discovery.KindWorktree},
+	}
+	if !slices.Equal(res.Projects, want) {
+		t.Errorf("Projects = %+v\nwant %+v", res.Projects, want)
+	}
+	if res.Truncated {
+		t.Error("scan should not be truncated")
+	}
+}
+
+func TestScanOptions(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root,
+		"one/.git/",
+		"two/deep/.git/",
+		"skip-me/.git/",
+		"custom/marker.txt",
+	)
+
+	t.Run("max depth", func(t *testing.T) {
+		res, err := discovery.Scan(context.Background(), discovery.Options{Roots: []string{root}, MaxDepth: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, f := range res.Projects {
+			if f.Path == filepath.Join(root, "two/deep") {
+				t.Errorf("found %s beyond max depth", f.Path)
+			}
+		}
+	})
+
+	t.Run("ignore patterns and markers", func(t *testing.T) {
+		res, err := discovery.Scan(context.Background(), discovery.Options{
+			Roots:   []string{root},
+			Ignore:  []string{"skip-*"},
+			Markers: []string{"marker.txt"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var paths []string
+		for _, f := range res.Projects {
+			paths = append(paths, f.Path)
+		}
+		want := []string{filepath.Join(root, "custom"), filepath.Join(root, "one"), filepath.Join(root, "two/deep")}
+		if !slices.Equal(paths, want) {
+			t.Errorf("paths = %v, want %v", paths, want)
+		}
+	})
+
+	t.Run("max dirs bounds the scan", func(t *testing.T) {
+		res, err := discovery.Scan(context.Background(), discovery.Options{Roots: []string{root}, MaxDirs: 3})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !res.Truncated || res.Visited != 3 {
+			t.Errorf("Truncated = %v, Visited = %d; want true, 3", res.Truncated, res.Visited)
+		}
+	})
+
+	t.Run("cancelled context stops the scan", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := discovery.Scan(ctx, discovery.Options{Roots: []string{root}})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("root that is a project", func(t *testing.T) {
+		res, err := discovery.Scan(context.Background(), discovery.Options{Roots: []string{filepath.Join(root, "one")}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(res.Projects) != 1 || res.Projects[0].Path != filepath.Join(root, "one") {
+			t.Errorf("Projects = %+v, want the root itself", res.Projects)
+		}
+	})
+
+	t.Run("missing root is skipped", func(t *testing.T) {
+		res, err := discovery.Scan(context.Background(), discovery.Options{Roots: []string{filepath.Join(root, "missing")}})
+		if err != nil || len(res.Projects) != 0 {
+			t.Errorf("Scan = %+v, %v; want nothing", res, err)
+		}
+	})
+}
@@ -1,12 +1,20 @@
 // Package fuzzy provides subsequence-based fuzzy matching.
 package fuzzy
 
-import "strings"
+import (
+	"slices"
+	"unicode"
+)
 
 // Match returns true if pattern is a subsequence of text.
 // Each character in pattern must appear in text in order,
-// but not necessarily consecutively.
+// but not necessarily consecutively. Characters are compared as runes.
 func Match(text, pattern string) bool {
+	return isSubsequence([]rune(text), []rune(pattern))
+}
+
+// isSubsequence reports whether every rune of pattern appears in text in order.
+func isSubsequence(text, pattern []rune) bool {
 	pi := 0
 	for i := 0; i < len(text) && pi < len(pattern); i++ {
 		if text[i] == pattern[pi] {
@@ -16,19 +24,219 @@ func Match(text, pattern string) bool {
 	return pi == len(pattern)
 }
 
-// Filter returns items whose names fuzzy-match the given filter string.
-// Matching is case-insensitive. The nameOf function extracts the name from each item.
-// If filter is empty, all items are returned.
+// Scoring weights, modelled on fzf. Every matched character scores
+// scoreMatch plus the bonus of its position; gaps between matched
+// characters cost gapStart for the first skipped character and gapExtension
+// for each one after.
+const (
+	scoreMatch   = 16
+	gapStart     = -3
+	gapExtension = -1
+
+	// bonusPrefix rewards a match at the very start of the text.
+	bonusPrefix = 10
+	// bonusBoundary rewards a match just after a separator such as - or /.
+	bonusBoundary = 8
+	// bonusCamel rewards a match at a lower-to-upper case change or where
+	// digits start after letters.
+	bonusCamel = 7
+	// bonusConsecutive is the least bonus a character directly following
+	// the previous match earns. Runs also keep the bonus of their first
+	// character, so a run starting on a boundary stays valuable.
+	bonusConsecutive = 4
+	// firstCharMultiplier weights the bonus of the first pattern character,
+	// since where a match starts says most about its quality.
+	firstCharMultiplier = 2
+)
+
+// Result describes how a pattern matched a text.
+type Result struct {
+	// Score ranks the match; higher is better.
+	Score int
+	// Positions holds the rune indices in the text of the matched
+	// characters, in ascending order.
+	Positions []int
+}
+
+// Score matches pattern against text case-insensitively. It reports false
+// when pattern is not a subsequence of text; otherwise the Result holds the
+// best-scoring alignment. Consecutive runs, matches at word boundaries, at
+// the start of the text and at camelCase humps score higher.
+func Score(text, pattern string) (Result, bool) {
+	runes := []rune(text)
+	return score(runes, lowerRunes(runes), lowerRunes([]rune(pattern)))
+}
+
+// isSeparator reports whether r separates words in names.
+func isSeparator(r rune) bool {
+	switch r {
+	case '-', '_', '/', '.', ':', ' ', '\t':
+		return true
+	}
+	return false
+}
+
+// positionBonus returns the bonus for matching text[i].
+func positionBonus(text []rune, i int) int {
+	if i == 0 {
+		return bonusPrefix
+	}
+	prev, cur := text[i-1], text[i]
+	switch {
+	case isSeparator(cur):
+		return 0
+	case isSeparator(prev):
+		return bonusBoundary
+	case unicode.IsLower(prev) && unicode.IsUpper(cur),
+		unicode.IsLetter(prev) && unicode.IsDigit(cur):
+		return bonusCamel
+	}
+	return 0
+}
+
+// score finds the best alignment of pattern in text with a Smith-Waterman
+// style dynamic programme: for each pattern rune j and text rune i it keeps
+// the best score of an alignment whose j-th rune lands on i. Runes are
+// compared in folded, which is text in the same case as pattern; bonuses
+// come from text itself. Texts are checked for a subsequence first, so
+// non-matches cost a single scan.
+func score(text, folded, pattern []rune) (Result, bool) {
+	if len(pattern) == 0 {
+		return Result{}, true
+	}
+	if !isSubsequence(folded, pattern) {
+		return Result{}, false
+	}
+
+	n, m := len(text), len(pattern)
+	bonus := make([]int, n)
+	for i := range text {
+		bonus[i] = positionBonus(text, i)
+	}
+
+	const none = -1 << 30
+	// best[j*n+i] is the best score with pattern[j] matched at text[i];
+	// runBonus is the bonus of the first character of the run ending there;
+	// from is the text index pattern[j-1] matched at on that alignment.
+	best := make([]int, m*n)
+	runBonus := make([]int, m*n)
+	from := make([]int, m*n)
+
+	for j := range m {
+		row := best[j*n : (j+1)*n]
+		gapBest, gapFrom := none, -1
+		for i := range n {
+			row[i] = none
+			if j > 0 && i >= 2 {
+				// Extend the best gapped predecessor by one more skipped rune,
+				// or open a gap after pattern[j-1] matched at i-2.
+				gapBest += gapExtension
+				if prev := best[(j-1)*n+i-2]; prev != none && prev+gapStart > gapBest {
+					gapBest, gapFrom = prev+gapStart, i-2
+				}
+			}
+			if folded[i] != pattern[j] || i < j {
+				continue
+			}
+
+			if j == 0 {
+				row[i] = scoreMatch + bonus[i]*firstCharMultiplier
+				runBonus[i] = bonus[i]
+				continue
+			}
+
+			if gapFrom >= 0 && gapBest > none/2 {
+				row[i] = gapBest + scoreMatch + bonus[i]
+				runBonus[j*n+i] = bonus[i]
+				from[j*n+i] = gapFrom
+			}
+			if prev := best[(j-1)*n+i-1]; prev != none {
+				rb := max(runBonus[(j-1)*n+i-1], bonus[i], bonusConsecutive)
+				if s := prev + scoreMatch + rb; s >= row[i] {
+					row[i] = s
+					runBonus[j*n+i] = rb
+					from[j*n+i] = i - 1
+				}
+			}
+		}
+	}
+
+	last := best[(m-1)*n:]
+	end := -1
+	for i, s := range last {
+		if s != none && (end < 0 || s > last[end]) {
+			end = i
+		}
+	}
+	if end < 0 {
+		return Result{}, false
+	}
+
+	positions := make([]int, m)
+	for j, i := m-1, end; j >= 0; j-- {
+		positions[j] = i
+		i = from[j*n+i]
+	}
+	return Result{Score: last[end], Positions: positions}, true
+}
+
+// Ranked is an item that matched a filter, with how it matched.
+type Ranked[T any] struct {
+	Item T
+	Result
+}
+
+// Rank returns the items whose names match filter, best match first. The
+// filter uses the extended syntax described on Query, and items that score
+// the same keep their input order. The nameOf function extracts the name
+// from each item. If filter has no terms, every item is returned in order
+// with an empty Result. Items have no tags, so #tag terms never match; see
+// RankTagged.
+func Rank[T any](items []T, filter string, nameOf func(T) string) []Ranked[T] {
+	return RankTagged(items, filter, nameOf, nil)
+}
+
+// RankTagged is like Rank for items that carry tags, which tagsOf extracts
+// for #tag terms to match against. A nil tagsOf means no item has tags.
+func RankTagged[T any](items []T, filter string, nameOf func(T) string, tagsOf func(T) []string) []Ranked[T] {
+	var ranked []Ranked[T]
+	query := ParseQuery(filter)
+	if query.Empty() {
+		for _, item := range items {
+			ranked = append(ranked, Ranked[T]{Item: item})
+		}
+		return ranked
+	}
+
+	for _, item := range items {
+		var tags []string
+		if tagsOf != nil {
+			tags = tagsOf(item)
+		}
+		if res, ok := query.MatchTagged(nameOf(item), tags); ok {
+			ranked = append(ranked, Ranked[T]{Item: item, Result: res})
+		}
+	}
+	slices.SortStableFunc(ranked, func(a, b Ranked[T]) int {
+		return b.Score - a.Score
+	})
+	return ranked
+}
+
+// Filter returns items whose names match the given filter string, best
+// match first; see Rank. If filter is empty, all items are returned.
 func Filter[T any](items []T, filter string, nameOf func(T) string) []T {
+	return FilterTagged(items, filter, nameOf, nil)
+}
+
+// FilterTagged is like Filter for items that carry tags; see RankTagged.
+func FilterTagged[T any](items []T, filter string, nameOf func(T) string, tagsOf func(T) []string) []T {
 	if filter == "" {
 		return items
 	}
-	lowerFilter := strings.ToLower(filter)
 	var result []T
-	for _, item := range items {
-		if Match(strings.ToLower(nameOf(item)), lowerFilter) {
-			result = append(result, item)
-		}
+	for _, r := range RankTagged(items, filter, nameOf, tagsOf) {
+		result = append(result, r.Item)
 	}
 	return result
 }
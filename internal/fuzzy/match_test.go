@@ -1,6 +1,8 @@
 package fuzzy_test
 
 import (
+	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/leeovery/portal/internal/fuzzy"
@@ -144,9 +146,21 @@ func TestFilter(t *testing.T) {
 			want:   []string{"Alpha", "Charlie"},
 		},
 		{
-			name:   "case insensitive matching",
+			name:   "lower-case filter ignores case",
+			items:  items,
+			filter: "alpha",
+			want:   []string{"Alpha"},
+		},
+		{
+			name:   "upper-case filter is case sensitive",
 			items:  items,
 			filter: "ALPHA",
+			want:   nil,
+		},
+		{
+			name:   "mixed-case filter is case sensitive",
+			items:  items,
+			filter: "Al",
 			want:   []string{"Alpha"},
 		},
 		{
@@ -195,3 +209,137 @@ func TestFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchIsRuneAware(t *testing.T) {
+	if !fuzzy.Match("café-münchen", "éü") {
+		t.Error("expected multi-byte runes to match")
+	}
+	if fuzzy.Match("é", "e") {
+		t.Error("accented rune should not match its base letter")
+	}
+}
+
+func TestScore(t *testing.T) {
+	t.Run("reports matched rune positions", func(t *testing.T) {
+		tests := []struct {
+			text    string
+			pattern string
+			want    []int
+		}{
+			{"api", "api", []int{0, 1, 2}},
+			{"my-api", "api", []int{3, 4, 5}},
+			{"a-p-i-api", "api", []int{6, 7, 8}},
+			{"myProjectName", "pn", []int{2, 9}},
+			{"über-café", "café", []int{5, 6, 7, 8}},
+			{"API", "api", []int{0, 1, 2}},
+		}
+		for _, tt := range tests {
+			res, ok := fuzzy.Score(tt.text, tt.pattern)
+			if !ok {
+				t.Errorf("Score(%q, %q) did not match", tt.text, tt.pattern)
+				continue
+			}
+			if !slices.Equal(res.Positions, tt.want) {
+				t.Errorf("Score(%q, %q) positions = %v, want %v", tt.text, tt.pattern, res.Positions, tt.want)
+			}
+		}
+	})
+
+	t.Run("non-subsequence does not match", func(t *testing.T) {
+		if _, ok := fuzzy.Score("portal", "pz"); ok {
+			t.Error("expected no match")
+		}
+	})
+
+	t.Run("empty pattern matches with no positions", func(t *testing.T) {
+		res, ok := fuzzy.Score("portal", "")
+		if !ok || len(res.Positions) != 0 {
+			t.Errorf("Score(portal, \"\") = %+v, %v", res, ok)
+		}
+	})
+
+	// Each pair lists a text that should outscore the other for the pattern.
+	better := []struct {
+		name    string
+		pattern string
+		better  string
+		worse   string
+	}{
+		{"consecutive beats scattered", "api", "api", "a-p-i-anything"},
+		{"prefix beats middle", "api", "api-gateway", "my-api"},
+		{"word boundary beats mid-word", "api", "web-api", "rapid"},
+		{"camel hump beats mid-word", "pn", "projectName", "spinner"},
+		{"short gap beats long gap", "ab", "a-b", "a---b"},
+	}
+	for _, tt := range better {
+		t.Run(tt.name, func(t *testing.T) {
+			b, okB := fuzzy.Score(tt.better, tt.pattern)
+			w, okW := fuzzy.Score(tt.worse, tt.pattern)
+			if !okB || !okW {
+				t.Fatalf("expected both to match: %v %v", okB, okW)
+			}
+			if b.Score <= w.Score {
+				t.Errorf("Score(%q) = %d, want it above Score(%q) = %d", tt.better, b.Score, tt.worse, w.Score)
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	items := []string{"a-p-i-anything", "rapid", "api", "my-api", "zzz"}
+
+	ranked := fuzzy.Rank(items, "api", func(s string) string { return s })
+
+	var got []string
+	for _, r := range ranked {
+		got = append(got, r.Item)
+	}
+	want := []string{"api", "my-api", "a-p-i-anything", "rapid"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Rank order = %v, want %v", got, want)
+	}
+	if !slices.Equal(ranked[1].Positions, []int{3, 4, 5}) {
+		t.Errorf("positions of my-api = %v, want [3 4 5]", ranked[1].Positions)
+	}
+
+	t.Run("equal scores keep input order", func(t *testing.T) {
+		got := fuzzy.Filter([]string{"web-2", "web-1"}, "web", func(s string) string { return s })
+		if !slices.Equal(got, []string{"web-2", "web-1"}) {
+			t.Errorf("Filter = %v, want input order", got)
+		}
+	})
+}
+
+// benchmarkNames returns n session-like names such as "api-7f3k2a".
+func benchmarkNames(n int) []string {
+	words := []string{"api", "web", "portal", "infra", "docs", "mobile", "billing", "search"}
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	names := make([]string, n)
+	for i := range names {
+		suffix := make([]byte, 6)
+		for j := range suffix {
+			suffix[j] = alphabet[(i*7+j*13)%len(alphabet)]
+		}
+		names[i] = words[i%len(words)] + "-" + words[(i/len(words))%len(words)] + "-" + string(suffix)
+	}
+	return names
+}
+
+func BenchmarkRank(b *testing.B) {
+	for _, n := range []int{1000, 10000} {
+		names := benchmarkNames(n)
+		for _, pattern := range []string{"a", "api", "portalweb"} {
+			b.Run(fmt.Sprintf("%d/%s", n, pattern), func(b *testing.B) {
+				for b.Loop() {
+					fuzzy.Rank(names, pattern, func(s string) string { return s })
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkScore(b *testing.B) {
+	for b.Loop() {
+		fuzzy.Score("billing-portal-service-7f3k2a", "bps")
+	}
+}
@@ -0,0 +1,294 @@
+package fuzzy
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// termKind is how a query term is matched against text.
+type termKind int
+
+const (
+	termFuzzy  termKind = iota // subsequence, scored
+	termExact                  // 'term: substring
+	termPrefix                 // ^term: text starts with term
+	termSuffix                 // term$: text ends with term
+	termEqual                  // ^term$: text is term
+	termTag                    // #term: an item tag starts with term
+)
+
+// term is one space-separated word of a query.
+type term struct {
+	kind    termKind
+	inverse bool
+	text    []rune
+}
+
+// Query is a filter in fzf's extended search syntax:
+//
+//	api web      items matching both api and web (fuzzy)
+//	api | web    items matching either
+//	'api         items containing api exactly
+//	^api         items starting with api
+//	api$         items ending with api
+//	!api         items not containing api; also !^api and !api$
+//	#client      items with a tag starting with client; !#client negates
+//
+// Tag terms only match items that carry tags; see MatchTagged. A backslash
+// keeps a following space in the term. Matching is smart-case:
+// case-insensitive unless the query contains an upper-case letter. Tags are
+// stored in lower case, so tag terms always match case-insensitively.
+type Query struct {
+	// groups are ANDed together; the terms of each group are ORed.
+	groups        [][]term
+	caseSensitive bool
+}
+
+// ParseQuery parses s in the extended search syntax described on Query.
+func ParseQuery(s string) Query {
+	q := Query{caseSensitive: slices.ContainsFunc([]rune(s), unicode.IsUpper)}
+
+	var group []term
+	orNext := false
+	for _, word := range splitWords(s) {
+		if word == "|" {
+			orNext = len(group) > 0
+			continue
+		}
+		t, ok := parseTerm(word)
+		if !ok {
+			continue
+		}
+		if !q.caseSensitive || t.kind == termTag {
+			t.text = lowerRunes(t.text)
+		}
+		if orNext {
+			group = append(group, t)
+			orNext = false
+			continue
+		}
+		if len(group) > 0 {
+			q.groups = append(q.groups, group)
+		}
+		group = []term{t}
+	}
+	if len(group) > 0 {
+		q.groups = append(q.groups, group)
+	}
+	return q
+}
+
+// splitWords splits s on unescaped spaces.
+func splitWords(s string) []string {
+	var words []string
+	var word strings.Builder
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			if r != ' ' {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ' ':
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+		default:
+			word.WriteRune(r)
+		}
+	}
+	if escaped {
+		word.WriteRune('\\')
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// parseTerm parses one word of a query. It reports false for words that
+// are only operators, such as a lone ! or ^.
+func parseTerm(word string) (term, bool) {
+	t := term{kind: termFuzzy}
+	if rest, ok := strings.CutPrefix(word, "!"); ok {
+		// Negated terms match exactly, as in fzf.
+		t.inverse = true
+		t.kind = termExact
+		word = rest
+	}
+
+	switch {
+	case strings.HasPrefix(word, "#") && len(word) > 1:
+		t.kind = termTag
+		word = word[1:]
+	case strings.HasPrefix(word, "'"):
+		t.kind = termExact
+		word = word[1:]
+	case strings.HasPrefix(word, "^") && len(word) > 1 && strings.HasSuffix(word, "$"):
+		t.kind = termEqual
+		word = word[1 : len(word)-1]
+	case strings.HasPrefix(word, "^"):
+		t.kind = termPrefix
+		word = word[1:]
+	case strings.HasSuffix(word, "$"):
+		t.kind = termSuffix
+		word = word[:len(word)-1]
+	}
+
+	t.text = []rune(word)
+	return t, len(t.text) > 0
+}
+
+// lowerRunes returns runes in lower case.
+func lowerRunes(runes []rune) []rune {
+	lowered := make([]rune, len(runes))
+	for i, r := range runes {
+		lowered[i] = unicode.ToLower(r)
+	}
+	return lowered
+}
+
+// Empty reports whether q has no terms and so matches everything.
+func (q Query) Empty() bool {
+	return len(q.groups) == 0
+}
+
+// WithoutTags returns q with its tag terms removed, for matching names
+// whose tags are not at hand.
+func (q Query) WithoutTags() Query {
+	stripped := Query{caseSensitive: q.caseSensitive}
+	for _, group := range q.groups {
+		kept := slices.DeleteFunc(slices.Clone(group), func(t term) bool {
+			return t.kind == termTag
+		})
+		if len(kept) > 0 {
+			stripped.groups = append(stripped.groups, kept)
+		}
+	}
+	return stripped
+}
+
+// Match matches text against q as an item without tags; see MatchTagged.
+func (q Query) Match(text string) (Result, bool) {
+	return q.MatchTagged(text, nil)
+}
+
+// MatchTagged matches an item named text and carrying tags against q. Every
+// group must match; within a group the best-scoring term that matches is
+// used. The Result's score is the sum over groups, and its positions are
+// those in text of every matched non-negated term.
+func (q Query) MatchTagged(text string, tags []string) (Result, bool) {
+	runes := []rune(text)
+	folded := runes
+	if !q.caseSensitive {
+		folded = lowerRunes(runes)
+	}
+
+	var total Result
+	for _, group := range q.groups {
+		best, matched := Result{}, false
+		for _, t := range group {
+			var res Result
+			var ok bool
+			if t.kind == termTag {
+				ok = t.matchTags(tags)
+			} else {
+				res, ok = t.match(runes, folded)
+			}
+			if ok && (!matched || res.Score > best.Score) {
+				best, matched = res, true
+			}
+		}
+		if !matched {
+			return Result{}, false
+		}
+		total.Score += best.Score
+		total.Positions = append(total.Positions, best.Positions...)
+	}
+
+	slices.Sort(total.Positions)
+	total.Positions = slices.Compact(total.Positions)
+	return total, true
+}
+
+// match matches a single term. text is the original text, used for position
+// bonuses, and folded is the text in the query's case.
+func (t term) match(text, folded []rune) (Result, bool) {
+	if t.kind == termFuzzy {
+		return score(text, folded, t.text)
+	}
+
+	var starts []int
+	n, m := len(folded), len(t.text)
+	if m <= n {
+		switch t.kind {
+		case termExact:
+			for i := 0; i+m <= n; i++ {
+				if slices.Equal(folded[i:i+m], t.text) {
+					starts = append(starts, i)
+				}
+			}
+		case termPrefix:
+			if slices.Equal(folded[:m], t.text) {
+				starts = append(starts, 0)
+			}
+		case termSuffix:
+			if slices.Equal(folded[n-m:], t.text) {
+				starts = append(starts, n-m)
+			}
+		case termEqual:
+			if n == m && slices.Equal(folded, t.text) {
+				starts = append(starts, 0)
+			}
+		}
+	}
+
+	if t.inverse {
+		return Result{}, len(starts) == 0
+	}
+	if len(starts) == 0 {
+		return Result{}, false
+	}
+
+	best := -1
+	var bestScore int
+	for _, start := range starts {
+		if s := runScore(text, start, m); best < 0 || s > bestScore {
+			best, bestScore = start, s
+		}
+	}
+	positions := make([]int, m)
+	for i := range positions {
+		positions[i] = best + i
+	}
+	return Result{Score: bestScore, Positions: positions}, true
+}
+
+// matchTags reports whether a tag term matches: some tag starts with the
+// term's text, ignoring case, or for a negated term, none does. Tag matches
+// add no score.
+func (t term) matchTags(tags []string) bool {
+	found := slices.ContainsFunc(tags, func(tag string) bool {
+		runes := lowerRunes([]rune(tag))
+		return len(runes) >= len(t.text) && slices.Equal(runes[:len(t.text)], t.text)
+	})
+	return found != t.inverse
+}
+
+// runScore scores a contiguous match of length runes starting at start, the
+// same way score rewards a consecutive run.
+func runScore(text []rune, start, length int) int {
+	runBonus := positionBonus(text, start)
+	total := scoreMatch + runBonus*firstCharMultiplier
+	for i := start + 1; i < start+length; i++ {
+		runBonus = max(runBonus, positionBonus(text, i), bonusConsecutive)
+		total += scoreMatch + runBonus
+	}
+	return total
+}
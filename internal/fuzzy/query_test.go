@@ -0,0 +1,125 @@
+package fuzzy_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/leeovery/portal/internal/fuzzy"
+)
+
+func TestQuery(t *testing.T) {
+	items := []string{"api-gateway", "web-api", "web-app", "portal", "Portal-docs", "api-test", "my api"}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"single fuzzy term", "wpp", []string{"web-app"}},
+		{"AND terms", "web api", []string{"web-api"}},
+		{"OR terms", "gateway | docs", []string{"api-gateway", "Portal-docs"}},
+		{"OR binds tighter than AND", "api gateway | test", []string{"api-gateway", "api-test"}},
+		{"exact", "'ptl", nil},
+		{"exact substring", "'web-a", []string{"web-api", "web-app"}},
+		{"prefix", "^api", []string{"api-gateway", "api-test"}},
+		{"suffix", "api$", []string{"web-api", "my api"}},
+		{"equal", "^portal$", []string{"portal"}},
+		{"negation", "api !test", []string{"api-gateway", "web-api", "my api"}},
+		{"negated prefix", "!^web !^api", []string{"portal", "Portal-docs", "my api"}},
+		{"negated suffix", "web !app$", []string{"web-api"}},
+		{"smart-case lower matches any case", "portal", []string{"portal", "Portal-docs"}},
+		{"smart-case upper is case sensitive", "Portal", []string{"Portal-docs"}},
+		{"escaped space", `my\ api`, []string{"my api"}},
+		{"operators alone are ignored", "! ^ | $", items},
+		{"blank query matches everything", "   ", items},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fuzzy.Filter(items, tt.query, func(s string) string { return s })
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Filter(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryPositions(t *testing.T) {
+	tests := []struct {
+		query string
+		text  string
+		want  []int
+	}{
+		{"web api", "web-api", []int{0, 1, 2, 4, 5, 6}},
+		{"^api", "api-api", []int{0, 1, 2}},
+		{"api$", "api-api", []int{4, 5, 6}},
+		{"'pi", "api", []int{1, 2}},
+		{"api !x", "api", []int{0, 1, 2}},
+		{"gw | docs", "api-gateway", []int{4, 8}},
+	}
+	for _, tt := range tests {
+		res, ok := fuzzy.ParseQuery(tt.query).Match(tt.text)
+		if !ok {
+			t.Errorf("ParseQuery(%q).Match(%q) did not match", tt.query, tt.text)
+			continue
+		}
+		if !slices.Equal(res.Positions, tt.want) {
+			t.Errorf("ParseQuery(%q).Match(%q) positions = %v, want %v", tt.query, tt.text, res.Positions, tt.want)
+		}
+	}
+}
+
+func TestQueryTags(t *testing.T) {
+	type item struct {
+		name string
+		tags []string
+	}
+	items := []item{
+		{"api", []string{"client", "work"}},
+		{"web", []string{"oss"}},
+		{"docs", nil},
+		{"cli", []string{"Client-old"}},
+	}
+	nameOf := func(i item) string { return i.name }
+	tagsOf := func(i item) []string { return i.tags }
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"tag", "#work", []string{"api"}},
+		{"tag prefix", "#cli", []string{"api", "cli"}},
+		{"tags ignore case", "#Client", []string{"api", "cli"}},
+		{"tags ignore case with smart-case names", "#Work api", []string{"api"}},
+		{"negated tag", "!#client", []string{"web", "docs"}},
+		{"tag and name", "#client cl", []string{"cli"}},
+		{"tag OR tag", "#oss | #work", []string{"api", "web"}},
+		{"lone hash is a name term", "#", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, i := range fuzzy.FilterTagged(items, tt.query, nameOf, tagsOf) {
+				got = append(got, i.name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("FilterTagged(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("untagged items never match a tag", func(t *testing.T) {
+		if got := fuzzy.Filter([]string{"client"}, "#client", func(s string) string { return s }); got != nil {
+			t.Errorf("Filter = %v, want none", got)
+		}
+	})
+
+	t.Run("WithoutTags keeps name terms for highlighting", func(t *testing.T) {
+		res, ok := fuzzy.ParseQuery("#client api").WithoutTags().Match("api")
+		if !ok || !slices.Equal(res.Positions, []int{0, 1, 2}) {
+			t.Errorf("Match = %v, %v; want positions [0 1 2]", res, ok)
+		}
+	})
+}
@@ -0,0 +1,253 @@
+// Package gitstatus reports the state of git repositories for display:
+// branch, uncommitted changes, divergence from upstream and stashes.
+package gitstatus
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leeovery/portal/internal/resolver"
+)
+
+// DefaultWorkers is the number of repositories Lookup inspects concurrently.
+const DefaultWorkers = 4
+
+// Status is the state of a git working tree.
+type Status struct {
+	// Branch is the checked-out branch, empty when HEAD is detached.
+	Branch string
+	// Commit is the abbreviated HEAD commit, set when HEAD is detached.
+	Commit string
+	// Dirty reports staged, unstaged or untracked changes.
+	Dirty bool
+	// Upstream reports whether the branch tracks an upstream branch.
+	Upstream bool
+	// Ahead and Behind count commits not in the upstream and not in the
+	// branch respectively.
+	Ahead, Behind int
+	// Stashes is the number of stash entries.
+	Stashes int
+}
+
+// String renders the status compactly, e.g. "main* ↑1 ↓2 ⚑3". A trailing *
+// marks uncommitted changes; a detached HEAD shows as its commit.
+func (s Status) String() string {
+	var b strings.Builder
+	if s.Branch != "" {
+		b.WriteString(s.Branch)
+	} else {
+		b.WriteString("@" + s.Commit)
+	}
+	if s.Dirty {
+		b.WriteString("*")
+	}
+	if s.Ahead > 0 {
+		fmt.Fprintf(&b, " ↑%d", s.Ahead)
+	}
+	if s.Behind > 0 {
+		fmt.Fprintf(&b, " ↓%d", s.Behind)
+	}
+	if s.Stashes > 0 {
+		fmt.Fprintf(&b, " ⚑%d", s.Stashes)
+	}
+	return b.String()
+}
+
+// Read runs git in dir to find its status.
+func Read(dir string, runner resolver.CommandRunner) (Status, error) {
+	out, err := runner.Run("git", "-C", dir, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to read git status: %w", err)
+	}
+	s := parsePorcelain(out)
+
+	// rev-list fails when there is no stash, which just means none.
+	if out, err := runner.Run("git", "-C", dir, "rev-list", "--walk-reflogs", "--count", "refs/stash"); err == nil {
+		s.Stashes, _ = strconv.Atoi(strings.TrimSpace(out))
+	}
+	return s, nil
+}
+
+// parsePorcelain parses the output of git status --porcelain=v2 --branch.
+func parsePorcelain(out string) Status {
+	var s Status
+	for line := range strings.Lines(out) {
+		line = strings.TrimRight(line, "\n")
+		header, ok := strings.CutPrefix(line, "# ")
+		if !ok {
+			if line != "" {
+				s.Dirty = true
+			}
+			continue
+		}
+
+		key, value, _ := strings.Cut(header, " ")
+		switch key {
+		case "branch.oid":
+			s.Commit = value[:min(len(value), 7)]
+		case "branch.head":
+			if value != "(detached)" {
+				s.Branch = value
+			}
+		case "branch.upstream":
+			s.Upstream = true
+		case "branch.ab":
+			ahead, behind, _ := strings.Cut(value, " ")
+			s.Ahead, _ = strconv.Atoi(strings.TrimPrefix(ahead, "+"))
+			s.Behind, _ = strconv.Atoi(strings.TrimPrefix(behind, "-"))
+		}
+	}
+	if s.Branch != "" {
+		s.Commit = ""
+	}
+	return s
+}
+
+// stamp records the modification times that invalidate a cached status.
+type stamp struct {
+	index, head time.Time
+}
+
+// entry is a cached status and the stamp it was read at.
+type entry struct {
+	status Status
+	stamp  stamp
+}
+
+// Cache reads statuses through a CommandRunner and remembers them per
+// directory until the repository's index or HEAD changes.
+type Cache struct {
+	runner  resolver.CommandRunner
+	workers int
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// NewCache creates a Cache that runs git through runner.
+func NewCache(runner resolver.CommandRunner) *Cache {
+	return &Cache{
+		runner:  runner,
+		workers: DefaultWorkers,
+		entries: make(map[string]entry),
+	}
+}
+
+// Get returns the status of the repository containing dir. ok is false when
+// dir is not inside a git working tree or its status cannot be read.
+func (c *Cache) Get(dir string) (status Status, ok bool) {
+	gitDir := findGitDir(dir)
+	if gitDir == "" {
+		return Status{}, false
+	}
+	st := stampOf(gitDir)
+
+	c.mu.Lock()
+	e, cached := c.entries[dir]
+	c.mu.Unlock()
+	if cached && e.stamp == st {
+		return e.status, true
+	}
+
+	status, err := Read(dir, c.runner)
+	if err != nil {
+		return Status{}, false
+	}
+
+	c.mu.Lock()
+	c.entries[dir] = entry{status: status, stamp: st}
+	c.mu.Unlock()
+	return status, true
+}
+
+// Lookup returns the statuses of dirs, read concurrently by a small pool of
+// workers. Directories that are not git working trees are left out.
+func (c *Cache) Lookup(dirs []string) map[string]Status {
+	jobs := make(chan string)
+	var (
+		mu       sync.Mutex
+		statuses = make(map[string]Status)
+		wg       sync.WaitGroup
+	)
+
+	for range min(c.workers, len(dirs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for dir := range jobs {
+				if status, ok := c.Get(dir); ok {
+					mu.Lock()
+					statuses[dir] = status
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	seen := make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		if !seen[dir] {
+			seen[dir] = true
+			jobs <- dir
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return statuses
+}
+
+// findGitDir returns the git directory of the working tree containing dir,
+// following the .git file of linked worktrees, or "" outside a working tree.
+func findGitDir(dir string) string {
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		info, err := os.Stat(dotGit)
+		if err == nil {
+			if info.IsDir() {
+				return dotGit
+			}
+			return readGitFile(dir, dotGit)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// readGitFile returns the directory a .git file points to.
+func readGitFile(dir, path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return ""
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+	return gitDir
+}
+
+// stampOf reads the modification times of a git directory's index and HEAD.
+// Missing files leave zero times.
+func stampOf(gitDir string) stamp {
+	var st stamp
+	if info, err := os.Stat(filepath.Join(gitDir, "index")); err == nil {
+		st.index = info.ModTime()
+	}
+	if info, err := os.Stat(filepath.Join(gitDir, "HEAD")); err == nil {
+		st.head = info.ModTime()
+	}
+	return st
+}
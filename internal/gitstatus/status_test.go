@@ -0,0 +1,153 @@
+package gitstatus_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/gitstatus"
+)
+
+// fakeRunner answers git status and git rev-list, counting status calls.
+type fakeRunner struct {
+	mu      sync.Mutex
+	status  string
+	stashes string
+	calls   int
+}
+
+func (f *fakeRunner) Run(name string, args ...string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case strings.Contains(strings.Join(args, " "), "status --porcelain=v2"):
+		f.calls++
+		return f.status, nil
+	case f.stashes != "":
+		return f.stashes, nil
+	default:
+		return "", errors.New("fatal: bad revision 'refs/stash'")
+	}
+}
+
+// makeRepo creates a working tree with a .git directory holding index and HEAD.
+func makeRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	gitDir := filepath.Join(dir, ".git")
+	if err := os.Mkdir(gitDir, 0o755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	for _, name := range []string{"index", "HEAD"} {
+		if err := os.WriteFile(filepath.Join(gitDir, name), nil, 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		stashes string
+		want    gitstatus.Status
+		str     string
+	}{
+		{
+			name:   "clean branch without upstream",
+			status: "# branch.oid 1234567890abcdef\n# branch.head main\n",
+			want:   gitstatus.Status{Branch: "main"},
+			str:    "main",
+		},
+		{
+			name: "dirty branch ahead and behind with stashes",
+			status: "# branch.oid 1234567890abcdef\n# branch.head feature\n# branch.upstream origin/feature\n# branch.ab +2 -1\n" +
+				"1 .M N... 100644 100644 100644 abc abc file.go\n? new.go\n",
+			stashes: "3\n",
+			want:    gitstatus.Status{Branch: "feature", Dirty: true, Upstream: true, Ahead: 2, Behind: 1, Stashes: 3},
+			str:     "feature* ↑2 ↓1 ⚑3",
+		},
+		{
+			name:   "detached head",
+			status: "# branch.oid 1234567890abcdef\n# branch.head (detached)\n",
+			want:   gitstatus.Status{Commit: "1234567"},
+			str:    "@1234567",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := gitstatus.Read("/repo", &fakeRunner{status: tt.status, stashes: tt.stashes})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Read() = %+v, want %+v", got, tt.want)
+			}
+			if got.String() != tt.str {
+				t.Errorf("String() = %q, want %q", got.String(), tt.str)
+			}
+		})
+	}
+}
+
+func TestCache(t *testing.T) {
+	t.Run("caches until the index changes", func(t *testing.T) {
+		dir := makeRepo(t)
+		runner := &fakeRunner{status: "# branch.head main\n"}
+		cache := gitstatus.NewCache(runner)
+
+		for range 2 {
+			if got, ok := cache.Get(dir); !ok || got.Branch != "main" {
+				t.Fatalf("Get() = %+v, %v; want main", got, ok)
+			}
+		}
+		if runner.calls != 1 {
+			t.Errorf("git status ran %d times, want 1", runner.calls)
+		}
+
+		runner.status = "# branch.head main\n? new.go\n"
+		later := time.Now().Add(time.Minute)
+		if err := os.Chtimes(filepath.Join(dir, ".git", "index"), later, later); err != nil {
+			t.Fatalf("failed to touch index: %v", err)
+		}
+		if got, _ := cache.Get(dir); !got.Dirty {
+			t.Errorf("Get() after index change = %+v, want dirty", got)
+		}
+		if runner.calls != 2 {
+			t.Errorf("git status ran %d times, want 2", runner.calls)
+		}
+	})
+
+	t.Run("follows the .git file of a worktree", func(t *testing.T) {
+		repo := makeRepo(t)
+		worktree := t.TempDir()
+		if err := os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: "+filepath.Join(repo, ".git")+"\n"), 0o644); err != nil {
+			t.Fatalf("failed to write .git file: %v", err)
+		}
+
+		cache := gitstatus.NewCache(&fakeRunner{status: "# branch.head wt\n"})
+		if got, ok := cache.Get(worktree); !ok || got.Branch != "wt" {
+			t.Errorf("Get() = %+v, %v; want wt", got, ok)
+		}
+	})
+
+	t.Run("lookup skips directories outside a repository", func(t *testing.T) {
+		repo := makeRepo(t)
+		plain := t.TempDir()
+		runner := &fakeRunner{status: "# branch.head main\n"}
+
+		got := gitstatus.NewCache(runner).Lookup([]string{repo, plain, repo})
+		if len(got) != 1 || got[repo].Branch != "main" {
+			t.Errorf("Lookup() = %+v, want only %s", got, repo)
+		}
+		if runner.calls != 1 {
+			t.Errorf("git status ran %d times, want 1", runner.calls)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package layout
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// Target is the set of tmux operations needed to replay a layout.
+// It is satisfied by *tmux.Client.
+type Target interface {
+	ActivePane(target string) (tmux.PaneRef, error)
+	NewWindow(sessionName, windowName, dir string) (tmux.PaneRef, error)
+	RenameWindow(target, name string) error
+	SplitWindow(target, dir string, horizontal bool, size string) (string, error)
+	SendKeys(target, command string) error
+	SelectLayout(target, layout string) error
+	SelectPane(target string) error
+	SelectWindow(target string) error
+}
+
+// Apply replays l onto the named session, which must already exist with its
+// initial window started in baseDir. The initial window becomes the layout's
+// first window; further windows are appended. Relative directories resolve
+// against baseDir. When skipFirstCommand is true, the first pane's command is
+// not sent because the session was started with its own command.
+func Apply(t Target, sessionName, baseDir string, l *Layout, skipFirstCommand bool) error {
+	var focusWindow string
+
+	for wi, w := range l.Windows {
+		windowDir := resolveDir(baseDir, w.Dir)
+
+		var ref tmux.PaneRef
+		var err error
+		if wi == 0 {
+			ref, err = t.ActivePane(sessionName + ":")
+			if err != nil {
+				return err
+			}
+			if w.Name != "" {
+				if err := t.RenameWindow(ref.WindowID, w.Name); err != nil {
+					return err
+				}
+			}
+		} else {
+			ref, err = t.NewWindow(sessionName, w.Name, resolveDir(windowDir, firstPaneDir(w)))
+			if err != nil {
+				return err
+			}
+		}
+
+		panes := w.Panes
+		if len(panes) == 0 {
+			panes = []Pane{{}}
+		}
+
+		paneIDs := make([]string, len(panes))
+		paneIDs[0] = ref.PaneID
+		for pi := 1; pi < len(panes); pi++ {
+			p := panes[pi]
+			id, err := t.SplitWindow(paneIDs[pi-1], resolveDir(windowDir, p.Dir), p.Split == SplitHorizontal, p.Size)
+			if err != nil {
+				return err
+			}
+			paneIDs[pi] = id
+		}
+
+		for pi, p := range panes {
+			if p.Command == "" || (wi == 0 && pi == 0 && skipFirstCommand) {
+				continue
+			}
+			if err := t.SendKeys(paneIDs[pi], p.Command); err != nil {
+				return err
+			}
+		}
+
+		if w.Layout != "" {
+			if err := t.SelectLayout(ref.WindowID, w.Layout); err != nil {
+				return err
+			}
+		}
+
+		for pi, p := range panes {
+			if p.Focus {
+				if err := t.SelectPane(paneIDs[pi]); err != nil {
+					return err
+				}
+			}
+		}
+
+		if w.Focus {
+			focusWindow = ref.WindowID
+		}
+	}
+
+	if focusWindow != "" {
+		return t.SelectWindow(focusWindow)
+	}
+
+	return nil
+}
+
+// firstPaneDir returns the configured directory of a window's first pane.
+func firstPaneDir(w Window) string {
+	if len(w.Panes) == 0 {
+		return ""
+	}
+	return w.Panes[0].Dir
+}
+
+// resolveDir resolves dir against base. Empty dirs resolve to base, a leading
+// ~ expands to the home directory and absolute dirs are returned unchanged.
+func resolveDir(base, dir string) string {
+	if dir == "" {
+		return base
+	}
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, dir[1:])
+		}
+	}
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(base, dir)
+}
+
+// Applier looks up project layouts in a Store and replays them through a Target.
+type Applier struct {
+	store  *Store
+	target Target
+}
+
+// NewApplier creates an Applier that reads layouts from store and applies them via target.
+func NewApplier(store *Store, target Target) *Applier {
+	return &Applier{store: store, target: target}
+}
+
+// HasLayout reports whether a layout is defined for the given project name.
+func (a *Applier) HasLayout(projectName string) bool {
+	return a.store.Exists(projectName)
+}
+
+// ApplyLayout loads the layout for projectName and replays it onto the named
+// session. It is a no-op when the project has no layout.
+func (a *Applier) ApplyLayout(sessionName, dir, projectName string, skipFirstCommand bool) error {
+	l, err := a.store.Load(projectName)
+	if err != nil {
+		return err
+	}
+	if l == nil {
+		return nil
+	}
+	if err := Apply(a.target, sessionName, dir, l, skipFirstCommand); err != nil {
+		return fmt.Errorf("failed to apply layout for %q: %w", projectName, err)
+	}
+	return nil
+}
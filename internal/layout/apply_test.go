@@ -0,0 +1,176 @@
+package layout_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leeovery/portal/internal/layout"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// recordingCommander implements tmux.Commander, recording every call and
+// handing out sequential window and pane IDs like a real tmux server.
+type recordingCommander struct {
+	calls      []string
+	nextWindow int
+	nextPane   int
+	failOn     string
+}
+
+func (r *recordingCommander) Run(args ...string) (string, error) {
+	r.calls = append(r.calls, strings.Join(args, " "))
+	if r.failOn != "" && args[0] == r.failOn {
+		return "", fmt.Errorf("tmux error")
+	}
+	switch args[0] {
+	case "display-message":
+		return "@0|%0", nil
+	case "new-window":
+		r.nextWindow++
+		r.nextPane++
+		return fmt.Sprintf("@%d|%%%d", r.nextWindow, r.nextPane), nil
+	case "split-window":
+		r.nextPane++
+		return fmt.Sprintf("%%%d", r.nextPane), nil
+	}
+	return "", nil
+}
+
+func TestApply(t *testing.T) {
+	t.Run("replays windows, splits, commands and focus in order", func(t *testing.T) {
+		cmd := &recordingCommander{}
+		client := tmux.NewClient(cmd)
+		l := &layout.Layout{Windows: []layout.Window{
+			{Name: "editor", Layout: "main-vertical", Panes: []layout.Pane{
+				{Command: "nvim", Focus: true},
+				{Split: "horizontal", Size: "30%", Dir: "src", Command: "make watch"},
+			}},
+			{Name: "server", Dir: "api", Focus: true, Panes: []layout.Pane{
+				{Command: "make dev"},
+				{Dir: "/var/log", Command: "tail -f app.log"},
+			}},
+			{Name: "shell"},
+		}}
+
+		err := layout.Apply(client, "myapp-abc123", "/code/myapp", l, false)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{
+			"display-message -p -t myapp-abc123: #{window_id}|#{pane_id}",
+			"rename-window -t @0 editor",
+			"split-window -d -h -t %0 -c /code/myapp/src -l 30% -P -F #{pane_id}",
+			"send-keys -t %0 nvim Enter",
+			"send-keys -t %1 make watch Enter",
+			"select-layout -t @0 main-vertical",
+			"select-pane -t %0",
+			"new-window -d -t myapp-abc123: -c /code/myapp/api -P -F #{window_id}|#{pane_id} -n server",
+			"split-window -d -v -t %2 -c /var/log -P -F #{pane_id}",
+			"send-keys -t %2 make dev Enter",
+			"send-keys -t %3 tail -f app.log Enter",
+			"new-window -d -t myapp-abc123: -c /code/myapp -P -F #{window_id}|#{pane_id} -n shell",
+			"select-window -t @1",
+		}
+		assertCalls(t, cmd.calls, want)
+	})
+
+	t.Run("skips first pane command when session runs its own command", func(t *testing.T) {
+		cmd := &recordingCommander{}
+		client := tmux.NewClient(cmd)
+		l := &layout.Layout{Windows: []layout.Window{
+			{Panes: []layout.Pane{
+				{Command: "nvim"},
+				{Command: "make dev"},
+			}},
+		}}
+
+		err := layout.Apply(client, "myapp-abc123", "/code/myapp", l, true)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{
+			"display-message -p -t myapp-abc123: #{window_id}|#{pane_id}",
+			"split-window -d -v -t %0 -c /code/myapp -P -F #{pane_id}",
+			"send-keys -t %1 make dev Enter",
+		}
+		assertCalls(t, cmd.calls, want)
+	})
+
+	t.Run("stops at first tmux failure", func(t *testing.T) {
+		cmd := &recordingCommander{failOn: "split-window"}
+		client := tmux.NewClient(cmd)
+		l := &layout.Layout{Windows: []layout.Window{
+			{Panes: []layout.Pane{{Command: "nvim"}, {Command: "make dev"}}},
+		}}
+
+		err := layout.Apply(client, "myapp-abc123", "/code/myapp", l, false)
+
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		for _, call := range cmd.calls {
+			if strings.HasPrefix(call, "send-keys") {
+				t.Errorf("no keys should be sent after a failed split, got %q", call)
+			}
+		}
+	})
+}
+
+func TestApplier(t *testing.T) {
+	t.Run("applies the layout stored for the project", func(t *testing.T) {
+		dir := t.TempDir()
+		content := `{"windows":[{"name":"main","panes":[{"command":"htop"}]}]}`
+		if err := os.WriteFile(filepath.Join(dir, "myapp.json"), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+		cmd := &recordingCommander{}
+		applier := layout.NewApplier(layout.NewStore(dir), tmux.NewClient(cmd))
+
+		if !applier.HasLayout("myapp") {
+			t.Fatal("HasLayout() = false, want true")
+		}
+		err := applier.ApplyLayout("myapp-abc123", "/code/myapp", "myapp", false)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{
+			"display-message -p -t myapp-abc123: #{window_id}|#{pane_id}",
+			"rename-window -t @0 main",
+			"send-keys -t %0 htop Enter",
+		}
+		assertCalls(t, cmd.calls, want)
+	})
+
+	t.Run("is a no-op when project has no layout", func(t *testing.T) {
+		cmd := &recordingCommander{}
+		applier := layout.NewApplier(layout.NewStore(t.TempDir()), tmux.NewClient(cmd))
+
+		if applier.HasLayout("myapp") {
+			t.Error("HasLayout() = true, want false")
+		}
+		if err := applier.ApplyLayout("myapp-abc123", "/code/myapp", "myapp", false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(cmd.calls) != 0 {
+			t.Errorf("expected no tmux calls, got %v", cmd.calls)
+		}
+	})
+}
+
+func assertCalls(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d calls:\n%s\nwant %d calls:\n%s", len(got), strings.Join(got, "\n"), len(want), strings.Join(want, "\n"))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("call[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
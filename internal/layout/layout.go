@@ -0,0 +1,102 @@
+// Package layout provides declarative window and pane layouts that are
+// replayed onto newly created tmux sessions.
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// Split directions for a pane relative to the pane before it.
+const (
+	SplitVertical   = "vertical"
+	SplitHorizontal = "horizontal"
+)
+
+// sizePattern matches tmux split sizes: a cell count or a percentage.
+var sizePattern = regexp.MustCompile(`^[1-9][0-9]*%?$`)
+
+// Pane describes a single pane within a window.
+type Pane struct {
+	// Dir is the pane's start directory. Relative paths resolve against the
+	// window directory. Defaults to the window directory.
+	Dir string `json:"dir,omitempty"`
+	// Command is typed into the pane's shell once the pane exists.
+	Command string `json:"command,omitempty"`
+	// Split is how this pane is split from the previous pane: "vertical"
+	// (stacked, the default) or "horizontal" (side by side). Ignored for the
+	// first pane of a window.
+	Split string `json:"split,omitempty"`
+	// Size is the new pane's size as cells ("20") or a percentage ("30%").
+	Size string `json:"size,omitempty"`
+	// Focus marks the pane as the active pane of its window.
+	Focus bool `json:"focus,omitempty"`
+}
+
+// Window describes a single window and the panes within it.
+type Window struct {
+	// Name is the window name shown in the tmux status bar.
+	Name string `json:"name,omitempty"`
+	// Dir is the default start directory for the window's panes. Relative
+	// paths resolve against the project directory.
+	Dir string `json:"dir,omitempty"`
+	// Layout is a tmux layout applied after all panes exist
+	// (e.g. "tiled", "main-vertical", "even-horizontal").
+	Layout string `json:"layout,omitempty"`
+	// Panes lists the window's panes in creation order. An empty list
+	// means a single pane with no command.
+	Panes []Pane `json:"panes,omitempty"`
+	// Focus marks the window as the session's current window.
+	Focus bool `json:"focus,omitempty"`
+}
+
+// Layout is a declarative description of a session's windows and panes.
+type Layout struct {
+	Windows []Window `json:"windows"`
+}
+
+// Validate checks the layout for structural errors and returns a
+// descriptive error for the first problem found.
+func (l *Layout) Validate() error {
+	if len(l.Windows) == 0 {
+		return errors.New("layout must define at least one window")
+	}
+
+	focusedWindows := 0
+	for wi, w := range l.Windows {
+		if w.Focus {
+			focusedWindows++
+		}
+		if wi == 0 && w.Dir != "" {
+			return errors.New("windows[0]: dir cannot be set; the first window starts in the project directory")
+		}
+
+		focusedPanes := 0
+		for pi, p := range w.Panes {
+			if p.Focus {
+				focusedPanes++
+			}
+			if wi == 0 && pi == 0 && p.Dir != "" {
+				return errors.New("windows[0].panes[0]: dir cannot be set; the first pane starts in the project directory")
+			}
+			switch p.Split {
+			case "", SplitVertical, SplitHorizontal:
+			default:
+				return fmt.Errorf("windows[%d].panes[%d]: invalid split %q (want %q or %q)", wi, pi, p.Split, SplitVertical, SplitHorizontal)
+			}
+			if p.Size != "" && !sizePattern.MatchString(p.Size) {
+				return fmt.Errorf("windows[%d].panes[%d]: invalid size %q (want cells like \"20\" or a percentage like \"30%%\")", wi, pi, p.Size)
+			}
+		}
+		if focusedPanes > 1 {
+			return fmt.Errorf("windows[%d]: only one pane may have focus", wi)
+		}
+	}
+
+	if focusedWindows > 1 {
+		return errors.New("only one window may have focus")
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package layout_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leeovery/portal/internal/layout"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  layout.Layout
+		wantErr string
+	}{
+		{
+			name: "accepts a single bare window",
+			layout: layout.Layout{Windows: []layout.Window{
+				{Name: "main"},
+			}},
+		},
+		{
+			name: "accepts splits, sizes and one focused pane per window",
+			layout: layout.Layout{Windows: []layout.Window{
+				{Name: "editor", Panes: []layout.Pane{
+					{Command: "nvim", Focus: true},
+					{Split: "horizontal", Size: "30%", Dir: "src"},
+					{Split: "vertical", Size: "10"},
+				}},
+				{Name: "logs", Dir: "var/log", Focus: true},
+			}},
+		},
+		{
+			name:    "rejects empty layout",
+			layout:  layout.Layout{},
+			wantErr: "at least one window",
+		},
+		{
+			name: "rejects dir on first window",
+			layout: layout.Layout{Windows: []layout.Window{
+				{Dir: "src"},
+			}},
+			wantErr: "windows[0]: dir cannot be set",
+		},
+		{
+			name: "rejects dir on first pane of first window",
+			layout: layout.Layout{Windows: []layout.Window{
+				{Panes: []layout.Pane{{Dir: "src"}}},
+			}},
+			wantErr: "windows[0].panes[0]: dir cannot be set",
+		},
+		{
+			name: "rejects unknown split direction",
+			layout: layout.Layout{Windows: []layout.Window{
+				{Panes: []layout.Pane{{}, {Split: "diagonal"}}},
+			}},
+			wantErr: `windows[0].panes[1]: invalid split "diagonal"`,
+		},
+		{
+			name: "rejects malformed size",
+			layout: layout.Layout{Windows: []layout.Window{
+				{Panes: []layout.Pane{{}, {Size: "half"}}},
+			}},
+			wantErr: `windows[0].panes[1]: invalid size "half"`,
+		},
+		{
+			name: "rejects two focused panes in one window",
+			layout: layout.Layout{Windows: []layout.Window{
+				{Panes: []layout.Pane{{Focus: true}, {Focus: true}}},
+			}},
+			wantErr: "windows[0]: only one pane may have focus",
+		},
+		{
+			name: "rejects two focused windows",
+			layout: layout.Layout{Windows: []layout.Window{
+				{Focus: true},
+				{Focus: true},
+			}},
+			wantErr: "only one window may have focus",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.layout.Validate()
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want it to contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package layout
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Store reads layouts from a directory containing one <project>.json file per project.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a Store that reads layout files from the given directory.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Path returns the layout file path for the given project name.
+func (s *Store) Path(projectName string) string {
+	return filepath.Join(s.dir, projectName+".json")
+}
+
+// Exists reports whether a layout file exists for the given project name.
+func (s *Store) Exists(projectName string) bool {
+	_, err := os.Stat(s.Path(projectName))
+	return err == nil
+}
+
+// Load reads and validates the layout for the given project name.
+// Returns nil and no error when the project has no layout file.
+// Unlike the project store, malformed files are reported as errors because
+// layouts are written by hand.
+func (s *Store) Load(projectName string) (*Layout, error) {
+	path := s.Path(projectName)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read layout file: %w", err)
+	}
+
+	var l Layout
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("invalid layout %s: %w", path, err)
+	}
+
+	if err := l.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid layout %s: %w", path, err)
+	}
+
+	return &l, nil
+}
@@ -0,0 +1,85 @@
+package layout_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leeovery/portal/internal/layout"
+)
+
+func TestStoreLoad(t *testing.T) {
+	t.Run("returns nil when project has no layout file", func(t *testing.T) {
+		store := layout.NewStore(t.TempDir())
+
+		got, err := store.Load("myapp")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != nil {
+			t.Errorf("Load() = %+v, want nil", got)
+		}
+		if store.Exists("myapp") {
+			t.Error("Exists() = true, want false")
+		}
+	})
+
+	t.Run("loads layout from <project>.json", func(t *testing.T) {
+		dir := t.TempDir()
+		content := `{"windows":[{"name":"editor","panes":[{"command":"nvim"},{"split":"horizontal","command":"make dev"}]}]}`
+		if err := os.WriteFile(filepath.Join(dir, "myapp.json"), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+		store := layout.NewStore(dir)
+
+		got, err := store.Load("myapp")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !store.Exists("myapp") {
+			t.Error("Exists() = false, want true")
+		}
+		if len(got.Windows) != 1 || got.Windows[0].Name != "editor" {
+			t.Fatalf("Load() windows = %+v, want one window named editor", got.Windows)
+		}
+		if len(got.Windows[0].Panes) != 2 || got.Windows[0].Panes[1].Split != "horizontal" {
+			t.Errorf("Load() panes = %+v, want two panes with horizontal second split", got.Windows[0].Panes)
+		}
+	})
+
+	t.Run("reports malformed JSON with file path", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "myapp.json")
+		if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+
+		_, err := layout.NewStore(dir).Load("myapp")
+
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), path) {
+			t.Errorf("error = %q, want it to contain %q", err.Error(), path)
+		}
+	})
+
+	t.Run("reports validation errors", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "myapp.json"), []byte(`{"windows":[]}`), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+
+		_, err := layout.NewStore(dir).Load("myapp")
+
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "at least one window") {
+			t.Errorf("error = %q, want validation message", err.Error())
+		}
+	})
+}
@@ -0,0 +1,82 @@
+// Package mux selects the terminal multiplexer Portal drives: tmux or Zellij.
+package mux
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/leeovery/portal/internal/tmux"
+	"github.com/leeovery/portal/internal/zellij"
+)
+
+// Backend names.
+const (
+	Tmux   = "tmux"
+	Zellij = "zellij"
+)
+
+// DefaultBackend is the backend used when none is configured.
+const DefaultBackend = Tmux
+
+// Backends lists the accepted backend names.
+var Backends = []string{Tmux, Zellij}
+
+// Session is a session as listed by any backend. Fields a backend cannot
+// report are left zero.
+type Session = tmux.Session
+
+// Backend is the set of session operations Portal needs from a multiplexer.
+type Backend interface {
+	// Name returns the backend's name, one of Backends.
+	Name() string
+	// Available reports, with install instructions, when the multiplexer is
+	// not installed.
+	Available() error
+	// Inside reports whether Portal is running inside one of the
+	// multiplexer's sessions.
+	Inside() bool
+	// CurrentSessionName returns the session Portal is running inside.
+	CurrentSessionName() (string, error)
+
+	ListSessions() ([]Session, error)
+	HasSession(name string) bool
+	// NewSession creates a detached session in dir, running shellCommand
+	// when it is non-empty.
+	NewSession(name, dir, shellCommand string) error
+	KillSession(name string) error
+	RenameSession(oldName, newName string) error
+	// SwitchClient moves the client Portal runs in to the named session.
+	SwitchClient(name string) error
+
+	// AttachArgs returns the argv Portal execs to attach to a session from
+	// outside the multiplexer.
+	AttachArgs(name string) []string
+	// CreateOrAttachArgs returns the argv Portal execs to attach to a
+	// session, creating it in dir running shellCommand when it does not
+	// exist. It returns nil when the multiplexer cannot do both at once.
+	CreateOrAttachArgs(name, dir, shellCommand string) []string
+}
+
+// ParseBackend validates a backend name. An empty name selects DefaultBackend.
+func ParseBackend(name string) (string, error) {
+	if name == "" {
+		return DefaultBackend, nil
+	}
+	if !slices.Contains(Backends, name) {
+		return "", fmt.Errorf("unknown backend %q (want one of: %s)", name, strings.Join(Backends, ", "))
+	}
+	return name, nil
+}
+
+// New returns the named backend, driving the real multiplexer.
+func New(name string) (Backend, error) {
+	name, err := ParseBackend(name)
+	if err != nil {
+		return nil, err
+	}
+	if name == Zellij {
+		return NewZellij(&zellij.RealCommander{}), nil
+	}
+	return NewTmux(&tmux.RealCommander{}), nil
+}
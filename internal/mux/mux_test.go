@@ -0,0 +1,317 @@
+package mux_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/leeovery/portal/internal/mux"
+)
+
+// fakeServer is an in-memory multiplexer server. Each backend's fake
+// commander translates its command line into operations on it, so one
+// contract suite can exercise both backends.
+type fakeServer struct {
+	names    []string
+	dirs     map[string]string
+	commands map[string]string
+	current  string
+}
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{dirs: map[string]string{}, commands: map[string]string{}}
+}
+
+var errNoSession = errors.New("exit status 1")
+
+func (s *fakeServer) has(name string) bool { return slices.Contains(s.names, name) }
+
+func (s *fakeServer) create(name, dir, command string) error {
+	if s.has(name) {
+		return fmt.Errorf("duplicate session: %s", name)
+	}
+	s.names = append(s.names, name)
+	s.dirs[name] = dir
+	if command != "" {
+		s.commands[name] = command
+	}
+	return nil
+}
+
+func (s *fakeServer) kill(name string) error {
+	i := slices.Index(s.names, name)
+	if i < 0 {
+		return errNoSession
+	}
+	s.names = slices.Delete(s.names, i, i+1)
+	return nil
+}
+
+func (s *fakeServer) rename(oldName, newName string) error {
+	i := slices.Index(s.names, oldName)
+	if i < 0 {
+		return errNoSession
+	}
+	s.names[i] = newName
+	return nil
+}
+
+func (s *fakeServer) switchTo(name string) error {
+	if !s.has(name) {
+		return errNoSession
+	}
+	s.current = name
+	return nil
+}
+
+// fakeTmux answers tmux command lines from a fakeServer.
+type fakeTmux struct{ s *fakeServer }
+
+func (f *fakeTmux) Run(args ...string) (string, error) {
+	switch args[0] {
+	case "list-sessions":
+		if len(f.s.names) == 0 {
+			return "", errors.New("no server running")
+		}
+		lines := make([]string, 0, len(f.s.names))
+		for _, name := range f.s.names {
+			lines = append(lines, fmt.Sprintf("1\t0\t\t\t\t\tzsh\t%s\t%s", f.s.dirs[name], name))
+		}
+		return strings.Join(lines, "\n"), nil
+	case "has-session":
+		if !f.s.has(args[2]) {
+			return "", errNoSession
+		}
+		return "", nil
+	case "new-session":
+		command := ""
+		if len(args) > 6 {
+			command = args[6]
+		}
+		return "", f.s.create(args[3], args[5], command)
+	case "kill-session":
+		return "", f.s.kill(args[2])
+	case "rename-session":
+		return "", f.s.rename(args[2], args[3])
+	case "switch-client":
+		return "", f.s.switchTo(args[2])
+	}
+	return "", fmt.Errorf("unexpected tmux command: %v", args)
+}
+
+// fakeZellij answers zellij command lines from a fakeServer.
+type fakeZellij struct{ s *fakeServer }
+
+func (f *fakeZellij) Run(args ...string) (string, error) {
+	target := ""
+	if args[0] == "--session" {
+		target, args = args[1], args[2:]
+	}
+	switch {
+	case args[0] == "list-sessions":
+		if len(f.s.names) == 0 {
+			return "", errors.New("exit status 1")
+		}
+		lines := make([]string, 0, len(f.s.names))
+		for _, name := range f.s.names {
+			lines = append(lines, name+" [Created 1m 5s ago]")
+		}
+		return strings.Join(lines, "\n"), nil
+	case args[0] == "attach" && args[1] == "--create-background":
+		return "", f.s.create(args[2], args[5], "")
+	case args[0] == "run":
+		f.s.commands[target] = args[len(args)-1]
+		return "", nil
+	case args[0] == "delete-session":
+		return "", f.s.kill(args[2])
+	case args[0] == "action" && args[1] == "rename-session":
+		return "", f.s.rename(target, args[2])
+	case args[0] == "action" && args[1] == "switch-session":
+		return "", f.s.switchTo(args[2])
+	}
+	return "", fmt.Errorf("unexpected zellij command: %v", args)
+}
+
+// backends builds each backend over a fresh fake server.
+var backends = map[string]func(*fakeServer) mux.Backend{
+	mux.Tmux:   func(s *fakeServer) mux.Backend { return mux.NewTmux(&fakeTmux{s}) },
+	mux.Zellij: func(s *fakeServer) mux.Backend { return mux.NewZellij(&fakeZellij{s}) },
+}
+
+// TestBackendContract runs the same behavioural checks against every backend.
+func TestBackendContract(t *testing.T) {
+	for name, newBackend := range backends {
+		t.Run(name, func(t *testing.T) {
+			testBackend(t, name, newBackend)
+		})
+	}
+}
+
+func testBackend(t *testing.T, name string, newBackend func(*fakeServer) mux.Backend) {
+	t.Run("reports its name", func(t *testing.T) {
+		if got := newBackend(newFakeServer()).Name(); got != name {
+			t.Errorf("Name() = %q, want %q", got, name)
+		}
+	})
+
+	t.Run("lists nothing without sessions", func(t *testing.T) {
+		b := newBackend(newFakeServer())
+		sessions, err := b.ListSessions()
+		if err != nil || len(sessions) != 0 {
+			t.Errorf("ListSessions() = %v, %v; want none", sessions, err)
+		}
+		if b.HasSession("api") {
+			t.Error("HasSession() = true without sessions")
+		}
+	})
+
+	t.Run("creates and lists sessions", func(t *testing.T) {
+		server := newFakeServer()
+		b := newBackend(server)
+		for _, n := range []string{"api", "web"} {
+			if err := b.NewSession(n, "/code/"+n, ""); err != nil {
+				t.Fatalf("NewSession(%q) error: %v", n, err)
+			}
+		}
+
+		sessions, err := b.ListSessions()
+		if err != nil {
+			t.Fatalf("ListSessions() error: %v", err)
+		}
+		var names []string
+		for _, s := range sessions {
+			names = append(names, s.Name)
+		}
+		if !slices.Equal(names, []string{"api", "web"}) {
+			t.Errorf("listed %v, want [api web]", names)
+		}
+		if !b.HasSession("api") || b.HasSession("docs") {
+			t.Error("HasSession() disagrees with the listed sessions")
+		}
+		if server.dirs["api"] != "/code/api" {
+			t.Errorf("session started in %q, want /code/api", server.dirs["api"])
+		}
+	})
+
+	t.Run("creates sessions running a command", func(t *testing.T) {
+		server := newFakeServer()
+		b := newBackend(server)
+		if err := b.NewSession("api", "/code/api", "zsh -ic 'claude; exec zsh'"); err != nil {
+			t.Fatalf("NewSession() error: %v", err)
+		}
+		if server.commands["api"] != "zsh -ic 'claude; exec zsh'" {
+			t.Errorf("ran %q, want the shell command", server.commands["api"])
+		}
+	})
+
+	t.Run("fails to create a duplicate session", func(t *testing.T) {
+		b := newBackend(newFakeServer())
+		_ = b.NewSession("api", "/code/api", "")
+		if err := b.NewSession("api", "/code/api", ""); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("renames sessions", func(t *testing.T) {
+		b := newBackend(newFakeServer())
+		_ = b.NewSession("api", "/code/api", "")
+		if err := b.RenameSession("api", "backend"); err != nil {
+			t.Fatalf("RenameSession() error: %v", err)
+		}
+		if b.HasSession("api") || !b.HasSession("backend") {
+			t.Error("session not renamed")
+		}
+	})
+
+	t.Run("kills sessions", func(t *testing.T) {
+		b := newBackend(newFakeServer())
+		_ = b.NewSession("api", "/code/api", "")
+		if err := b.KillSession("api"); err != nil {
+			t.Fatalf("KillSession() error: %v", err)
+		}
+		if b.HasSession("api") {
+			t.Error("session still exists")
+		}
+		if err := b.KillSession("api"); err == nil {
+			t.Error("expected an error killing a missing session")
+		}
+	})
+
+	t.Run("switches the client", func(t *testing.T) {
+		server := newFakeServer()
+		b := newBackend(server)
+		_ = b.NewSession("api", "/code/api", "")
+		if err := b.SwitchClient("api"); err != nil {
+			t.Fatalf("SwitchClient() error: %v", err)
+		}
+		if server.current != "api" {
+			t.Errorf("switched to %q, want api", server.current)
+		}
+		if err := b.SwitchClient("docs"); err == nil {
+			t.Error("expected an error switching to a missing session")
+		}
+	})
+
+	t.Run("attaches by execing the multiplexer", func(t *testing.T) {
+		b := newBackend(newFakeServer())
+		args := b.AttachArgs("api")
+		if len(args) == 0 || args[0] != name || !slices.Contains(args, "api") {
+			t.Errorf("AttachArgs() = %v, want %s naming the session", args, name)
+		}
+	})
+
+	t.Run("creates or attaches in the session directory", func(t *testing.T) {
+		b := newBackend(newFakeServer())
+		args := b.CreateOrAttachArgs("api", "/code/api", "")
+		if len(args) == 0 || args[0] != name || !slices.Contains(args, "api") || !slices.Contains(args, "/code/api") {
+			t.Errorf("CreateOrAttachArgs() = %v, want %s naming the session and directory", args, name)
+		}
+		if args := b.CreateOrAttachArgs("api", "/code/api", "claude"); args != nil && !slices.Contains(args, "claude") {
+			t.Errorf("CreateOrAttachArgs() = %v drops the command", args)
+		}
+	})
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "", want: mux.Tmux},
+		{name: "tmux", want: mux.Tmux},
+		{name: "zellij", want: mux.Zellij},
+		{name: "screen", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := mux.New(tt.name)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if b.Name() != tt.want {
+				t.Errorf("New(%q).Name() = %q, want %q", tt.name, b.Name(), tt.want)
+			}
+		})
+	}
+
+	t.Run("tmux client is only available from tmux", func(t *testing.T) {
+		tmuxBackend, _ := mux.New(mux.Tmux)
+		zellijBackend, _ := mux.New(mux.Zellij)
+		if _, ok := mux.TmuxClient(tmuxBackend); !ok {
+			t.Error("TmuxClient() not available from tmux")
+		}
+		if _, ok := mux.TmuxClient(zellijBackend); ok {
+			t.Error("TmuxClient() available from zellij")
+		}
+	})
+}
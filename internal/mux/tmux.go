@@ -0,0 +1,49 @@
+package mux
+
+import "github.com/leeovery/portal/internal/tmux"
+
+// TmuxBackend drives tmux. It embeds the tmux client, so the tmux-only
+// operations Portal uses for layouts, pane previews and detaching clients
+// stay available to callers that check for it.
+type TmuxBackend struct {
+	*tmux.Client
+}
+
+// NewTmux creates a tmux backend that runs tmux through cmd.
+func NewTmux(cmd tmux.Commander) *TmuxBackend {
+	return &TmuxBackend{Client: tmux.NewClient(cmd)}
+}
+
+// Name returns "tmux".
+func (b *TmuxBackend) Name() string { return Tmux }
+
+// Available reports when tmux is not installed.
+func (b *TmuxBackend) Available() error { return tmux.CheckTmuxAvailable() }
+
+// Inside reports whether Portal is running inside a tmux session.
+func (b *TmuxBackend) Inside() bool { return tmux.InsideTmux() }
+
+// AttachArgs returns tmux attach-session argv.
+func (b *TmuxBackend) AttachArgs(name string) []string {
+	return []string{"tmux", "attach-session", "-t", name}
+}
+
+// CreateOrAttachArgs returns tmux new-session -A argv, which attaches to the
+// session or creates it atomically.
+func (b *TmuxBackend) CreateOrAttachArgs(name, dir, shellCommand string) []string {
+	args := []string{"tmux", "new-session", "-A", "-s", name, "-c", dir}
+	if shellCommand != "" {
+		args = append(args, shellCommand)
+	}
+	return args
+}
+
+// TmuxClient returns the tmux client behind b when b drives tmux, for the
+// features only tmux supports.
+func TmuxClient(b Backend) (*tmux.Client, bool) {
+	tb, ok := b.(*TmuxBackend)
+	if !ok {
+		return nil, false
+	}
+	return tb.Client, true
+}
@@ -0,0 +1,62 @@
+package mux
+
+import "github.com/leeovery/portal/internal/zellij"
+
+// ZellijBackend drives Zellij.
+type ZellijBackend struct {
+	*zellij.Client
+}
+
+// NewZellij creates a Zellij backend that runs zellij through cmd.
+func NewZellij(cmd zellij.Commander) *ZellijBackend {
+	return &ZellijBackend{Client: zellij.NewClient(cmd)}
+}
+
+// Name returns "zellij".
+func (b *ZellijBackend) Name() string { return Zellij }
+
+// Available reports when zellij is not installed.
+func (b *ZellijBackend) Available() error { return zellij.CheckZellijAvailable() }
+
+// Inside reports whether Portal is running inside a Zellij session.
+func (b *ZellijBackend) Inside() bool { return zellij.InsideZellij() }
+
+// CurrentSessionName returns the Zellij session Portal is running inside.
+func (b *ZellijBackend) CurrentSessionName() (string, error) {
+	return zellij.CurrentSessionName()
+}
+
+// ListSessions lists Zellij sessions, including exited ones. Zellij does
+// not report windows, clients or activity; the session Portal runs in is
+// reported as attached.
+func (b *ZellijBackend) ListSessions() ([]Session, error) {
+	listed, err := b.Client.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]Session, 0, len(listed))
+	for _, s := range listed {
+		sessions = append(sessions, Session{
+			Name:     s.Name,
+			Created:  s.Created,
+			Attached: s.Current,
+			Exited:   s.Exited,
+		})
+	}
+	return sessions, nil
+}
+
+// AttachArgs returns zellij attach argv, which also resurrects exited sessions.
+func (b *ZellijBackend) AttachArgs(name string) []string {
+	return []string{"zellij", "attach", name}
+}
+
+// CreateOrAttachArgs returns zellij attach --create argv. Zellij cannot start
+// a session running a command that way, so it returns nil when shellCommand
+// is set and the session must be created first.
+func (b *ZellijBackend) CreateOrAttachArgs(name, dir, shellCommand string) []string {
+	if shellCommand != "" {
+		return nil
+	}
+	return []string{"zellij", "attach", "--create", name, "options", "--default-cwd", dir}
+}
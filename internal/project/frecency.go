@@ -0,0 +1,135 @@
+package project
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Order is a strategy for ordering remembered projects.
+type Order string
+
+const (
+	// OrderFrecency ranks projects by how often and how recently they were used.
+	OrderFrecency Order = "frecency"
+	// OrderRecency ranks the most recently used project first.
+	OrderRecency Order = "recency"
+	// OrderFrequency ranks the most often used project first.
+	OrderFrequency Order = "frequency"
+	// OrderAlphabetical sorts projects by name.
+	OrderAlphabetical Order = "alphabetical"
+)
+
+// DefaultOrder is the order used when none is configured.
+const DefaultOrder = OrderFrecency
+
+// Orders lists every supported order.
+var Orders = []Order{OrderFrecency, OrderRecency, OrderFrequency, OrderAlphabetical}
+
+// ParseOrder returns the Order named by s.
+func ParseOrder(s string) (Order, error) {
+	if order := Order(s); slices.Contains(Orders, order) {
+		return order, nil
+	}
+	names := make([]string, len(Orders))
+	for i, o := range Orders {
+		names[i] = string(o)
+	}
+	return "", fmt.Errorf("unknown order %q (valid: %s)", s, strings.Join(names, ", "))
+}
+
+// maxVisits is how many recent visits each project keeps for scoring.
+const maxVisits = 10
+
+// visitWeights weights a visit by its age: the first bucket whose age limit
+// the visit falls within gives its weight, and older visits weigh 10.
+var visitWeights = []struct {
+	age    time.Duration
+	weight float64
+}{
+	{4 * time.Hour, 100},
+	{24 * time.Hour, 80},
+	{7 * 24 * time.Hour, 60},
+	{30 * 24 * time.Hour, 40},
+	{90 * 24 * time.Hour, 20},
+}
+
+// visitWeight returns the weight of a visit at t as seen at now.
+func visitWeight(t, now time.Time) float64 {
+	age := now.Sub(t)
+	for _, w := range visitWeights {
+		if age <= w.age {
+			return w.weight
+		}
+	}
+	return 10
+}
+
+// Frecency scores how often and how recently p was used: its total use count
+// multiplied by the average age weight of its recent visits. A project opened
+// thirty times today outranks one opened once an hour ago.
+func (p Project) Frecency(now time.Time) float64 {
+	if len(p.Visits) == 0 {
+		return 0
+	}
+	var total float64
+	for _, v := range p.Visits {
+		total += visitWeight(v, now)
+	}
+	return float64(p.UseCount) * total / float64(len(p.Visits))
+}
+
+// recordVisit counts a use of p at now, keeping only the most recent visits.
+func (p *Project) recordVisit(now time.Time) {
+	p.UseCount++
+	p.LastUsed = now
+	p.Visits = append(p.Visits, now)
+	if extra := len(p.Visits) - maxVisits; extra > 0 {
+		p.Visits = slices.Delete(p.Visits, 0, extra)
+	}
+}
+
+// migrate fills in usage data for projects saved before it was tracked,
+// counting the last use as the only visit. Projects never used, such as
+// imported ones, are left with no uses.
+func (p *Project) migrate() {
+	if p.UseCount > 0 || p.LastUsed.IsZero() {
+		return
+	}
+	if len(p.Visits) == 0 && !p.LastUsed.IsZero() {
+		p.Visits = []time.Time{p.LastUsed}
+	}
+	p.UseCount = max(len(p.Visits), 1)
+}
+
+// SortProjects orders projects in place by order as of now. Ties fall back to
+// the most recently used, then to the name.
+func SortProjects(projects []Project, order Order, now time.Time) {
+	byRecency := func(a, b Project) int {
+		if c := b.LastUsed.Compare(a.LastUsed); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	}
+
+	var compare func(a, b Project) int
+	switch order {
+	case OrderRecency:
+		compare = byRecency
+	case OrderFrequency:
+		compare = func(a, b Project) int {
+			return cmp.Or(cmp.Compare(b.UseCount, a.UseCount), byRecency(a, b))
+		}
+	case OrderAlphabetical:
+		compare = func(a, b Project) int {
+			return cmp.Or(strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)), strings.Compare(a.Path, b.Path))
+		}
+	default:
+		compare = func(a, b Project) int {
+			return cmp.Or(cmp.Compare(b.Frecency(now), a.Frecency(now)), byRecency(a, b))
+		}
+	}
+	slices.SortStableFunc(projects, compare)
+}
@@ -16,6 +16,12 @@ type Project struct {
 	Path     string    `json:"path"`
 	Name     string    `json:"name"`
 	LastUsed time.Time `json:"last_used"`
+	// UseCount is how many times the project has been opened.
+	UseCount int `json:"use_count,omitempty"`
+	// Visits holds the times of the most recent uses, oldest first.
+	Visits []time.Time `json:"visits,omitempty"`
+	// Tags are free-form labels for grouping projects, filtered by #tag.
+	Tags []string `json:"tags,omitempty"`
 }
 
 // projectsFile is the on-disk JSON structure for projects.json.
@@ -25,16 +31,26 @@ type projectsFile struct {
 
 // Store manages persistence of project data to a JSON file.
 type Store struct {
-	path string
+	path  string
+	order Order
 }
 
 // NewStore creates a Store that reads and writes to the given file path.
+// List orders projects by DefaultOrder unless WithOrder changes it.
 func NewStore(path string) *Store {
-	return &Store{path: path}
+	return &Store{path: path, order: DefaultOrder}
+}
+
+// WithOrder sets the order List returns projects in.
+func (s *Store) WithOrder(order Order) *Store {
+	s.order = order
+	return s
 }
 
 // Load reads projects from the JSON file.
 // Returns an empty slice when the file is missing or contains malformed JSON.
+// Projects saved before use counts were tracked are migrated as having been
+// used once, at their last-used time; the migration is saved with the next write.
 func (s *Store) Load() ([]Project, error) {
 	data, err := os.ReadFile(s.path)
 	if err != nil {
@@ -49,6 +65,9 @@ func (s *Store) Load() ([]Project, error) {
 		return []Project{}, nil
 	}
 
+	for i := range f.Projects {
+		f.Projects[i].migrate()
+	}
 	return f.Projects, nil
 }
 
@@ -92,8 +111,8 @@ func (s *Store) Save(projects []Project) error {
 }
 
 // Upsert adds a new project or updates an existing one matched by path.
-// The LastUsed timestamp is set to the current time. If the project already
-// exists (matched by Path), its Name and LastUsed are updated.
+// Either way it records a use at the current time, updating LastUsed, the use
+// count and recent visits. An existing project's Name is also updated.
 func (s *Store) Upsert(path, name string) error {
 	projects, err := s.Load()
 	if err != nil {
@@ -106,37 +125,108 @@ func (s *Store) Upsert(path, name string) error {
 	for i := range projects {
 		if projects[i].Path == path {
 			projects[i].Name = name
-			projects[i].LastUsed = now
+			projects[i].recordVisit(now)
 			found = true
 			break
 		}
 	}
 
 	if !found {
-		projects = append(projects, Project{
-			Path:     path,
-			Name:     name,
-			LastUsed: now,
-		})
+		p := Project{Path: path, Name: name}
+		p.recordVisit(now)
+		projects = append(projects, p)
 	}
 
 	return s.Save(projects)
 }
 
-// List returns all projects sorted by LastUsed in descending order (most recent first).
+// Add adds a new project or renames an existing one matched by path,
+// without recording a use: LastUsed, the use count and recent visits are left
+// as they are.
+func (s *Store) Add(path, name string) error {
+	projects, err := s.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load projects: %w", err)
+	}
+
+	i := slices.IndexFunc(projects, func(p Project) bool { return p.Path == path })
+	if i >= 0 {
+		projects[i].Name = name
+	} else {
+		projects = append(projects, Project{Path: path, Name: name})
+	}
+
+	return s.Save(projects)
+}
+
+// Import adds each project whose path is not already stored, without
+// recording a use, and returns the projects added. Existing projects are
+// left untouched. The file is only saved if a project was added.
+func (s *Store) Import(candidates []Project) ([]Project, error) {
+	projects, err := s.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load projects: %w", err)
+	}
+
+	known := make(map[string]bool, len(projects))
+	for _, p := range projects {
+		known[p.Path] = true
+	}
+
+	var added []Project
+	for _, c := range candidates {
+		if known[c.Path] {
+			continue
+		}
+		known[c.Path] = true
+		added = append(added, c)
+	}
+
+	if len(added) == 0 {
+		return nil, nil
+	}
+	if err := s.Save(append(projects, added...)); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// List returns all projects in the store's order, frecency by default.
 func (s *Store) List() ([]Project, error) {
 	projects, err := s.Load()
 	if err != nil {
 		return nil, err
 	}
 
-	slices.SortFunc(projects, func(a, b Project) int {
-		return b.LastUsed.Compare(a.LastUsed)
-	})
+	SortProjects(projects, s.order, time.Now())
 
 	return projects, nil
 }
 
+// Missing reports whether path no longer exists. Permission errors and other
+// failures count as present, so unreadable directories are never cleaned.
+func Missing(path string) bool {
+	_, err := os.Stat(path)
+	return errors.Is(err, os.ErrNotExist)
+}
+
+// Stale returns the projects whose directories no longer exist on disk,
+// without removing them. Projects with permission errors are not stale.
+func (s *Store) Stale() ([]Project, error) {
+	projects, err := s.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load projects: %w", err)
+	}
+
+	var stale []Project
+	for _, p := range projects {
+		if Missing(p.Path) {
+			stale = append(stale, p)
+		}
+	}
+	return stale, nil
+}
+
 // CleanStale removes projects whose directories no longer exist on disk.
 // Projects with permission errors are retained. Returns the removed projects.
 // The file is only saved if at least one project was removed.
@@ -150,14 +240,9 @@ func (s *Store) CleanStale() ([]Project, error) {
 	var removed []Project
 
 	for _, p := range projects {
-		_, statErr := os.Stat(p.Path)
-		switch {
-		case statErr == nil:
-			kept = append(kept, p)
-		case errors.Is(statErr, os.ErrNotExist):
+		if Missing(p.Path) {
 			removed = append(removed, p)
-		default:
-			// Permission denied or other errors: retain the project
+		} else {
 			kept = append(kept, p)
 		}
 	}
@@ -171,6 +256,48 @@ func (s *Store) CleanStale() ([]Project, error) {
 	return removed, nil
 }
 
+// Duplicate is a project whose directory resolves to the same real path as
+// another project, the one that is kept.
+type Duplicate struct {
+	Project Project
+	Kept    Project
+}
+
+// Duplicates returns the projects whose directories resolve, through
+// symlinks, to the same real path as another project. Of each group the most
+// recently used project is kept and the others are returned, in file order.
+// Directories that cannot be resolved are skipped.
+func (s *Store) Duplicates() ([]Duplicate, error) {
+	projects, err := s.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load projects: %w", err)
+	}
+
+	real := make([]string, len(projects))
+	kept := make(map[string]Project)
+	for i, p := range projects {
+		resolved, err := filepath.EvalSymlinks(p.Path)
+		if err != nil {
+			continue
+		}
+		real[i] = resolved
+		if k, ok := kept[resolved]; !ok || p.LastUsed.After(k.LastUsed) {
+			kept[resolved] = p
+		}
+	}
+
+	var duplicates []Duplicate
+	for i, p := range projects {
+		if real[i] == "" {
+			continue
+		}
+		if k := kept[real[i]]; k.Path != p.Path {
+			duplicates = append(duplicates, Duplicate{Project: p, Kept: k})
+		}
+	}
+	return duplicates, nil
+}
+
 // Rename updates the display name of the project matched by path.
 // It does not change the LastUsed timestamp. It is a no-op if the path is not found.
 func (s *Store) Rename(path, newName string) error {
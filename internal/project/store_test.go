@@ -3,6 +3,8 @@ package project_test
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -581,3 +583,440 @@ func TestCleanStale(t *testing.T) {
 		}
 	})
 }
+
+func TestStale(t *testing.T) {
+	t.Run("lists missing directories without removing them", func(t *testing.T) {
+		dir := t.TempDir()
+		filePath := filepath.Join(dir, "projects.json")
+		existingDir := t.TempDir()
+		staleDir := filepath.Join(dir, "gone")
+
+		content := `{"projects":[
+			{"path":"` + existingDir + `","name":"exists","last_used":"2026-01-01T00:00:00Z"},
+			{"path":"` + staleDir + `","name":"stale","last_used":"2026-02-01T00:00:00Z"}
+		]}`
+		if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+
+		store := project.NewStore(filePath)
+		stale, err := store.Stale()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(stale) != 1 || stale[0].Path != staleDir {
+			t.Errorf("stale = %+v, want only %s", stale, staleDir)
+		}
+		projects, _ := store.Load()
+		if len(projects) != 2 {
+			t.Errorf("got %d projects, want both kept", len(projects))
+		}
+	})
+
+	t.Run("permission errors are not stale", func(t *testing.T) {
+		dir := t.TempDir()
+		parentDir := filepath.Join(dir, "restricted")
+		childDir := filepath.Join(parentDir, "child")
+		if err := os.MkdirAll(childDir, 0o755); err != nil {
+			t.Fatalf("failed to create child dir: %v", err)
+		}
+		if err := os.Chmod(parentDir, 0o000); err != nil {
+			t.Fatalf("failed to chmod: %v", err)
+		}
+		t.Cleanup(func() { _ = os.Chmod(parentDir, 0o755) })
+
+		if project.Missing(childDir) {
+			t.Errorf("Missing(%q) = true, want false for an unreadable directory", childDir)
+		}
+	})
+}
+
+func TestDuplicates(t *testing.T) {
+	t.Run("keeps the most recently used of projects with the same real path", func(t *testing.T) {
+		dir := t.TempDir()
+		filePath := filepath.Join(dir, "projects.json")
+		realDir := filepath.Join(dir, "api")
+		linkDir := filepath.Join(dir, "api-link")
+		otherDir := filepath.Join(dir, "web")
+		for _, d := range []string{realDir, otherDir} {
+			if err := os.Mkdir(d, 0o755); err != nil {
+				t.Fatalf("failed to create dir: %v", err)
+			}
+		}
+		if err := os.Symlink(realDir, linkDir); err != nil {
+			t.Fatalf("failed to create symlink: %v", err)
+		}
+
+		content := `{"projects":[
+			{"path":"` + realDir + `","name":"api","last_used":"2026-01-01T00:00:00Z"},
+			{"path":"` + otherDir + `","name":"web","last_used":"2026-01-01T00:00:00Z"},
+			{"path":"` + linkDir + `","name":"api-link","last_used":"2026-02-01T00:00:00Z"}
+		]}`
+		if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+
+		duplicates, err := project.NewStore(filePath).Duplicates()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(duplicates) != 1 {
+			t.Fatalf("got %d duplicates, want 1: %+v", len(duplicates), duplicates)
+		}
+		if duplicates[0].Project.Path != realDir || duplicates[0].Kept.Path != linkDir {
+			t.Errorf("duplicate = %s kept %s, want %s kept %s", duplicates[0].Project.Path, duplicates[0].Kept.Path, realDir, linkDir)
+		}
+	})
+
+	t.Run("skips missing directories", func(t *testing.T) {
+		dir := t.TempDir()
+		filePath := filepath.Join(dir, "projects.json")
+		content := `{"projects":[
+			{"path":"` + filepath.Join(dir, "gone") + `","name":"a","last_used":"2026-01-01T00:00:00Z"},
+			{"path":"` + filepath.Join(dir, "gone") + `/","name":"b","last_used":"2026-01-01T00:00:00Z"}
+		]}`
+		if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+
+		duplicates, err := project.NewStore(filePath).Duplicates()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(duplicates) != 0 {
+			t.Errorf("duplicates = %+v, want none", duplicates)
+		}
+	})
+}
+
+func TestUsageTracking(t *testing.T) {
+	t.Run("upsert counts uses and keeps recent visits", func(t *testing.T) {
+		store := project.NewStore(filepath.Join(t.TempDir(), "projects.json"))
+
+		for range 12 {
+			if err := store.Upsert("/code/app", "app"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		projects, err := store.Load()
+		if err != nil {
+			t.Fatalf("failed to load: %v", err)
+		}
+		if projects[0].UseCount != 12 {
+			t.Errorf("UseCount = %d, want 12", projects[0].UseCount)
+		}
+		if len(projects[0].Visits) != 10 {
+			t.Errorf("kept %d visits, want the 10 most recent", len(projects[0].Visits))
+		}
+		if !projects[0].Visits[9].Equal(projects[0].LastUsed) {
+			t.Errorf("last visit %v should equal LastUsed %v", projects[0].Visits[9], projects[0].LastUsed)
+		}
+	})
+
+	t.Run("files without usage fields migrate on load", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "projects.json")
+		content := `{"projects":[{"path":"/code/app","name":"app","last_used":"2026-01-22T10:30:00Z"}]}`
+		if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+
+		store := project.NewStore(filePath)
+		projects, err := store.Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		p := projects[0]
+		lastUsed := time.Date(2026, 1, 22, 10, 30, 0, 0, time.UTC)
+		if p.UseCount != 1 || len(p.Visits) != 1 || !p.Visits[0].Equal(lastUsed) {
+			t.Errorf("migrated to UseCount=%d Visits=%v, want one visit at %v", p.UseCount, p.Visits, lastUsed)
+		}
+
+		if err := store.Upsert("/code/app", "app"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		projects, _ = store.Load()
+		if projects[0].UseCount != 2 || len(projects[0].Visits) != 2 {
+			t.Errorf("after a use got UseCount=%d Visits=%v, want 2 of each", projects[0].UseCount, projects[0].Visits)
+		}
+	})
+}
+
+func TestFrecency(t *testing.T) {
+	now := time.Date(2026, 6, 1, 18, 0, 0, 0, time.UTC)
+	visits := func(n int, age time.Duration) []time.Time {
+		v := make([]time.Time, n)
+		for i := range v {
+			v[i] = now.Add(-age)
+		}
+		return v
+	}
+
+	daily := project.Project{Name: "daily", UseCount: 30, Visits: visits(10, 2*time.Hour), LastUsed: now.Add(-2 * time.Hour)}
+	once := project.Project{Name: "once", UseCount: 1, Visits: visits(1, time.Minute), LastUsed: now.Add(-time.Minute)}
+	stale := project.Project{Name: "stale", UseCount: 30, Visits: visits(10, 200*24*time.Hour), LastUsed: now.Add(-200 * 24 * time.Hour)}
+
+	if !(daily.Frecency(now) > once.Frecency(now)) {
+		t.Errorf("frequently used project should outrank one used once more recently: %v <= %v", daily.Frecency(now), once.Frecency(now))
+	}
+	if !(daily.Frecency(now) > stale.Frecency(now)) {
+		t.Errorf("recent uses should outweigh old ones: %v <= %v", daily.Frecency(now), stale.Frecency(now))
+	}
+	if got := (project.Project{}).Frecency(now); got != 0 {
+		t.Errorf("unused project scored %v, want 0", got)
+	}
+}
+
+func TestSortProjects(t *testing.T) {
+	now := time.Date(2026, 6, 1, 18, 0, 0, 0, time.UTC)
+	projects := []project.Project{
+		{Name: "once", Path: "/once", UseCount: 1, Visits: []time.Time{now.Add(-time.Minute)}, LastUsed: now.Add(-time.Minute)},
+		{Name: "Busy", Path: "/busy", UseCount: 30, Visits: []time.Time{now.Add(-time.Hour)}, LastUsed: now.Add(-time.Hour)},
+		{Name: "archived", Path: "/archived", UseCount: 50, Visits: []time.Time{now.Add(-365 * 24 * time.Hour)}, LastUsed: now.Add(-365 * 24 * time.Hour)},
+	}
+
+	tests := []struct {
+		order project.Order
+		want  []string
+	}{
+		{project.OrderFrecency, []string{"Busy", "archived", "once"}},
+		{project.OrderRecency, []string{"once", "Busy", "archived"}},
+		{project.OrderFrequency, []string{"archived", "Busy", "once"}},
+		{project.OrderAlphabetical, []string{"archived", "Busy", "once"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			sorted := slices.Clone(projects)
+			project.SortProjects(sorted, tt.order, now)
+
+			var got []string
+			for _, p := range sorted {
+				got = append(got, p.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("parse rejects unknown orders", func(t *testing.T) {
+		if _, err := project.ParseOrder("popularity"); err == nil {
+			t.Error("expected error for unknown order")
+		}
+		if order, err := project.ParseOrder("recency"); err != nil || order != project.OrderRecency {
+			t.Errorf("ParseOrder(recency) = %q, %v", order, err)
+		}
+	})
+}
+
+func TestSetTags(t *testing.T) {
+	t.Run("tags round-trip through save and load", func(t *testing.T) {
+		dir := t.TempDir()
+		filePath := filepath.Join(dir, "projects.json")
+		store := project.NewStore(filePath)
+
+		if err := store.Upsert("/code/api", "api"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.SetTags("/code/api", []string{"#Client, oss", "client"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		projects, err := project.NewStore(filePath).Load()
+		if err != nil {
+			t.Fatalf("failed to load: %v", err)
+		}
+		if want := []string{"client", "oss"}; len(projects) != 1 || !slices.Equal(projects[0].Tags, want) {
+			t.Fatalf("Tags = %v, want %v", projects, want)
+		}
+
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		if !strings.Contains(string(data), `"tags"`) {
+			t.Errorf("file does not store tags:\n%s", data)
+		}
+	})
+
+	t.Run("upsert keeps tags", func(t *testing.T) {
+		store := project.NewStore(filepath.Join(t.TempDir(), "projects.json"))
+		if err := store.Upsert("/code/api", "api"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.SetTags("/code/api", []string{"work"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.Upsert("/code/api", "api"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		projects, err := store.Load()
+		if err != nil {
+			t.Fatalf("failed to load: %v", err)
+		}
+		if !slices.Equal(projects[0].Tags, []string{"work"}) {
+			t.Errorf("Tags = %v, want [work]", projects[0].Tags)
+		}
+	})
+
+	t.Run("clearing tags omits them from the file", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "projects.json")
+		store := project.NewStore(filePath)
+		if err := store.Upsert("/code/api", "api"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.SetTags("/code/api", []string{"work"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.SetTags("/code/api", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		if strings.Contains(string(data), `"tags"`) {
+			t.Errorf("file still stores tags:\n%s", data)
+		}
+	})
+
+	t.Run("no error for nonexistent path", func(t *testing.T) {
+		store := project.NewStore(filepath.Join(t.TempDir(), "projects.json"))
+		if err := store.SetTags("/nonexistent", []string{"work"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "nil", tags: nil, want: nil},
+		{name: "splits on commas and spaces", tags: []string{"client, oss work"}, want: []string{"client", "oss", "work"}},
+		{name: "drops hash and lower-cases", tags: []string{"#Client", "##OSS"}, want: []string{"client", "oss"}},
+		{name: "drops empty and duplicate tags", tags: []string{"", "#", "b", "a", "b"}, want: []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := project.NormalizeTags(tt.tags)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("NormalizeTags(%q) = %q, want %q", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTags(t *testing.T) {
+	if got := project.FormatTags([]string{"client", "oss"}); got != "#client #oss" {
+		t.Errorf("FormatTags = %q, want %q", got, "#client #oss")
+	}
+	if got := project.FormatTags(nil); got != "" {
+		t.Errorf("FormatTags(nil) = %q, want empty", got)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	store := project.NewStore(filepath.Join(t.TempDir(), "projects.json"))
+	if err := store.Upsert("/code/api", "api"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	before, err := store.Load()
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+
+	if err := store.Add("/code/api", "renamed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.Add("/code/web", "web"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	projects, err := store.Load()
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("got %d projects, want 2", len(projects))
+	}
+	if api := projects[0]; api.Name != "renamed" || api.UseCount != 1 || !api.LastUsed.Equal(before[0].LastUsed) {
+		t.Errorf("existing project should only be renamed: %+v", api)
+	}
+	if web := projects[1]; web.UseCount != 0 || !web.LastUsed.IsZero() || len(web.Visits) != 0 {
+		t.Errorf("added project has uses: %+v", web)
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Run("adds new projects without recording a use", func(t *testing.T) {
+		store := project.NewStore(filepath.Join(t.TempDir(), "projects.json"))
+		if err := store.Upsert("/code/api", "api"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		added, err := store.Import([]project.Project{
+			{Path: "/code/api", Name: "other"},
+			{Path: "/code/web", Name: "web"},
+			{Path: "/code/web", Name: "web"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(added) != 1 || added[0].Path != "/code/web" {
+			t.Fatalf("added = %+v, want only /code/web", added)
+		}
+
+		projects, err := store.Load()
+		if err != nil {
+			t.Fatalf("failed to load: %v", err)
+		}
+		if len(projects) != 2 {
+			t.Fatalf("got %d projects, want 2", len(projects))
+		}
+		if projects[0].Name != "api" || projects[0].UseCount != 1 {
+			t.Errorf("existing project changed: %+v", projects[0])
+		}
+		if web := projects[1]; web.UseCount != 0 || !web.LastUsed.IsZero() || len(web.Visits) != 0 {
+			t.Errorf("imported project has uses: %+v", web)
+		}
+	})
+
+	t.Run("imported projects rank after used ones", func(t *testing.T) {
+		store := project.NewStore(filepath.Join(t.TempDir(), "projects.json"))
+		if _, err := store.Import([]project.Project{{Path: "/code/a", Name: "a"}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.Upsert("/code/z", "z"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		projects, err := store.List()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if projects[0].Name != "z" {
+			t.Errorf("order = %v, want z first", projects)
+		}
+	})
+
+	t.Run("nothing to add leaves the file alone", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "projects.json")
+		added, err := project.NewStore(filePath).Import(nil)
+		if err != nil || added != nil {
+			t.Fatalf("Import(nil) = %v, %v", added, err)
+		}
+		if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+			t.Errorf("file should not be created, stat err = %v", err)
+		}
+	})
+}
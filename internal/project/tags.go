@@ -0,0 +1,56 @@
+package project
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// NormalizeTags cleans up tags as typed by the user. Each entry may hold
+// several tags separated by commas or spaces; a leading # is dropped and
+// tags are lower-cased. The result is sorted and free of duplicates.
+func NormalizeTags(tags []string) []string {
+	var normalized []string
+	for _, entry := range tags {
+		words := strings.FieldsFunc(entry, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+		for _, word := range words {
+			if tag := strings.ToLower(strings.TrimLeft(word, "#")); tag != "" {
+				normalized = append(normalized, tag)
+			}
+		}
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// FormatTags renders tags as space-separated #tag tokens, the form they are
+// filtered by.
+func FormatTags(tags []string) string {
+	tokens := make([]string, len(tags))
+	for i, tag := range tags {
+		tokens[i] = "#" + tag
+	}
+	return strings.Join(tokens, " ")
+}
+
+// SetTags replaces the tags of the project matched by path. Tags are
+// normalized with NormalizeTags. It does not change the LastUsed timestamp.
+// It is a no-op if the path is not found.
+func (s *Store) SetTags(path string, tags []string) error {
+	projects, err := s.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load projects: %w", err)
+	}
+
+	for i := range projects {
+		if projects[i].Path == path {
+			projects[i].Tags = NormalizeTags(tags)
+			return s.Save(projects)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+// Package registry records which project each Portal-created tmux session
+// belongs to, so sessions keep their origin across renames.
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Entry records the origin of a single tmux session.
+type Entry struct {
+	Name        string    `json:"name"`
+	ProjectPath string    `json:"project_path"`
+	ProjectName string    `json:"project_name"`
+	Command     []string  `json:"command,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// sessionsFile is the on-disk JSON structure for sessions.json.
+type sessionsFile struct {
+	Sessions []Entry `json:"sessions"`
+}
+
+// Store manages persistence of session registry data to a JSON file.
+type Store struct {
+	path string
+}
+
+// NewStore creates a Store that reads and writes to the given file path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load reads entries from the JSON file.
+// Returns an empty slice when the file is missing or contains malformed JSON.
+func (s *Store) Load() ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []Entry{}, nil
+		}
+		return nil, err
+	}
+
+	var f sessionsFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return []Entry{}, nil
+	}
+
+	return f.Sessions, nil
+}
+
+// Save writes entries to the JSON file using atomic write (temp file + rename).
+// Creates the parent directory if it does not exist.
+func (s *Store) Save(entries []Entry) error {
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	f := sessionsFile{Sessions: entries}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal sessions: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "sessions-*.json.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	return nil
+}
+
+// Record adds or replaces the entry for the named session.
+// The project name is derived from the base of projectPath and CreatedAt is
+// set to the current time.
+func (s *Store) Record(name, projectPath string, command []string) error {
+	entries, err := s.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	entries = slices.DeleteFunc(entries, func(e Entry) bool {
+		return e.Name == name
+	})
+	entries = append(entries, Entry{
+		Name:        name,
+		ProjectPath: projectPath,
+		ProjectName: filepath.Base(projectPath),
+		Command:     command,
+		CreatedAt:   time.Now().UTC(),
+	})
+
+	return s.Save(entries)
+}
+
+// ByName returns all entries keyed by session name.
+func (s *Store) ByName() (map[string]Entry, error) {
+	entries, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]Entry, len(entries))
+	for _, e := range entries {
+		byName[e.Name] = e
+	}
+	return byName, nil
+}
+
+// Rename moves the entry for oldName to newName. It is a no-op if oldName
+// is not registered.
+func (s *Store) Rename(oldName, newName string) error {
+	entries, err := s.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	idx := slices.IndexFunc(entries, func(e Entry) bool { return e.Name == oldName })
+	if idx < 0 {
+		return nil
+	}
+
+	entries = slices.DeleteFunc(entries, func(e Entry) bool { return e.Name == newName })
+	idx = slices.IndexFunc(entries, func(e Entry) bool { return e.Name == oldName })
+	entries[idx].Name = newName
+
+	return s.Save(entries)
+}
+
+// Remove deletes the entry for the named session. It is a no-op if the
+// session is not registered.
+func (s *Store) Remove(name string) error {
+	entries, err := s.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	kept := slices.DeleteFunc(slices.Clone(entries), func(e Entry) bool {
+		return e.Name == name
+	})
+	if len(kept) == len(entries) {
+		return nil
+	}
+
+	return s.Save(kept)
+}
+
+// Prune removes entries whose sessions are not in the live set, such as
+// sessions killed outside Portal. Returns the removed entries.
+// The file is only saved if at least one entry was removed.
+func (s *Store) Prune(live []string) ([]Entry, error) {
+	entries, err := s.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	var kept []Entry
+	var removed []Entry
+	for _, e := range entries {
+		if slices.Contains(live, e.Name) {
+			kept = append(kept, e)
+		} else {
+			removed = append(removed, e)
+		}
+	}
+
+	if len(removed) > 0 {
+		if err := s.Save(kept); err != nil {
+			return nil, fmt.Errorf("failed to save after pruning sessions: %w", err)
+		}
+	}
+
+	return removed, nil
+}
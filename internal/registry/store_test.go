@@ -0,0 +1,157 @@
+package registry_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/registry"
+)
+
+func TestRecord(t *testing.T) {
+	t.Run("records project path, name, command and creation time", func(t *testing.T) {
+		store := registry.NewStore(filepath.Join(t.TempDir(), "sessions.json"))
+		before := time.Now().UTC().Add(-time.Second)
+
+		if err := store.Record("myapp-abc123", "/code/myapp", []string{"make", "dev"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		entries, err := store.Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 1 {
+			t.Fatalf("got %d entries, want 1", len(entries))
+		}
+		e := entries[0]
+		if e.Name != "myapp-abc123" || e.ProjectPath != "/code/myapp" || e.ProjectName != "myapp" {
+			t.Errorf("entry = %+v, want name myapp-abc123 for /code/myapp", e)
+		}
+		if len(e.Command) != 2 || e.Command[0] != "make" || e.Command[1] != "dev" {
+			t.Errorf("Command = %v, want [make dev]", e.Command)
+		}
+		if e.CreatedAt.Before(before) {
+			t.Errorf("CreatedAt = %v, want after %v", e.CreatedAt, before)
+		}
+	})
+
+	t.Run("replaces existing entry with the same name", func(t *testing.T) {
+		store := registry.NewStore(filepath.Join(t.TempDir(), "sessions.json"))
+
+		_ = store.Record("dev", "/code/old", nil)
+		if err := store.Record("dev", "/code/new", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		byName, err := store.ByName()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(byName) != 1 || byName["dev"].ProjectPath != "/code/new" {
+			t.Errorf("ByName() = %+v, want single dev entry for /code/new", byName)
+		}
+	})
+}
+
+func TestLoadMissingAndMalformed(t *testing.T) {
+	t.Run("returns empty slice when file does not exist", func(t *testing.T) {
+		store := registry.NewStore(filepath.Join(t.TempDir(), "missing", "sessions.json"))
+
+		entries, err := store.Load()
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("got %d entries, want 0", len(entries))
+		}
+	})
+
+	t.Run("returns empty slice for malformed JSON", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "sessions.json")
+		if err := os.WriteFile(path, []byte("{broken"), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+
+		entries, err := registry.NewStore(path).Load()
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("got %d entries, want 0", len(entries))
+		}
+	})
+}
+
+func TestRename(t *testing.T) {
+	t.Run("moves entry to the new name and keeps its project", func(t *testing.T) {
+		store := registry.NewStore(filepath.Join(t.TempDir(), "sessions.json"))
+		_ = store.Record("myapp-abc123", "/code/myapp", nil)
+
+		if err := store.Rename("myapp-abc123", "api"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		byName, _ := store.ByName()
+		if _, ok := byName["myapp-abc123"]; ok {
+			t.Error("old name should no longer be registered")
+		}
+		if byName["api"].ProjectPath != "/code/myapp" {
+			t.Errorf("renamed entry project = %q, want /code/myapp", byName["api"].ProjectPath)
+		}
+	})
+
+	t.Run("no-op for unregistered session", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "sessions.json")
+		store := registry.NewStore(path)
+
+		if err := store.Rename("ghost", "other"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Error("file should not be created for a no-op rename")
+		}
+	})
+}
+
+func TestRemoveAndPrune(t *testing.T) {
+	t.Run("remove forgets a single session", func(t *testing.T) {
+		store := registry.NewStore(filepath.Join(t.TempDir(), "sessions.json"))
+		_ = store.Record("a", "/code/a", nil)
+		_ = store.Record("b", "/code/b", nil)
+
+		if err := store.Remove("a"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		byName, _ := store.ByName()
+		if _, ok := byName["a"]; ok {
+			t.Error("a should have been removed")
+		}
+		if _, ok := byName["b"]; !ok {
+			t.Error("b should be retained")
+		}
+	})
+
+	t.Run("prune removes sessions that are no longer live", func(t *testing.T) {
+		store := registry.NewStore(filepath.Join(t.TempDir(), "sessions.json"))
+		_ = store.Record("live", "/code/live", nil)
+		_ = store.Record("dead", "/code/dead", nil)
+
+		removed, err := store.Prune([]string{"live", "unregistered"})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(removed) != 1 || removed[0].Name != "dead" {
+			t.Errorf("removed = %+v, want [dead]", removed)
+		}
+		byName, _ := store.ByName()
+		if len(byName) != 1 {
+			t.Errorf("got %d remaining entries, want 1", len(byName))
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package registry
+
+import "fmt"
+
+// SessionOps performs the tmux operations that the registry must follow.
+type SessionOps interface {
+	KillSession(name string) error
+	RenameSession(oldName, newName string) error
+}
+
+// Tracker wraps tmux session operations and keeps the registry in sync with
+// them. It satisfies the killer and renamer interfaces used by cmd and tui.
+type Tracker struct {
+	ops   SessionOps
+	store *Store
+}
+
+// NewTracker creates a Tracker that applies ops and mirrors them into store.
+func NewTracker(ops SessionOps, store *Store) *Tracker {
+	return &Tracker{ops: ops, store: store}
+}
+
+// KillSession kills the tmux session and forgets its registry entry.
+func (t *Tracker) KillSession(name string) error {
+	if err := t.ops.KillSession(name); err != nil {
+		return err
+	}
+	if err := t.store.Remove(name); err != nil {
+		return fmt.Errorf("failed to update session registry: %w", err)
+	}
+	return nil
+}
+
+// RenameSession renames the tmux session and moves its registry entry.
+func (t *Tracker) RenameSession(oldName, newName string) error {
+	if err := t.ops.RenameSession(oldName, newName); err != nil {
+		return err
+	}
+	if err := t.store.Rename(oldName, newName); err != nil {
+		return fmt.Errorf("failed to update session registry: %w", err)
+	}
+	return nil
+}
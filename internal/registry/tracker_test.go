@@ -0,0 +1,76 @@
+package registry_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/leeovery/portal/internal/registry"
+)
+
+// mockSessionOps implements registry.SessionOps for testing.
+type mockSessionOps struct {
+	killed  string
+	renamed [2]string
+	err     error
+}
+
+func (m *mockSessionOps) KillSession(name string) error {
+	m.killed = name
+	return m.err
+}
+
+func (m *mockSessionOps) RenameSession(oldName, newName string) error {
+	m.renamed = [2]string{oldName, newName}
+	return m.err
+}
+
+func TestTracker(t *testing.T) {
+	t.Run("kill removes the registry entry", func(t *testing.T) {
+		store := registry.NewStore(filepath.Join(t.TempDir(), "sessions.json"))
+		_ = store.Record("dev", "/code/dev", nil)
+		ops := &mockSessionOps{}
+
+		if err := registry.NewTracker(ops, store).KillSession("dev"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if ops.killed != "dev" {
+			t.Errorf("KillSession called with %q, want %q", ops.killed, "dev")
+		}
+		byName, _ := store.ByName()
+		if _, ok := byName["dev"]; ok {
+			t.Error("dev should have been removed from the registry")
+		}
+	})
+
+	t.Run("rename follows the session to its new name", func(t *testing.T) {
+		store := registry.NewStore(filepath.Join(t.TempDir(), "sessions.json"))
+		_ = store.Record("dev", "/code/dev", nil)
+		ops := &mockSessionOps{}
+
+		if err := registry.NewTracker(ops, store).RenameSession("dev", "main"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		byName, _ := store.ByName()
+		if byName["main"].ProjectPath != "/code/dev" {
+			t.Errorf("registry entry for main = %+v, want project /code/dev", byName["main"])
+		}
+	})
+
+	t.Run("registry is untouched when tmux fails", func(t *testing.T) {
+		store := registry.NewStore(filepath.Join(t.TempDir(), "sessions.json"))
+		_ = store.Record("dev", "/code/dev", nil)
+		ops := &mockSessionOps{err: fmt.Errorf("no such session")}
+
+		if err := registry.NewTracker(ops, store).KillSession("dev"); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		byName, _ := store.ByName()
+		if _, ok := byName["dev"]; !ok {
+			t.Error("dev should still be registered after a failed kill")
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package resolver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// AliasCycleError indicates aliases that reference each other in a loop.
+type AliasCycleError struct {
+	Chain []string
+}
+
+// Error returns the user-facing error message.
+func (e *AliasCycleError) Error() string {
+	return fmt.Sprintf("alias cycle: %s", strings.Join(e.Chain, " -> "))
+}
+
+// UnsetVariableError indicates an alias whose value references an
+// environment variable that is not set.
+type UnsetVariableError struct {
+	Alias    string
+	Variable string
+}
+
+// Error returns the user-facing error message.
+func (e *UnsetVariableError) Error() string {
+	return fmt.Sprintf("alias %s uses unset variable $%s", e.Alias, e.Variable)
+}
+
+// ExpandAlias resolves name through aliases. name is an alias, or an alias
+// followed by a subpath such as work/api, which resolves the alias work and
+// appends api. Alias values may start with ~ and contain $VARS, both expanded
+// here rather than when the alias was set, and may themselves reference
+// another alias the same way.
+//
+// ok reports whether name starts with an alias; names beginning with /, ., ~
+// or $ never do. A chain of aliases that loops back on itself returns an
+// *AliasCycleError, and a value referencing an unset variable returns an
+// *UnsetVariableError.
+func ExpandAlias(aliases AliasLookup, name string) (path string, ok bool, err error) {
+	return expandAlias(aliases, name, nil)
+}
+
+func expandAlias(aliases AliasLookup, name string, seen []string) (string, bool, error) {
+	if name == "" || strings.ContainsRune("/.~$", rune(name[0])) {
+		return "", false, nil
+	}
+
+	key, sub := name, ""
+	value, ok := aliases.Get(name)
+	if !ok {
+		var found bool
+		key, sub, found = strings.Cut(name, "/")
+		if !found {
+			return "", false, nil
+		}
+		if value, ok = aliases.Get(key); !ok {
+			return "", false, nil
+		}
+	}
+
+	if slices.Contains(seen, key) {
+		return "", true, &AliasCycleError{Chain: append(seen, key)}
+	}
+	seen = append(seen, key)
+
+	var unset string
+	value = os.Expand(value, func(name string) string {
+		v, ok := os.LookupEnv(name)
+		if !ok && unset == "" {
+			unset = name
+		}
+		return v
+	})
+	if unset != "" {
+		return "", true, &UnsetVariableError{Alias: key, Variable: unset}
+	}
+	value = expandTilde(value)
+	target, chained, err := expandAlias(aliases, value, seen)
+	if err != nil {
+		return "", true, err
+	}
+	if !chained {
+		target = value
+	}
+
+	if sub != "" {
+		target = filepath.Join(target, sub)
+	}
+	return target, true, nil
+}
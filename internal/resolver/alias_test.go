@@ -0,0 +1,150 @@
+package resolver_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leeovery/portal/internal/resolver"
+)
+
+func TestExpandAlias(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatalf("failed to get home dir: %v", err)
+	}
+	t.Setenv("PORTAL_TEST_WORK", "/srv/work")
+
+	aliases := &mockAliasLookup{aliases: map[string]string{
+		"code":  "/Users/lee/Code",
+		"dots":  "~/dotfiles",
+		"work":  "$PORTAL_TEST_WORK/projects",
+		"api":   "work/api",
+		"v2":    "api/v2",
+		"a/b":   "/exact/slash",
+		"loopa": "loopb/x",
+		"loopb": "loopa",
+		"self":  "self/sub",
+		"unset": "$PORTAL_TEST_UNSET/code",
+		"wrap":  "unset/api",
+	}}
+
+	tests := []struct {
+		name     string
+		query    string
+		wantPath string
+		wantOK   bool
+	}{
+		{name: "plain alias", query: "code", wantPath: "/Users/lee/Code", wantOK: true},
+		{name: "alias with a subpath", query: "code/portal/cmd", wantPath: "/Users/lee/Code/portal/cmd", wantOK: true},
+		{name: "tilde expanded when resolved", query: "dots", wantPath: filepath.Join(home, "dotfiles"), wantOK: true},
+		{name: "environment variables expanded when resolved", query: "work", wantPath: "/srv/work/projects", wantOK: true},
+		{name: "alias referencing an alias", query: "api", wantPath: "/srv/work/projects/api", wantOK: true},
+		{name: "chain of aliases with a subpath", query: "v2/docs", wantPath: "/srv/work/projects/api/v2/docs", wantOK: true},
+		{name: "exact name containing a slash wins", query: "a/b", wantPath: "/exact/slash", wantOK: true},
+		{name: "unknown name", query: "nope", wantOK: false},
+		{name: "unknown first element", query: "nope/sub", wantOK: false},
+		{name: "absolute path is not an alias", query: "/code", wantOK: false},
+		{name: "relative path is not an alias", query: "./code", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok, err := resolver.ExpandAlias(aliases, tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.wantOK || path != tt.wantPath {
+				t.Errorf("ExpandAlias(%q) = %q, %v, want %q, %v", tt.query, path, ok, tt.wantPath, tt.wantOK)
+			}
+		})
+	}
+
+	t.Run("cycle reports the chain", func(t *testing.T) {
+		_, ok, err := resolver.ExpandAlias(aliases, "loopa")
+
+		var cycle *resolver.AliasCycleError
+		if !ok || !errors.As(err, &cycle) {
+			t.Fatalf("got ok=%v err=%v, want an AliasCycleError", ok, err)
+		}
+		if err.Error() != "alias cycle: loopa -> loopb -> loopa" {
+			t.Errorf("error = %q", err.Error())
+		}
+	})
+
+	t.Run("unset variable reports the alias that uses it", func(t *testing.T) {
+		_, ok, err := resolver.ExpandAlias(aliases, "wrap")
+
+		var unset *resolver.UnsetVariableError
+		if !ok || !errors.As(err, &unset) {
+			t.Fatalf("got ok=%v err=%v, want an UnsetVariableError", ok, err)
+		}
+		if err.Error() != "alias unset uses unset variable $PORTAL_TEST_UNSET" {
+			t.Errorf("error = %q", err.Error())
+		}
+	})
+
+	t.Run("alias referencing itself is a cycle", func(t *testing.T) {
+		_, _, err := resolver.ExpandAlias(aliases, "self")
+		if err == nil || err.Error() != "alias cycle: self -> self" {
+			t.Errorf("err = %v, want alias cycle: self -> self", err)
+		}
+	})
+}
+
+func TestQueryResolver_Resolve_AliasSubpath(t *testing.T) {
+	aliases := &mockAliasLookup{aliases: map[string]string{"work": "/code/work"}}
+
+	t.Run("resolves the alias and appends the subpath", func(t *testing.T) {
+		dirValidator := &mockDirValidator{existing: map[string]bool{"/code/work/api": true}}
+
+		qr := resolver.NewQueryResolver(aliases, &mockZoxideQuerier{err: resolver.ErrNoMatch}, dirValidator)
+		result, err := qr.Resolve("work/api")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		pr, ok := result.(*resolver.PathResult)
+		if !ok || pr.Path != "/code/work/api" {
+			t.Errorf("got %#v, want PathResult /code/work/api", result)
+		}
+	})
+
+	t.Run("missing subpath directory is an error", func(t *testing.T) {
+		qr := resolver.NewQueryResolver(aliases, &mockZoxideQuerier{err: resolver.ErrNoMatch}, &mockDirValidator{})
+		_, err := qr.Resolve("work/nope")
+
+		var notFound *resolver.DirNotFoundError
+		if !errors.As(err, &notFound) || notFound.Path != "/code/work/nope" {
+			t.Errorf("err = %v, want directory not found for /code/work/nope", err)
+		}
+	})
+
+	t.Run("subpath worktree query resolves the repository", func(t *testing.T) {
+		dirValidator := &mockDirValidator{existing: map[string]bool{"/code/work/api": true}}
+
+		qr := resolver.NewQueryResolver(aliases, &mockZoxideQuerier{err: resolver.ErrNoMatch}, dirValidator)
+		result, err := qr.Resolve("work/api@feature")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		wt, ok := result.(*resolver.WorktreeResult)
+		if !ok || wt.Repo != "/code/work/api" || wt.Branch != "feature" {
+			t.Errorf("got %#v, want WorktreeResult /code/work/api feature", result)
+		}
+	})
+
+	t.Run("alias cycle is an error", func(t *testing.T) {
+		cyclic := &mockAliasLookup{aliases: map[string]string{"a": "b", "b": "a"}}
+
+		qr := resolver.NewQueryResolver(cyclic, &mockZoxideQuerier{err: resolver.ErrNoMatch}, &mockDirValidator{})
+		_, err := qr.Resolve("a")
+
+		var cycle *resolver.AliasCycleError
+		if !errors.As(err, &cycle) {
+			t.Errorf("err = %v, want an AliasCycleError", err)
+		}
+	})
+}
@@ -42,3 +42,34 @@ func ResolveGitRoot(dir string, runner CommandRunner) (string, error) {
 
 	return strings.TrimSpace(output), nil
 }
+
+// ResolveProjectDir resolves dir to the directory a session opens in.
+// When useGitRoot is true, dir is resolved to its git repository root as in
+// ResolveGitRoot. Otherwise dir is returned unchanged once it is known to exist.
+func ResolveProjectDir(dir string, runner CommandRunner, useGitRoot bool) (string, error) {
+	if useGitRoot {
+		return ResolveGitRoot(dir, runner)
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		return "", fmt.Errorf("directory does not exist: %w", err)
+	}
+
+	return dir, nil
+}
+
+// CurrentBranch returns the branch checked out in the git repository
+// containing dir. It returns an empty string outside a repository or when
+// HEAD is detached.
+func CurrentBranch(dir string, runner CommandRunner) (string, error) {
+	output, err := runner.Run("git", "-C", dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", nil
+	}
+
+	branch := strings.TrimSpace(output)
+	if branch == "HEAD" {
+		return "", nil
+	}
+	return branch, nil
+}
@@ -2,6 +2,7 @@ package resolver_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/leeovery/portal/internal/resolver"
@@ -104,3 +105,96 @@ func TestResolveGitRoot(t *testing.T) {
 func TestRealCommandRunner_implements_CommandRunner(t *testing.T) {
 	var _ resolver.CommandRunner = &resolver.RealCommandRunner{}
 }
+
+func TestResolveProjectDir(t *testing.T) {
+	t.Run("resolves to git root when enabled", func(t *testing.T) {
+		dir := t.TempDir()
+		runner := &MockCommandRunner{Output: "/home/user/project\n"}
+
+		got, err := resolver.ResolveProjectDir(dir, runner, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "/home/user/project" {
+			t.Errorf("ResolveProjectDir() = %q, want %q", got, "/home/user/project")
+		}
+	})
+
+	t.Run("returns directory unchanged without running git when disabled", func(t *testing.T) {
+		dir := t.TempDir()
+		called := false
+		runner := &MockCommandRunner{
+			Output: "/home/user/project\n",
+			OnRun:  func(string, ...string) { called = true },
+		}
+
+		got, err := resolver.ResolveProjectDir(dir, runner, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != dir {
+			t.Errorf("ResolveProjectDir() = %q, want %q", got, dir)
+		}
+		if called {
+			t.Error("git should not be run when git root resolution is disabled")
+		}
+	})
+
+	t.Run("returns error for non-existent directory when disabled", func(t *testing.T) {
+		_, err := resolver.ResolveProjectDir("/nonexistent/path/xyz", &MockCommandRunner{}, false)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestCurrentBranch(t *testing.T) {
+	tests := []struct {
+		name       string
+		mockOutput string
+		mockErr    error
+		want       string
+	}{
+		{
+			name:       "returns trimmed branch name",
+			mockOutput: "feature/login\n",
+			want:       "feature/login",
+		},
+		{
+			name:       "detached HEAD yields empty branch",
+			mockOutput: "HEAD\n",
+			want:       "",
+		},
+		{
+			name:    "outside a repository yields empty branch",
+			mockErr: fmt.Errorf("fatal: not a git repository"),
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotArgs []string
+			runner := &MockCommandRunner{
+				Output: tt.mockOutput,
+				Err:    tt.mockErr,
+				OnRun: func(name string, args ...string) {
+					gotArgs = append([]string{name}, args...)
+				},
+			}
+
+			got, err := resolver.CurrentBranch("/code/app", runner)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CurrentBranch() = %q, want %q", got, tt.want)
+			}
+
+			wantArgs := "git -C /code/app rev-parse --abbrev-ref HEAD"
+			if strings.Join(gotArgs, " ") != wantArgs {
+				t.Errorf("ran %q, want %q", strings.Join(gotArgs, " "), wantArgs)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package resolver
 import (
 	"fmt"
 	"os"
+	"strings"
 )
 
 // AliasLookup retrieves the path for a given alias name.
@@ -32,6 +33,16 @@ type PathResult struct {
 
 func (*PathResult) queryResult() {}
 
+// WorktreeResult indicates a repo@branch query: the session should open in
+// the worktree of the repository at Repo that has Branch checked out, which
+// may need to be created first.
+type WorktreeResult struct {
+	Repo   string
+	Branch string
+}
+
+func (*WorktreeResult) queryResult() {}
+
 // FallbackResult indicates no resolution was found; the TUI should be
 // launched with the query pre-filled as filter text.
 type FallbackResult struct {
@@ -80,21 +91,54 @@ func NewQueryResolver(aliases AliasLookup, zoxide ZoxideQuerier, dirValidator Di
 }
 
 // Resolve applies the resolution chain for the given query.
-// Path-like arguments are resolved directly via ResolvePath.
-// Non-path arguments are checked against aliases, then zoxide, then fall back to TUI.
+// Tag queries such as #client go straight to the TUI, filtered to the tag.
+// Aliases come next, including alias/subpath queries such as work/api, so an
+// alias wins over a relative directory of the same name.
+// Other path-like arguments are resolved directly via ResolvePath, and the
+// rest are checked against zoxide, then fall back to TUI.
+// A repo@branch query that is not itself an alias resolves repo the same
+// way and yields a WorktreeResult. Branch names may contain '/', so only the
+// repo part decides whether such a query is a path; path@branch is used only
+// when the whole query is not an existing directory.
 // After alias or zoxide resolution, the directory is validated on disk.
 func (qr *QueryResolver) Resolve(query string) (QueryResult, error) {
-	if IsPathArgument(query) {
+	if isTagQuery(query) {
+		return &FallbackResult{Query: query}, nil
+	}
+
+	repo, branch, isWorktree := splitWorktreeQuery(query)
+
+	// Alias lookup. An exact alias containing @ wins over a worktree query;
+	// alias/subpath@branch is a worktree query unless the directory exists.
+	path, aliased, err := ExpandAlias(qr.aliases, query)
+	if err != nil {
+		return nil, err
+	}
+	if aliased {
+		_, exact := qr.aliases.Get(query)
+		if !isWorktree || exact || qr.dirValidator.Exists(path) {
+			return qr.validatedPath(path)
+		}
+	}
+
+	_, repoAliased, _ := ExpandAlias(qr.aliases, repo)
+	if IsPathArgument(query) && !aliased && (!isWorktree || (IsPathArgument(repo) && !repoAliased)) {
 		resolved, err := ResolvePath(query)
-		if err != nil {
+		if err == nil {
+			return &PathResult{Path: resolved}, nil
+		}
+		if !isWorktree {
 			return nil, err
 		}
-		return &PathResult{Path: resolved}, nil
+		repoPath, repoErr := ResolvePath(repo)
+		if repoErr != nil {
+			return nil, err
+		}
+		return &WorktreeResult{Repo: repoPath, Branch: branch}, nil
 	}
 
-	// Alias lookup
-	if path, ok := qr.aliases.Get(query); ok {
-		return qr.validatedPath(path)
+	if isWorktree {
+		return qr.resolveWorktree(query, repo, branch)
 	}
 
 	// Zoxide query
@@ -106,6 +150,38 @@ func (qr *QueryResolver) Resolve(query string) (QueryResult, error) {
 	return &FallbackResult{Query: query}, nil
 }
 
+// splitWorktreeQuery splits a repo@branch query at its last @.
+func splitWorktreeQuery(query string) (repo, branch string, ok bool) {
+	i := strings.LastIndex(query, "@")
+	if i <= 0 || i == len(query)-1 {
+		return "", "", false
+	}
+	return query[:i], query[i+1:], true
+}
+
+// resolveWorktree resolves the repo of a repo@branch query through aliases,
+// then zoxide, falling back to the TUI filtered by the whole query.
+func (qr *QueryResolver) resolveWorktree(query, repo, branch string) (QueryResult, error) {
+	path, ok, err := ExpandAlias(qr.aliases, repo)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if path, err = qr.zoxide.Query(repo); err != nil {
+			return &FallbackResult{Query: query}, nil
+		}
+	}
+	if !qr.dirValidator.Exists(path) {
+		return nil, &DirNotFoundError{Path: path}
+	}
+	return &WorktreeResult{Repo: path, Branch: branch}, nil
+}
+
+// isTagQuery reports whether query is a #tag filter rather than a destination.
+func isTagQuery(query string) bool {
+	return len(query) > 1 && strings.HasPrefix(query, "#")
+}
+
 // validatedPath returns a PathResult after verifying the directory exists on disk.
 func (qr *QueryResolver) validatedPath(path string) (QueryResult, error) {
 	if !qr.dirValidator.Exists(path) {
@@ -372,6 +372,49 @@ func (t *trackingZoxideQuerier) Query(terms string) (string, error) {
 	return t.result, t.err
 }
 
+func TestQueryResolver_Resolve_TagQuery(t *testing.T) {
+	t.Run("tag query falls back to TUI without alias or zoxide lookup", func(t *testing.T) {
+		aliasLookup := &mockAliasLookup{aliases: map[string]string{"#client": "/alias/path"}}
+		zoxideCalled := false
+		zoxide := &trackingZoxideQuerier{
+			result:  "/zoxide/path",
+			onQuery: func() { zoxideCalled = true },
+		}
+		dirValidator := &mockDirValidator{existing: map[string]bool{"/alias/path": true, "/zoxide/path": true}}
+
+		qr := resolver.NewQueryResolver(aliasLookup, zoxide, dirValidator)
+		result, err := qr.Resolve("#client")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		fallback, ok := result.(*resolver.FallbackResult)
+		if !ok {
+			t.Fatalf("expected *FallbackResult, got %T", result)
+		}
+		if fallback.Query != "#client" {
+			t.Errorf("Query = %q, want %q", fallback.Query, "#client")
+		}
+		if zoxideCalled {
+			t.Error("zoxide should not be called for tag queries")
+		}
+	})
+
+	t.Run("lone hash is resolved as usual", func(t *testing.T) {
+		aliasLookup := &mockAliasLookup{aliases: map[string]string{"#": "/alias/path"}}
+		dirValidator := &mockDirValidator{existing: map[string]bool{"/alias/path": true}}
+
+		qr := resolver.NewQueryResolver(aliasLookup, &mockZoxideQuerier{err: errors.New("no match")}, dirValidator)
+		result, err := qr.Resolve("#")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := result.(*resolver.PathResult); !ok {
+			t.Errorf("expected *PathResult, got %T", result)
+		}
+	})
+}
+
 func TestQueryResolver_Resolve_NonExistentResolvedDirectory(t *testing.T) {
 	t.Run("non-existent resolved directory prints error and exits 1", func(t *testing.T) {
 		aliasLookup := &mockAliasLookup{aliases: map[string]string{"myapp": "/does/not/exist"}}
@@ -396,3 +439,102 @@ func TestQueryResolver_Resolve_NonExistentResolvedDirectory(t *testing.T) {
 		}
 	})
 }
+
+func TestQueryResolver_Resolve_WorktreeQuery(t *testing.T) {
+	t.Run("resolves the repository through an alias", func(t *testing.T) {
+		aliasLookup := &mockAliasLookup{aliases: map[string]string{"api": "/code/api"}}
+		dirValidator := &mockDirValidator{existing: map[string]bool{"/code/api": true}}
+
+		qr := resolver.NewQueryResolver(aliasLookup, &mockZoxideQuerier{err: errors.New("no match")}, dirValidator)
+		result, err := qr.Resolve("api@feature/login")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		wt, ok := result.(*resolver.WorktreeResult)
+		if !ok {
+			t.Fatalf("expected *WorktreeResult, got %T", result)
+		}
+		if wt.Repo != "/code/api" || wt.Branch != "feature/login" {
+			t.Errorf("WorktreeResult = %+v, want /code/api and feature/login", wt)
+		}
+	})
+
+	t.Run("resolves the repository through zoxide", func(t *testing.T) {
+		dirValidator := &mockDirValidator{existing: map[string]bool{"/code/api": true}}
+
+		qr := resolver.NewQueryResolver(&mockAliasLookup{aliases: map[string]string{}}, &mockZoxideQuerier{result: "/code/api"}, dirValidator)
+		result, err := qr.Resolve("api@main")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if wt, ok := result.(*resolver.WorktreeResult); !ok || wt.Repo != "/code/api" || wt.Branch != "main" {
+			t.Errorf("result = %+v, want worktree of /code/api at main", result)
+		}
+	})
+
+	t.Run("an alias containing @ wins", func(t *testing.T) {
+		aliasLookup := &mockAliasLookup{aliases: map[string]string{"me@work": "/code/work"}}
+		dirValidator := &mockDirValidator{existing: map[string]bool{"/code/work": true}}
+
+		qr := resolver.NewQueryResolver(aliasLookup, &mockZoxideQuerier{err: errors.New("no match")}, dirValidator)
+		result, err := qr.Resolve("me@work")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := result.(*resolver.PathResult); !ok {
+			t.Errorf("expected *PathResult, got %T", result)
+		}
+	})
+
+	t.Run("unknown repository falls back to TUI", func(t *testing.T) {
+		qr := resolver.NewQueryResolver(&mockAliasLookup{aliases: map[string]string{}}, &mockZoxideQuerier{err: errors.New("no match")}, &mockDirValidator{})
+		result, err := qr.Resolve("nope@main")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fallback, ok := result.(*resolver.FallbackResult); !ok || fallback.Query != "nope@main" {
+			t.Errorf("result = %+v, want fallback with the whole query", result)
+		}
+	})
+
+	t.Run("a trailing or leading @ is not a worktree query", func(t *testing.T) {
+		qr := resolver.NewQueryResolver(&mockAliasLookup{aliases: map[string]string{}}, &mockZoxideQuerier{err: errors.New("no match")}, &mockDirValidator{})
+		for _, query := range []string{"api@", "@main"} {
+			result, err := qr.Resolve(query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, ok := result.(*resolver.FallbackResult); !ok {
+				t.Errorf("Resolve(%q) = %T, want *FallbackResult", query, result)
+			}
+		}
+	})
+}
+
+func TestQueryResolver_Resolve_WorktreePath(t *testing.T) {
+	repo := t.TempDir()
+	qr := resolver.NewQueryResolver(&mockAliasLookup{aliases: map[string]string{}}, &mockZoxideQuerier{err: errors.New("no match")}, &mockDirValidator{})
+
+	result, err := qr.Resolve(repo + "@feature/login")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wt, ok := result.(*resolver.WorktreeResult); !ok || wt.Repo != repo || wt.Branch != "feature/login" {
+		t.Errorf("result = %+v, want worktree of %s at feature/login", result, repo)
+	}
+
+	t.Run("an existing directory containing @ is a path", func(t *testing.T) {
+		dir := filepath.Join(repo, "node_modules", "@scope")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		result, err := qr.Resolve(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if path, ok := result.(*resolver.PathResult); !ok || path.Path != dir {
+			t.Errorf("result = %+v, want path %s", result, dir)
+		}
+	})
+}
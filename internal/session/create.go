@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/leeovery/portal/internal/tmux"
 )
 
 // ShellFromEnv returns the user's shell from $SHELL, falling back to /bin/sh.
@@ -41,16 +43,34 @@ type ProjectStore interface {
 // TmuxClient provides tmux session operations.
 type TmuxClient interface {
 	HasSession(name string) bool
+	ListSessions() ([]tmux.Session, error)
 	NewSession(name, dir, shellCommand string) error
+	KillSession(name string) error
+}
+
+// LayoutApplier replays a project's declarative window/pane layout onto a new session.
+type LayoutApplier interface {
+	HasLayout(projectName string) bool
+	ApplyLayout(sessionName, dir, projectName string, skipFirstCommand bool) error
+}
+
+// SessionRecorder records which project a newly created session belongs to.
+type SessionRecorder interface {
+	Record(name, projectPath string, command []string) error
 }
 
 // SessionCreator orchestrates the creation of a new tmux session from a directory.
 type SessionCreator struct {
-	git   GitResolver
-	store ProjectStore
-	tmux  TmuxClient
-	gen   IDGenerator
-	shell string
+	git     GitResolver
+	store   ProjectStore
+	tmux    TmuxClient
+	gen     IDGenerator
+	shell   string
+	layouts LayoutApplier
+	reg     SessionRecorder
+
+	nameFormats    NameFormats
+	defaultCommand []string
 }
 
 // NewSessionCreator creates a SessionCreator with the given dependencies.
@@ -65,12 +85,45 @@ func NewSessionCreator(git GitResolver, store ProjectStore, tmux TmuxClient, gen
 	}
 }
 
+// WithLayouts enables replaying project layouts onto newly created sessions.
+func (sc *SessionCreator) WithLayouts(layouts LayoutApplier) *SessionCreator {
+	sc.layouts = layouts
+	return sc
+}
+
+// WithNameFormats sets the session name templates; see FormatSessionName.
+func (sc *SessionCreator) WithNameFormats(formats NameFormats) *SessionCreator {
+	sc.nameFormats = formats
+	return sc
+}
+
+// WithDefaultCommand sets the command run in new sessions when none is given.
+func (sc *SessionCreator) WithDefaultCommand(command []string) *SessionCreator {
+	sc.defaultCommand = command
+	return sc
+}
+
+// WithRegistry enables recording each created session's project in reg.
+func (sc *SessionCreator) WithRegistry(reg SessionRecorder) *SessionCreator {
+	sc.reg = reg
+	return sc
+}
+
 // CreateFromDir resolves the directory to a git root, generates a session name,
 // upserts the project in the store, and creates a tmux session.
 // When command is non-nil and non-empty, constructs a shell-command for tmux.
+// When command is empty, the configured default command is used instead.
+// When layouts are enabled, the project's layout is applied after creation;
+// if it fails, the half-built session is killed.
+// When a registry is set, the session's project and command are recorded;
+// if that fails, the session is killed too.
 // Returns the generated session name.
 func (sc *SessionCreator) CreateFromDir(dir string, command []string) (string, error) {
-	prepared, err := PrepareSession(dir, command, sc.git, sc.store, sc.tmux, sc.gen, sc.shell)
+	if len(command) == 0 {
+		command = sc.defaultCommand
+	}
+
+	prepared, err := PrepareSession(dir, command, sc.git, sc.store, sc.tmux, sc.gen, sc.nameFormats, sc.shell)
 	if err != nil {
 		return "", err
 	}
@@ -79,5 +132,19 @@ func (sc *SessionCreator) CreateFromDir(dir string, command []string) (string, e
 		return "", fmt.Errorf("failed to create tmux session: %w", err)
 	}
 
+	if sc.layouts != nil {
+		if err := sc.layouts.ApplyLayout(prepared.SessionName, prepared.ResolvedDir, prepared.ProjectName, prepared.ShellCmd != ""); err != nil {
+			_ = sc.tmux.KillSession(prepared.SessionName)
+			return "", fmt.Errorf("failed to apply layout: %w", err)
+		}
+	}
+
+	if sc.reg != nil {
+		if err := sc.reg.Record(prepared.SessionName, prepared.ResolvedDir, command); err != nil {
+			_ = sc.tmux.KillSession(prepared.SessionName)
+			return "", fmt.Errorf("failed to record session: %w", err)
+		}
+	}
+
 	return prepared.SessionName, nil
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/leeovery/portal/internal/session"
+	"github.com/leeovery/portal/internal/tmux"
 )
 
 func TestBuildShellCommand(t *testing.T) {
@@ -126,12 +127,22 @@ type mockTmuxClient struct {
 	newSessionDir      string
 	newSessionShellCmd string
 	newSessionErr      error
+	killed             []string
 }
 
 func (m *mockTmuxClient) HasSession(name string) bool {
 	return m.existingSessions[name]
 }
 
+func (m *mockTmuxClient) ListSessions() ([]tmux.Session, error) {
+	return listSessions(m.existingSessions), nil
+}
+
+func (m *mockTmuxClient) KillSession(name string) error {
+	m.killed = append(m.killed, name)
+	return nil
+}
+
 func (m *mockTmuxClient) NewSession(name, dir, shellCommand string) error {
 	m.newSessionName = name
 	m.newSessionDir = dir
@@ -139,6 +150,45 @@ func (m *mockTmuxClient) NewSession(name, dir, shellCommand string) error {
 	return m.newSessionErr
 }
 
+// mockLayoutApplier implements session.LayoutApplier for testing.
+type mockLayoutApplier struct {
+	layouts          map[string]bool
+	appliedSession   string
+	appliedDir       string
+	appliedProject   string
+	skipFirstCommand bool
+	applyCount       int
+	err              error
+}
+
+func (m *mockLayoutApplier) HasLayout(projectName string) bool {
+	return m.layouts[projectName]
+}
+
+func (m *mockLayoutApplier) ApplyLayout(sessionName, dir, projectName string, skipFirstCommand bool) error {
+	m.appliedSession = sessionName
+	m.appliedDir = dir
+	m.appliedProject = projectName
+	m.skipFirstCommand = skipFirstCommand
+	m.applyCount++
+	return m.err
+}
+
+// mockSessionRecorder implements session.SessionRecorder for testing.
+type mockSessionRecorder struct {
+	name        string
+	projectPath string
+	command     []string
+	err         error
+}
+
+func (m *mockSessionRecorder) Record(name, projectPath string, command []string) error {
+	m.name = name
+	m.projectPath = projectPath
+	m.command = command
+	return m.err
+}
+
 func TestCreateFromDir(t *testing.T) {
 	namePattern := regexp.MustCompile(`^[a-zA-Z0-9_-]+-[a-zA-Z0-9]{6}$`)
 
@@ -361,6 +411,53 @@ func TestCreateFromDir(t *testing.T) {
 		}
 	})
 
+	t.Run("uses default command when none is given", func(t *testing.T) {
+		t.Setenv("SHELL", "/bin/zsh")
+		dir := t.TempDir()
+		gitResolver := &mockGitResolver{}
+		store := &mockProjectStore{}
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+
+		creator := session.NewSessionCreator(gitResolver, store, tmuxClient, gen).WithDefaultCommand([]string{"nvim"})
+
+		if _, err := creator.CreateFromDir(dir, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "/bin/zsh -ic 'nvim; exec /bin/zsh'"
+		if tmuxClient.newSessionShellCmd != want {
+			t.Errorf("shell command = %q, want %q", tmuxClient.newSessionShellCmd, want)
+		}
+
+		if _, err := creator.CreateFromDir(dir, []string{"claude"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want = "/bin/zsh -ic 'claude; exec /bin/zsh'"
+		if tmuxClient.newSessionShellCmd != want {
+			t.Errorf("explicit command should win: shell command = %q, want %q", tmuxClient.newSessionShellCmd, want)
+		}
+	})
+
+	t.Run("names session with configured format", func(t *testing.T) {
+		dir := t.TempDir()
+		gitResolver := &mockGitResolver{}
+		store := &mockProjectStore{}
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+
+		creator := session.NewSessionCreator(gitResolver, store, tmuxClient, gen).WithNameFormats(session.NameFormats{Default: "{id}-{project}"})
+
+		name, err := creator.CreateFromDir(dir, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := "abc123-" + filepath.Base(dir)
+		if name != want {
+			t.Errorf("session name = %q, want %q", name, want)
+		}
+	})
+
 	t.Run("uses shell resolved at construction time", func(t *testing.T) {
 		t.Setenv("SHELL", "/usr/local/bin/fish")
 		dir := t.TempDir()
@@ -425,4 +522,120 @@ func TestCreateFromDir(t *testing.T) {
 			t.Errorf("session name = %q, want %q", sessionName, wantName)
 		}
 	})
+	t.Run("applies layout after creating the session", func(t *testing.T) {
+		dir := t.TempDir()
+		gitResolver := &mockGitResolver{}
+		store := &mockProjectStore{}
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+		layouts := &mockLayoutApplier{}
+
+		creator := session.NewSessionCreator(gitResolver, store, tmuxClient, gen).WithLayouts(layouts)
+
+		sessionName, err := creator.CreateFromDir(dir, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if layouts.applyCount != 1 {
+			t.Fatalf("ApplyLayout called %d times, want 1", layouts.applyCount)
+		}
+		if layouts.appliedSession != sessionName {
+			t.Errorf("layout applied to %q, want %q", layouts.appliedSession, sessionName)
+		}
+		if layouts.appliedDir != dir {
+			t.Errorf("layout dir = %q, want %q", layouts.appliedDir, dir)
+		}
+		if layouts.appliedProject != filepath.Base(dir) {
+			t.Errorf("layout project = %q, want %q", layouts.appliedProject, filepath.Base(dir))
+		}
+		if layouts.skipFirstCommand {
+			t.Error("skipFirstCommand = true, want false when no command given")
+		}
+	})
+
+	t.Run("skips first layout command when a command is given", func(t *testing.T) {
+		dir := t.TempDir()
+		gitResolver := &mockGitResolver{}
+		store := &mockProjectStore{}
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+		layouts := &mockLayoutApplier{}
+
+		creator := session.NewSessionCreator(gitResolver, store, tmuxClient, gen).WithLayouts(layouts)
+
+		if _, err := creator.CreateFromDir(dir, []string{"claude"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !layouts.skipFirstCommand {
+			t.Error("skipFirstCommand = false, want true when a command is given")
+		}
+	})
+
+	t.Run("kills the session and returns error when layout fails to apply", func(t *testing.T) {
+		dir := t.TempDir()
+		gitResolver := &mockGitResolver{}
+		store := &mockProjectStore{}
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+		layouts := &mockLayoutApplier{err: fmt.Errorf("bad layout")}
+		reg := &mockSessionRecorder{}
+
+		creator := session.NewSessionCreator(gitResolver, store, tmuxClient, gen).WithLayouts(layouts).WithRegistry(reg)
+
+		_, err := creator.CreateFromDir(dir, nil)
+		if err == nil || err.Error() != "failed to apply layout: bad layout" {
+			t.Fatalf("err = %v, want failed to apply layout: bad layout", err)
+		}
+		if len(tmuxClient.killed) != 1 || tmuxClient.killed[0] != tmuxClient.newSessionName {
+			t.Errorf("killed %v, want the new session %q", tmuxClient.killed, tmuxClient.newSessionName)
+		}
+		if reg.name != "" {
+			t.Errorf("recorded %q, want nothing recorded for a killed session", reg.name)
+		}
+	})
+	t.Run("records created session in registry", func(t *testing.T) {
+		dir := t.TempDir()
+		gitResolver := &mockGitResolver{}
+		store := &mockProjectStore{}
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+		reg := &mockSessionRecorder{}
+
+		creator := session.NewSessionCreator(gitResolver, store, tmuxClient, gen).WithRegistry(reg)
+
+		sessionName, err := creator.CreateFromDir(dir, []string{"make", "dev"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if reg.name != sessionName {
+			t.Errorf("recorded name = %q, want %q", reg.name, sessionName)
+		}
+		if reg.projectPath != dir {
+			t.Errorf("recorded project path = %q, want %q", reg.projectPath, dir)
+		}
+		if len(reg.command) != 2 || reg.command[0] != "make" {
+			t.Errorf("recorded command = %v, want [make dev]", reg.command)
+		}
+	})
+
+	t.Run("kills the session and returns error when registry write fails", func(t *testing.T) {
+		dir := t.TempDir()
+		gitResolver := &mockGitResolver{}
+		store := &mockProjectStore{}
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+		reg := &mockSessionRecorder{err: fmt.Errorf("disk full")}
+
+		creator := session.NewSessionCreator(gitResolver, store, tmuxClient, gen).WithRegistry(reg)
+
+		if _, err := creator.CreateFromDir(dir, nil); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if len(tmuxClient.killed) != 1 || tmuxClient.killed[0] != tmuxClient.newSessionName {
+			t.Errorf("killed %v, want the new session %q", tmuxClient.killed, tmuxClient.newSessionName)
+		}
+	})
 }
@@ -3,43 +3,186 @@ package session
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 const (
-	maxRetries = 10
-	suffixLen  = 6
-	alphabet   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	maxRetries  = 10
+	maxSequence = 9999
+	suffixLen   = 6
+	alphabet    = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	dateLayout  = "2006-01-02"
 )
 
+// DefaultNameFormat is the session name format used when none is configured.
+const DefaultNameFormat = "{project}-{id}"
+
 // IDGenerator produces a random string suitable for use as a session name suffix.
 type IDGenerator func() (string, error)
 
 // ExistsFunc reports whether a tmux session with the given name already exists.
 type ExistsFunc func(name string) bool
 
-// SanitiseProjectName replaces characters that are invalid in tmux session names
-// (periods and colons) with hyphens.
+// SanitiseProjectName replaces characters that tmux rejects or rewrites in
+// session names with hyphens: periods and colons (tmux target separators),
+// control characters and invalid UTF-8. Whitespace is replaced too, since it
+// makes names awkward to pass to xctl attach unquoted.
 func SanitiseProjectName(name string) string {
-	r := strings.NewReplacer(".", "-", ":", "-")
-	return r.Replace(name)
+	var b strings.Builder
+	b.Grow(len(name))
+	for i, w := 0, 0; i < len(name); i += w {
+		r, size := utf8.DecodeRuneInString(name[i:])
+		w = size
+		if r == utf8.RuneError && size <= 1 {
+			b.WriteByte('-')
+			continue
+		}
+		if r == '.' || r == ':' || unicode.IsControl(r) || unicode.IsSpace(r) {
+			b.WriteByte('-')
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// NameVars holds the values substituted into a session name template.
+type NameVars struct {
+	// Project is the project name, normally the basename of its directory.
+	Project string
+	// Parent is the basename of the project directory's parent.
+	Parent string
+	// Branch is the project's current git branch, empty outside a repository.
+	Branch string
+	// Date is the creation time used for {date}.
+	Date time.Time
+}
+
+// placeholderPattern matches a {name} or {name:arg} template placeholder.
+var placeholderPattern = regexp.MustCompile(`\{([a-z]+)(?::([^}]*))?\}`)
+
+// ValidateNameTemplate checks that tmpl only uses known placeholders and
+// contains {id} or {seq}, one of which is needed to keep names unique.
+//
+// Placeholders: {project}, {parent}, {branch}, {date} (YYYY-MM-DD),
+// {id} (random suffix) and {seq} or {seq:N} (lowest free number, zero-padded
+// to N digits).
+func ValidateNameTemplate(tmpl string) error {
+	if tmpl == "" {
+		return errors.New("must not be empty")
+	}
+
+	unique := false
+	for _, m := range placeholderPattern.FindAllStringSubmatch(tmpl, -1) {
+		name, arg := m[1], m[2]
+		switch name {
+		case "project", "parent", "branch", "date", "id":
+			if arg != "" {
+				return fmt.Errorf("placeholder {%s} takes no argument", name)
+			}
+			unique = unique || name == "id"
+		case "seq":
+			if arg != "" {
+				if width, err := strconv.Atoi(arg); err != nil || width < 1 || width > 6 {
+					return fmt.Errorf("{seq:%s}: width must be a number from 1 to 6", arg)
+				}
+			}
+			unique = true
+		default:
+			return fmt.Errorf("unknown placeholder {%s}", name)
+		}
+	}
+
+	if rest := placeholderPattern.ReplaceAllString(tmpl, ""); strings.ContainsAny(rest, "{}") {
+		return errors.New("unbalanced braces")
+	}
+
+	if !unique {
+		return errors.New("must contain {id} or {seq}")
+	}
+	return nil
+}
+
+// expandTemplate substitutes vars, id and seq into tmpl and sanitises the result.
+func expandTemplate(tmpl string, vars NameVars, id string, seq int) string {
+	expanded := placeholderPattern.ReplaceAllStringFunc(tmpl, func(p string) string {
+		m := placeholderPattern.FindStringSubmatch(p)
+		switch m[1] {
+		case "project":
+			return vars.Project
+		case "parent":
+			return vars.Parent
+		case "branch":
+			return vars.Branch
+		case "date":
+			return vars.Date.Format(dateLayout)
+		case "id":
+			return id
+		case "seq":
+			width, _ := strconv.Atoi(m[2])
+			return fmt.Sprintf("%0*d", width, seq)
+		}
+		return p
+	})
+	return SanitiseProjectName(expanded)
 }
 
 // GenerateSessionName produces a unique tmux session name in the format {project}-{nanoid}.
 // It sanitises the project name, appends a 6-character random suffix from gen,
 // and retries up to 10 times if the generated name collides with an existing session.
 func GenerateSessionName(projectName string, gen IDGenerator, exists ExistsFunc) (string, error) {
-	sanitised := SanitiseProjectName(projectName)
+	return FormatSessionName(DefaultNameFormat, NameVars{Project: projectName}, gen, exists)
+}
 
-	for range maxRetries {
-		suffix, err := gen()
+// FormatSessionName produces a unique tmux session name from the template tmpl
+// (see ValidateNameTemplate). Templates with {seq} take the lowest sequence
+// number whose name is not already a session; a template that also has {id}
+// therefore almost always takes 1. Otherwise a fresh {id} is generated up to
+// 10 times until the name does not collide. exists is called for every
+// candidate, so for {seq} it should check a set of names fetched up front.
+func FormatSessionName(tmpl string, vars NameVars, gen IDGenerator, exists ExistsFunc) (string, error) {
+	if err := ValidateNameTemplate(tmpl); err != nil {
+		return "", fmt.Errorf("invalid session name template %q: %w", tmpl, err)
+	}
+
+	usesID := strings.Contains(tmpl, "{id}")
+	nextID := func() (string, error) {
+		if !usesID {
+			return "", nil
+		}
+		id, err := gen()
 		if err != nil {
 			return "", fmt.Errorf("failed to generate session ID: %w", err)
 		}
+		return id, nil
+	}
 
-		candidate := sanitised + "-" + suffix
-		if !exists(candidate) {
+	if strings.Contains(tmpl, "{seq") {
+		for seq := 1; seq <= maxSequence; seq++ {
+			id, err := nextID()
+			if err != nil {
+				return "", err
+			}
+			if candidate := expandTemplate(tmpl, vars, id, seq); !exists(candidate) {
+				return candidate, nil
+			}
+		}
+		return "", fmt.Errorf("failed to generate unique session name: sequence exhausted at %d", maxSequence)
+	}
+
+	for range maxRetries {
+		id, err := nextID()
+		if err != nil {
+			return "", err
+		}
+		if candidate := expandTemplate(tmpl, vars, id, 0); !exists(candidate) {
 			return candidate, nil
 		}
 	}
@@ -47,6 +190,30 @@ func GenerateSessionName(projectName string, gen IDGenerator, exists ExistsFunc)
 	return "", fmt.Errorf("failed to generate unique session name after %d attempts", maxRetries)
 }
 
+// NameFormats selects the session name template for each project.
+type NameFormats struct {
+	// Default is used for projects without their own template.
+	// An empty Default means DefaultNameFormat.
+	Default string
+	// Projects maps a project name or absolute directory to its template.
+	Projects map[string]string
+}
+
+// For returns the template for the project at dir with the given name.
+// A directory match takes precedence over a name match.
+func (f NameFormats) For(dir, name string) string {
+	if tmpl, ok := f.Projects[dir]; ok {
+		return tmpl
+	}
+	if tmpl, ok := f.Projects[name]; ok {
+		return tmpl
+	}
+	if f.Default == "" {
+		return DefaultNameFormat
+	}
+	return f.Default
+}
+
 // NewNanoIDGenerator returns an IDGenerator that produces 6-character alphanumeric strings
 // using crypto/rand.
 func NewNanoIDGenerator() IDGenerator {
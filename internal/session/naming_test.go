@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/leeovery/portal/internal/session"
 )
@@ -39,6 +40,26 @@ func TestSanitiseProjectName(t *testing.T) {
 			input: "",
 			want:  "",
 		},
+		{
+			name:  "replaces whitespace",
+			input: "my app\tv2",
+			want:  "my-app-v2",
+		},
+		{
+			name:  "replaces control characters",
+			input: "my\x00app\x1b",
+			want:  "my-app-",
+		},
+		{
+			name:  "replaces invalid UTF-8",
+			input: "my\xffapp",
+			want:  "my-app",
+		},
+		{
+			name:  "keeps valid unicode and slashes",
+			input: "café/feature",
+			want:  "café/feature",
+		},
 	}
 
 	for _, tt := range tests {
@@ -166,3 +187,206 @@ func TestGenerateSessionName(t *testing.T) {
 		}
 	})
 }
+
+func TestFormatSessionName(t *testing.T) {
+	t.Run("substitutes project and id placeholders", func(t *testing.T) {
+		gen := func() (string, error) { return "abc123", nil }
+		exists := func(name string) bool { return false }
+
+		got, err := session.FormatSessionName("{id}_{project}", session.NameVars{Project: "portal"}, gen, exists)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got != "abc123_portal" {
+			t.Errorf("got %q, want %q", got, "abc123_portal")
+		}
+	})
+
+	t.Run("sanitises literal text in the format", func(t *testing.T) {
+		gen := func() (string, error) { return "abc123", nil }
+		exists := func(name string) bool { return false }
+
+		got, err := session.FormatSessionName("dev.{project}:{id}", session.NameVars{Project: "my.app"}, gen, exists)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got != "dev-my-app-abc123" {
+			t.Errorf("got %q, want %q", got, "dev-my-app-abc123")
+		}
+	})
+
+	t.Run("retries on collision", func(t *testing.T) {
+		ids := []string{"aaaaaa", "bbbbbb"}
+		call := 0
+		gen := func() (string, error) {
+			id := ids[call]
+			call++
+			return id, nil
+		}
+		exists := func(name string) bool { return name == "x-aaaaaa-portal" }
+
+		got, err := session.FormatSessionName("x-{id}-{project}", session.NameVars{Project: "portal"}, gen, exists)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got != "x-bbbbbb-portal" {
+			t.Errorf("got %q, want %q", got, "x-bbbbbb-portal")
+		}
+	})
+}
+
+func TestFormatSessionNameTemplates(t *testing.T) {
+	gen := func() (string, error) { return "abc123", nil }
+	none := func(name string) bool { return false }
+	vars := session.NameVars{
+		Project: "api",
+		Parent:  "work",
+		Branch:  "feature/login",
+		Date:    time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{name: "parent and project", tmpl: "{parent}-{project}-{id}", want: "work-api-abc123"},
+		{name: "branch", tmpl: "{project}@{branch}-{id}", want: "api@feature/login-abc123"},
+		{name: "date", tmpl: "{project}-{date}-{seq}", want: "api-2026-03-07-1"},
+		{name: "padded sequence", tmpl: "{project}-{seq:2}", want: "api-01"},
+		{name: "unpadded sequence", tmpl: "{project}{seq}", want: "api1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := session.FormatSessionName(tt.tmpl, vars, gen, none)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("sequence takes the lowest free number", func(t *testing.T) {
+		existing := map[string]bool{"api-01": true, "api-02": true, "api-04": true}
+		exists := func(name string) bool { return existing[name] }
+
+		got, err := session.FormatSessionName("{project}-{seq:2}", vars, gen, exists)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "api-03" {
+			t.Errorf("got %q, want %q", got, "api-03")
+		}
+	})
+
+	t.Run("sequence grows past its padding", func(t *testing.T) {
+		exists := func(name string) bool { return len(name) == len("api-01") }
+
+		got, err := session.FormatSessionName("{project}-{seq:2}", vars, gen, exists)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "api-100" {
+			t.Errorf("got %q, want %q", got, "api-100")
+		}
+	})
+
+	t.Run("sequence-only template does not call generator", func(t *testing.T) {
+		failing := func() (string, error) { return "", fmt.Errorf("should not be called") }
+
+		got, err := session.FormatSessionName("{project}-{seq}", vars, failing, none)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "api-1" {
+			t.Errorf("got %q, want %q", got, "api-1")
+		}
+	})
+
+	t.Run("empty branch leaves placeholder empty", func(t *testing.T) {
+		got, err := session.FormatSessionName("{project}{branch}-{seq}", session.NameVars{Project: "api"}, gen, none)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "api-1" {
+			t.Errorf("got %q, want %q", got, "api-1")
+		}
+	})
+
+	t.Run("invalid template is rejected", func(t *testing.T) {
+		_, err := session.FormatSessionName("{project}", vars, gen, none)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestValidateNameTemplate(t *testing.T) {
+	tests := []struct {
+		tmpl    string
+		wantErr string
+	}{
+		{tmpl: "{project}-{id}"},
+		{tmpl: "{project}-{seq:3}"},
+		{tmpl: "{parent}/{project}-{branch}-{date}-{seq}"},
+		{tmpl: "", wantErr: "must not be empty"},
+		{tmpl: "{project}", wantErr: "must contain {id} or {seq}"},
+		{tmpl: "{project}-{nope}-{id}", wantErr: "unknown placeholder {nope}"},
+		{tmpl: "{project}-{seq:x}", wantErr: "{seq:x}: width must be a number from 1 to 6"},
+		{tmpl: "{project}-{seq:9}", wantErr: "{seq:9}: width must be a number from 1 to 6"},
+		{tmpl: "{project:2}-{id}", wantErr: "placeholder {project} takes no argument"},
+		{tmpl: "{project-{id}", wantErr: "unbalanced braces"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tmpl, func(t *testing.T) {
+			err := session.ValidateNameTemplate(tt.tmpl)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNameFormatsFor(t *testing.T) {
+	formats := session.NameFormats{
+		Default: "{project}-{seq}",
+		Projects: map[string]string{
+			"api":            "{project}-{seq:2}",
+			"/code/work/api": "work-{seq}",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		formats session.NameFormats
+		dir     string
+		project string
+		want    string
+	}{
+		{name: "directory match wins", formats: formats, dir: "/code/work/api", project: "api", want: "work-{seq}"},
+		{name: "name match", formats: formats, dir: "/code/personal/api", project: "api", want: "{project}-{seq:2}"},
+		{name: "falls back to default", formats: formats, dir: "/code/web", project: "web", want: "{project}-{seq}"},
+		{name: "empty default uses built-in", formats: session.NameFormats{}, dir: "/code/web", project: "web", want: session.DefaultNameFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.formats.For(tt.dir, tt.project); got != tt.want {
+				t.Errorf("For(%q, %q) = %q, want %q", tt.dir, tt.project, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,16 +3,33 @@ package session
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
+// BranchResolver reports the current git branch of a directory.
+// A GitResolver that also implements BranchResolver supplies {branch}
+// to session name templates.
+type BranchResolver interface {
+	Branch(dir string) (string, error)
+}
+
+// ProjectNamer names the project at a directory. A GitResolver that also
+// implements ProjectNamer overrides the default name, the directory's
+// basename, e.g. to name a git worktree after its repository and branch.
+type ProjectNamer interface {
+	ProjectName(dir string) string
+}
+
 // PreparedSession holds the intermediate result of the shared session-preparation pipeline.
 // Both SessionCreator and QuickStart consume this to perform their respective final steps.
 type PreparedSession struct {
 	// ResolvedDir is the git root directory resolved from the input path.
 	ResolvedDir string
-	// ProjectName is derived from filepath.Base of ResolvedDir.
+	// ProjectName is derived from filepath.Base of ResolvedDir, unless the
+	// GitResolver is a ProjectNamer.
 	ProjectName string
-	// SessionName is the generated tmux session name in {project}-{nanoid} format.
+	// SessionName is the session name generated from the project's name template.
 	SessionName string
 	// ShellCmd is the constructed shell command string, empty when no command is provided.
 	ShellCmd string
@@ -21,6 +38,7 @@ type PreparedSession struct {
 // PrepareSession executes the shared session-preparation pipeline:
 // (1) resolve git root, (2) derive project name, (3) generate session name,
 // (4) upsert project in store, (5) build shell command.
+// The session name template is chosen per project from formats.
 func PrepareSession(
 	path string,
 	command []string,
@@ -28,6 +46,7 @@ func PrepareSession(
 	store ProjectStore,
 	checker SessionChecker,
 	gen IDGenerator,
+	formats NameFormats,
 	shell string,
 ) (*PreparedSession, error) {
 	resolvedDir, err := git.Resolve(path)
@@ -36,12 +55,27 @@ func PrepareSession(
 	}
 
 	projectName := filepath.Base(resolvedDir)
+	if namer, ok := git.(ProjectNamer); ok {
+		projectName = namer.ProjectName(resolvedDir)
+	}
+
+	tmpl := formats.For(resolvedDir, projectName)
+	vars := NameVars{
+		Project: projectName,
+		Parent:  filepath.Base(filepath.Dir(resolvedDir)),
+		Date:    time.Now(),
+	}
+	if br, ok := git.(BranchResolver); ok && strings.Contains(tmpl, "{branch}") {
+		// Outside a repository the branch is simply left empty.
+		vars.Branch, _ = br.Branch(resolvedDir)
+	}
 
-	exists := func(name string) bool {
-		return checker.HasSession(name)
+	exists, err := sessionExists(checker, tmpl)
+	if err != nil {
+		return nil, err
 	}
 
-	sessionName, err := GenerateSessionName(projectName, gen, exists)
+	sessionName, err := FormatSessionName(tmpl, vars, gen, exists)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate session name: %w", err)
 	}
@@ -59,3 +93,23 @@ func PrepareSession(
 		ShellCmd:    shellCmd,
 	}, nil
 }
+
+// sessionExists returns the collision check for names generated from tmpl.
+// A {seq} template may try thousands of names, so the running sessions are
+// listed once and checked in memory; other templates probe their few
+// candidates directly.
+func sessionExists(checker SessionChecker, tmpl string) (ExistsFunc, error) {
+	if !strings.Contains(tmpl, "{seq") {
+		return checker.HasSession, nil
+	}
+
+	sessions, err := checker.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	names := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		names[s.Name] = true
+	}
+	return func(name string) bool { return names[name] }, nil
+}
@@ -18,7 +18,7 @@ func TestPrepareSession(t *testing.T) {
 		checker := &mockSessionChecker{existingSessions: map[string]bool{}}
 		gen := func() (string, error) { return "abc123", nil }
 
-		result, err := session.PrepareSession(subDir, nil, gitResolver, store, checker, gen, "/bin/zsh")
+		result, err := session.PrepareSession(subDir, nil, gitResolver, store, checker, gen, session.NameFormats{}, "/bin/zsh")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -37,7 +37,7 @@ func TestPrepareSession(t *testing.T) {
 		checker := &mockSessionChecker{existingSessions: map[string]bool{}}
 		gen := func() (string, error) { return "abc123", nil }
 
-		result, err := session.PrepareSession(subDir, nil, gitResolver, store, checker, gen, "/bin/zsh")
+		result, err := session.PrepareSession(subDir, nil, gitResolver, store, checker, gen, session.NameFormats{}, "/bin/zsh")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -54,7 +54,7 @@ func TestPrepareSession(t *testing.T) {
 		checker := &mockSessionChecker{existingSessions: map[string]bool{}}
 		gen := func() (string, error) { return "x7k2m9", nil }
 
-		result, err := session.PrepareSession(dir, nil, gitResolver, store, checker, gen, "/bin/zsh")
+		result, err := session.PrepareSession(dir, nil, gitResolver, store, checker, gen, session.NameFormats{}, "/bin/zsh")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -72,7 +72,7 @@ func TestPrepareSession(t *testing.T) {
 		checker := &mockSessionChecker{existingSessions: map[string]bool{}}
 		gen := func() (string, error) { return "abc123", nil }
 
-		_, err := session.PrepareSession(gitRoot, nil, gitResolver, store, checker, gen, "/bin/zsh")
+		_, err := session.PrepareSession(gitRoot, nil, gitResolver, store, checker, gen, session.NameFormats{}, "/bin/zsh")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -94,7 +94,7 @@ func TestPrepareSession(t *testing.T) {
 		checker := &mockSessionChecker{existingSessions: map[string]bool{}}
 		gen := func() (string, error) { return "abc123", nil }
 
-		result, err := session.PrepareSession(dir, []string{"claude", "--resume"}, gitResolver, store, checker, gen, "/bin/zsh")
+		result, err := session.PrepareSession(dir, []string{"claude", "--resume"}, gitResolver, store, checker, gen, session.NameFormats{}, "/bin/zsh")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -112,7 +112,7 @@ func TestPrepareSession(t *testing.T) {
 		checker := &mockSessionChecker{existingSessions: map[string]bool{}}
 		gen := func() (string, error) { return "abc123", nil }
 
-		result, err := session.PrepareSession(dir, nil, gitResolver, store, checker, gen, "/bin/zsh")
+		result, err := session.PrepareSession(dir, nil, gitResolver, store, checker, gen, session.NameFormats{}, "/bin/zsh")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -128,7 +128,7 @@ func TestPrepareSession(t *testing.T) {
 		checker := &mockSessionChecker{existingSessions: map[string]bool{}}
 		gen := func() (string, error) { return "abc123", nil }
 
-		_, err := session.PrepareSession("/some/path", nil, gitResolver, store, checker, gen, "/bin/zsh")
+		_, err := session.PrepareSession("/some/path", nil, gitResolver, store, checker, gen, session.NameFormats{}, "/bin/zsh")
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
@@ -141,7 +141,7 @@ func TestPrepareSession(t *testing.T) {
 		checker := &mockSessionChecker{existingSessions: map[string]bool{}}
 		gen := func() (string, error) { return "", fmt.Errorf("random source exhausted") }
 
-		_, err := session.PrepareSession(dir, nil, gitResolver, store, checker, gen, "/bin/zsh")
+		_, err := session.PrepareSession(dir, nil, gitResolver, store, checker, gen, session.NameFormats{}, "/bin/zsh")
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
@@ -154,9 +154,119 @@ func TestPrepareSession(t *testing.T) {
 		checker := &mockSessionChecker{existingSessions: map[string]bool{}}
 		gen := func() (string, error) { return "abc123", nil }
 
-		_, err := session.PrepareSession(dir, nil, gitResolver, store, checker, gen, "/bin/zsh")
+		_, err := session.PrepareSession(dir, nil, gitResolver, store, checker, gen, session.NameFormats{}, "/bin/zsh")
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
 	})
+
+	t.Run("uses per-project template with parent and branch", func(t *testing.T) {
+		gitRoot := filepath.Join(t.TempDir(), "work", "api")
+		gitResolver := &mockBranchResolver{mockGitResolver: mockGitResolver{resolvedDir: gitRoot}, branch: "main"}
+		store := &mockProjectStore{}
+		checker := &mockSessionChecker{existingSessions: map[string]bool{"work-api-main-1": true}}
+		gen := func() (string, error) { return "abc123", nil }
+		formats := session.NameFormats{Projects: map[string]string{"api": "{parent}-{project}-{branch}-{seq}"}}
+
+		result, err := session.PrepareSession(gitRoot, nil, gitResolver, store, checker, gen, formats, "/bin/zsh")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.SessionName != "work-api-main-2" {
+			t.Errorf("SessionName = %q, want %q", result.SessionName, "work-api-main-2")
+		}
+		if gitResolver.branchDir != gitRoot {
+			t.Errorf("Branch called with %q, want %q", gitResolver.branchDir, gitRoot)
+		}
+	})
+
+	t.Run("lists sessions once instead of probing each sequence number", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "api")
+		gitResolver := &mockGitResolver{resolvedDir: dir}
+		checker := &mockSessionChecker{existingSessions: map[string]bool{"api-1": true, "api-2": true, "api-3": true}}
+		gen := func() (string, error) { return "abc123", nil }
+		formats := session.NameFormats{Default: "{project}-{seq}"}
+
+		result, err := session.PrepareSession(dir, nil, gitResolver, &mockProjectStore{}, checker, gen, formats, "/bin/zsh")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.SessionName != "api-4" {
+			t.Errorf("SessionName = %q, want %q", result.SessionName, "api-4")
+		}
+		if checker.listCount != 1 || checker.probeCount != 0 {
+			t.Errorf("ListSessions called %d times and HasSession %d times, want 1 and 0", checker.listCount, checker.probeCount)
+		}
+	})
+
+	t.Run("returns error when sessions cannot be listed for a sequence", func(t *testing.T) {
+		dir := t.TempDir()
+		checker := &mockSessionChecker{listErr: fmt.Errorf("server gone")}
+		gen := func() (string, error) { return "abc123", nil }
+		formats := session.NameFormats{Default: "{project}-{seq}"}
+
+		_, err := session.PrepareSession(dir, nil, &mockGitResolver{}, &mockProjectStore{}, checker, gen, formats, "/bin/zsh")
+		if err == nil || err.Error() != "failed to list sessions: server gone" {
+			t.Fatalf("err = %v, want failed to list sessions: server gone", err)
+		}
+	})
+
+	t.Run("does not look up branch when template does not use it", func(t *testing.T) {
+		dir := t.TempDir()
+		gitResolver := &mockBranchResolver{branch: "main"}
+		store := &mockProjectStore{}
+		checker := &mockSessionChecker{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+
+		if _, err := session.PrepareSession(dir, nil, gitResolver, store, checker, gen, session.NameFormats{}, "/bin/zsh"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gitResolver.branchDir != "" {
+			t.Errorf("Branch should not be called, got %q", gitResolver.branchDir)
+		}
+	})
+
+	t.Run("project namer names the project and session", func(t *testing.T) {
+		dir := t.TempDir()
+		gitResolver := &mockProjectNamer{mockGitResolver: mockGitResolver{resolvedDir: dir}, name: "api@feature"}
+		store := &mockProjectStore{}
+		checker := &mockSessionChecker{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+
+		result, err := session.PrepareSession(dir, nil, gitResolver, store, checker, gen, session.NameFormats{}, "/bin/zsh")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.ProjectName != "api@feature" || store.upsertName != "api@feature" {
+			t.Errorf("ProjectName = %q, upserted %q; want %q", result.ProjectName, store.upsertName, "api@feature")
+		}
+		if result.SessionName != "api@feature-abc123" {
+			t.Errorf("SessionName = %q, want %q", result.SessionName, "api@feature-abc123")
+		}
+	})
+}
+
+// mockProjectNamer implements session.GitResolver and session.ProjectNamer for testing.
+type mockProjectNamer struct {
+	mockGitResolver
+	name string
+}
+
+func (m *mockProjectNamer) ProjectName(dir string) string {
+	return m.name
+}
+
+// mockBranchResolver implements session.GitResolver and session.BranchResolver for testing.
+type mockBranchResolver struct {
+	mockGitResolver
+	branch    string
+	branchDir string
+}
+
+func (m *mockBranchResolver) Branch(dir string) (string, error) {
+	m.branchDir = dir
+	return m.branch, nil
 }
@@ -1,8 +1,32 @@
 package session
 
-// SessionChecker reports whether a tmux session exists by name.
+import (
+	"fmt"
+
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// SessionChecker reports which tmux sessions exist, so new session names
+// avoid them.
 type SessionChecker interface {
 	HasSession(name string) bool
+	ListSessions() ([]tmux.Session, error)
+}
+
+// Handoff builds the command lines that replace Portal with a multiplexer
+// client attached to a session.
+type Handoff interface {
+	// CreateOrAttachArgs returns argv attaching to a session and creating it
+	// when missing, or nil when the multiplexer cannot do both at once.
+	CreateOrAttachArgs(name, dir, shellCommand string) []string
+	// AttachArgs returns argv attaching to an existing session.
+	AttachArgs(name string) []string
+	// NewSession creates a detached session, for multiplexers that cannot
+	// create and attach at once.
+	NewSession(name, dir, shellCommand string) error
+	// KillSession removes a session that was created but could not be
+	// recorded.
+	KillSession(name string) error
 }
 
 // QuickStartResult contains the result of a quick-start session creation,
@@ -12,7 +36,8 @@ type QuickStartResult struct {
 	SessionName string
 	// Dir is the resolved directory (git root) where the session was created.
 	Dir string
-	// ExecArgs are the arguments for syscall.Exec to replace the process with tmux.
+	// ExecArgs are the arguments for syscall.Exec to replace the process with
+	// the multiplexer.
 	ExecArgs []string
 }
 
@@ -25,6 +50,13 @@ type QuickStart struct {
 	checker SessionChecker
 	gen     IDGenerator
 	shell   string
+	tmux    TmuxClient
+	layouts LayoutApplier
+	reg     SessionRecorder
+	handoff Handoff
+
+	nameFormats    NameFormats
+	defaultCommand []string
 }
 
 // NewQuickStart creates a QuickStart with the given dependencies.
@@ -39,19 +71,77 @@ func NewQuickStart(git GitResolver, store ProjectStore, checker SessionChecker,
 	}
 }
 
+// WithLayouts enables replaying project layouts. A project with a layout cannot
+// use the atomic new-session -A handoff, so its session is created detached via
+// tmux, the layout is applied, and the exec args attach to the finished session.
+func (qs *QuickStart) WithLayouts(tmux TmuxClient, layouts LayoutApplier) *QuickStart {
+	qs.tmux = tmux
+	qs.layouts = layouts
+	return qs
+}
+
+// WithHandoff sets the multiplexer the exec args hand over to. Without one,
+// they run tmux.
+func (qs *QuickStart) WithHandoff(handoff Handoff) *QuickStart {
+	qs.handoff = handoff
+	return qs
+}
+
+// WithNameFormats sets the session name templates; see FormatSessionName.
+func (qs *QuickStart) WithNameFormats(formats NameFormats) *QuickStart {
+	qs.nameFormats = formats
+	return qs
+}
+
+// WithDefaultCommand sets the command run in new sessions when none is given.
+func (qs *QuickStart) WithDefaultCommand(command []string) *QuickStart {
+	qs.defaultCommand = command
+	return qs
+}
+
+// WithRegistry enables recording each started session's project in reg.
+// The entry is written once the session exists, or just before an atomic exec
+// handoff, since the exec'd client creates the session and replaces Portal.
+func (qs *QuickStart) WithRegistry(reg SessionRecorder) *QuickStart {
+	qs.reg = reg
+	return qs
+}
+
 // Run executes the quick-start pipeline for the given path.
 // It resolves the git root, registers the project, generates a session name,
-// and returns the result with exec args for atomic tmux create-or-attach handoff.
+// and returns the result with exec args for atomic create-or-attach handoff.
+// When the handoff cannot create and attach at once, the session is created
+// detached first and the exec args attach to it.
 // When command is non-nil and non-empty, a shell-command is appended to exec args.
+// When command is empty, the configured default command is used instead.
 func (qs *QuickStart) Run(path string, command []string) (*QuickStartResult, error) {
-	prepared, err := PrepareSession(path, command, qs.git, qs.store, qs.checker, qs.gen, qs.shell)
+	if len(command) == 0 {
+		command = qs.defaultCommand
+	}
+
+	prepared, err := PrepareSession(path, command, qs.git, qs.store, qs.checker, qs.gen, qs.nameFormats, qs.shell)
 	if err != nil {
 		return nil, err
 	}
 
-	execArgs := []string{"tmux", "new-session", "-A", "-s", prepared.SessionName, "-c", prepared.ResolvedDir}
-	if prepared.ShellCmd != "" {
-		execArgs = append(execArgs, prepared.ShellCmd)
+	if qs.layouts != nil && qs.layouts.HasLayout(prepared.ProjectName) {
+		return qs.runWithLayout(prepared, command)
+	}
+
+	execArgs := qs.createOrAttachArgs(prepared)
+	if execArgs == nil {
+		if err := qs.handoff.NewSession(prepared.SessionName, prepared.ResolvedDir, prepared.ShellCmd); err != nil {
+			return nil, fmt.Errorf("failed to create session: %w", err)
+		}
+		if err := qs.record(prepared, command); err != nil {
+			_ = qs.handoff.KillSession(prepared.SessionName)
+			return nil, err
+		}
+		execArgs = qs.attachArgs(prepared.SessionName)
+	} else if err := qs.record(prepared, command); err != nil {
+		// The exec'd client creates the session, so the entry has to be
+		// written before the handoff replaces this process.
+		return nil, err
 	}
 
 	return &QuickStartResult{
@@ -60,3 +150,60 @@ func (qs *QuickStart) Run(path string, command []string) (*QuickStartResult, err
 		ExecArgs:    execArgs,
 	}, nil
 }
+
+// runWithLayout creates the session detached, applies the project layout, and
+// returns exec args that attach to the prepared session. The session is
+// killed if the layout fails to apply or the session cannot be recorded.
+func (qs *QuickStart) runWithLayout(prepared *PreparedSession, command []string) (*QuickStartResult, error) {
+	if err := qs.tmux.NewSession(prepared.SessionName, prepared.ResolvedDir, prepared.ShellCmd); err != nil {
+		return nil, fmt.Errorf("failed to create tmux session: %w", err)
+	}
+
+	if err := qs.layouts.ApplyLayout(prepared.SessionName, prepared.ResolvedDir, prepared.ProjectName, prepared.ShellCmd != ""); err != nil {
+		_ = qs.tmux.KillSession(prepared.SessionName)
+		return nil, fmt.Errorf("failed to apply layout: %w", err)
+	}
+
+	if err := qs.record(prepared, command); err != nil {
+		_ = qs.tmux.KillSession(prepared.SessionName)
+		return nil, err
+	}
+
+	return &QuickStartResult{
+		SessionName: prepared.SessionName,
+		Dir:         prepared.ResolvedDir,
+		ExecArgs:    qs.attachArgs(prepared.SessionName),
+	}, nil
+}
+
+// record writes the prepared session to the registry, when one is set.
+func (qs *QuickStart) record(prepared *PreparedSession, command []string) error {
+	if qs.reg == nil {
+		return nil
+	}
+	if err := qs.reg.Record(prepared.SessionName, prepared.ResolvedDir, command); err != nil {
+		return fmt.Errorf("failed to record session: %w", err)
+	}
+	return nil
+}
+
+// createOrAttachArgs returns the exec args that attach to the prepared
+// session, creating it atomically, or nil when the handoff cannot.
+func (qs *QuickStart) createOrAttachArgs(prepared *PreparedSession) []string {
+	if qs.handoff != nil {
+		return qs.handoff.CreateOrAttachArgs(prepared.SessionName, prepared.ResolvedDir, prepared.ShellCmd)
+	}
+	execArgs := []string{"tmux", "new-session", "-A", "-s", prepared.SessionName, "-c", prepared.ResolvedDir}
+	if prepared.ShellCmd != "" {
+		execArgs = append(execArgs, prepared.ShellCmd)
+	}
+	return execArgs
+}
+
+// attachArgs returns the exec args that attach to an existing session.
+func (qs *QuickStart) attachArgs(name string) []string {
+	if qs.handoff != nil {
+		return qs.handoff.AttachArgs(name)
+	}
+	return []string{"tmux", "attach-session", "-t", name}
+}
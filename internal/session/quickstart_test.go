@@ -4,20 +4,60 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"testing"
 
 	"github.com/leeovery/portal/internal/session"
+	"github.com/leeovery/portal/internal/tmux"
 )
 
 // mockSessionChecker implements session.SessionChecker for testing.
 type mockSessionChecker struct {
 	existingSessions map[string]bool
+	listErr          error
+	probeCount       int
+	listCount        int
 }
 
 func (m *mockSessionChecker) HasSession(name string) bool {
+	m.probeCount++
 	return m.existingSessions[name]
 }
 
+func (m *mockSessionChecker) ListSessions() ([]tmux.Session, error) {
+	m.listCount++
+	return listSessions(m.existingSessions), m.listErr
+}
+
+// listSessions returns the sessions named in existing.
+func listSessions(existing map[string]bool) []tmux.Session {
+	var sessions []tmux.Session
+	for name, ok := range existing {
+		if ok {
+			sessions = append(sessions, tmux.Session{Name: name})
+		}
+	}
+	return sessions
+}
+
+// mockHandoff implements session.Handoff for testing, returning no
+// create-or-attach args when atomic is false.
+type mockHandoff struct {
+	mockTmuxClient
+	atomic bool
+}
+
+func (m *mockHandoff) CreateOrAttachArgs(name, dir, shellCommand string) []string {
+	if !m.atomic {
+		return nil
+	}
+	return []string{"mux", "create-or-attach", name, dir}
+}
+
+func (m *mockHandoff) AttachArgs(name string) []string {
+	return []string{"mux", "attach", name}
+}
+
 func TestQuickStart(t *testing.T) {
 	namePattern := regexp.MustCompile(`^[a-zA-Z0-9_-]+-[a-zA-Z0-9]{6}$`)
 
@@ -272,6 +312,29 @@ func TestQuickStart(t *testing.T) {
 		}
 	})
 
+	t.Run("default command and name format apply to exec args", func(t *testing.T) {
+		t.Setenv("SHELL", "/bin/zsh")
+		dir := t.TempDir()
+		gitResolver := &mockGitResolver{}
+		store := &mockProjectStore{}
+		checker := &mockSessionChecker{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+
+		qs := session.NewQuickStart(gitResolver, store, checker, gen).
+			WithNameFormats(session.NameFormats{Default: "work-{id}"}).
+			WithDefaultCommand([]string{"nvim"})
+
+		result, err := qs.Run(dir, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		wantArgs := []string{"tmux", "new-session", "-A", "-s", "work-abc123", "-c", dir, "/bin/zsh -ic 'nvim; exec /bin/zsh'"}
+		if !slices.Equal(result.ExecArgs, wantArgs) {
+			t.Errorf("result.ExecArgs = %v, want %v", result.ExecArgs, wantArgs)
+		}
+	})
+
 	t.Run("returns error when git resolution fails", func(t *testing.T) {
 		gitResolver := &mockGitResolver{err: fmt.Errorf("git error")}
 		store := &mockProjectStore{}
@@ -285,4 +348,192 @@ func TestQuickStart(t *testing.T) {
 			t.Fatal("expected error, got nil")
 		}
 	})
+	t.Run("creates session detached and attaches when project has a layout", func(t *testing.T) {
+		dir := t.TempDir()
+		gitResolver := &mockGitResolver{}
+		store := &mockProjectStore{}
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+		layouts := &mockLayoutApplier{layouts: map[string]bool{filepath.Base(dir): true}}
+
+		qs := session.NewQuickStart(gitResolver, store, tmuxClient, gen).WithLayouts(tmuxClient, layouts)
+
+		result, err := qs.Run(dir, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		wantSessionName := filepath.Base(dir) + "-abc123"
+		if tmuxClient.newSessionName != wantSessionName {
+			t.Errorf("new session name = %q, want %q", tmuxClient.newSessionName, wantSessionName)
+		}
+		if layouts.applyCount != 1 || layouts.appliedSession != wantSessionName {
+			t.Errorf("ApplyLayout called %d times for %q, want once for %q", layouts.applyCount, layouts.appliedSession, wantSessionName)
+		}
+		wantArgs := []string{"tmux", "attach-session", "-t", wantSessionName}
+		if len(result.ExecArgs) != len(wantArgs) {
+			t.Fatalf("result.ExecArgs = %v, want %v", result.ExecArgs, wantArgs)
+		}
+		for i, arg := range result.ExecArgs {
+			if arg != wantArgs[i] {
+				t.Errorf("result.ExecArgs[%d] = %q, want %q", i, arg, wantArgs[i])
+			}
+		}
+	})
+
+	t.Run("kills the session and returns error when layout fails to apply", func(t *testing.T) {
+		dir := t.TempDir()
+		gitResolver := &mockGitResolver{}
+		store := &mockProjectStore{}
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+		layouts := &mockLayoutApplier{layouts: map[string]bool{filepath.Base(dir): true}, err: fmt.Errorf("bad layout")}
+
+		qs := session.NewQuickStart(gitResolver, store, tmuxClient, gen).WithLayouts(tmuxClient, layouts)
+
+		_, err := qs.Run(dir, nil)
+		if err == nil || err.Error() != "failed to apply layout: bad layout" {
+			t.Fatalf("err = %v, want failed to apply layout: bad layout", err)
+		}
+		wantSessionName := filepath.Base(dir) + "-abc123"
+		if len(tmuxClient.killed) != 1 || tmuxClient.killed[0] != wantSessionName {
+			t.Errorf("killed %v, want [%s]", tmuxClient.killed, wantSessionName)
+		}
+	})
+
+	t.Run("keeps atomic new-session -A handoff when project has no layout", func(t *testing.T) {
+		dir := t.TempDir()
+		gitResolver := &mockGitResolver{}
+		store := &mockProjectStore{}
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+		layouts := &mockLayoutApplier{layouts: map[string]bool{}}
+
+		qs := session.NewQuickStart(gitResolver, store, tmuxClient, gen).WithLayouts(tmuxClient, layouts)
+
+		result, err := qs.Run(dir, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if tmuxClient.newSessionName != "" {
+			t.Errorf("session should not be pre-created, got %q", tmuxClient.newSessionName)
+		}
+		if layouts.applyCount != 0 {
+			t.Errorf("ApplyLayout called %d times, want 0", layouts.applyCount)
+		}
+		if len(result.ExecArgs) < 3 || result.ExecArgs[1] != "new-session" || result.ExecArgs[2] != "-A" {
+			t.Errorf("result.ExecArgs = %v, want tmux new-session -A handoff", result.ExecArgs)
+		}
+	})
+	t.Run("records session in registry before exec handoff", func(t *testing.T) {
+		dir := t.TempDir()
+		gitResolver := &mockGitResolver{}
+		store := &mockProjectStore{}
+		checker := &mockSessionChecker{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+		reg := &mockSessionRecorder{}
+
+		qs := session.NewQuickStart(gitResolver, store, checker, gen).WithRegistry(reg)
+
+		result, err := qs.Run(dir, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if reg.name != result.SessionName || reg.projectPath != dir {
+			t.Errorf("recorded %q at %q, want %q at %q", reg.name, reg.projectPath, result.SessionName, dir)
+		}
+	})
+	t.Run("exec args come from the handoff", func(t *testing.T) {
+		dir := t.TempDir()
+		handoff := &mockHandoff{mockTmuxClient: mockTmuxClient{existingSessions: map[string]bool{}}, atomic: true}
+		gen := func() (string, error) { return "abc123", nil }
+
+		qs := session.NewQuickStart(&mockGitResolver{}, &mockProjectStore{}, handoff, gen).WithHandoff(handoff)
+
+		result, err := qs.Run(dir, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []string{"mux", "create-or-attach", result.SessionName, dir}
+		if !slices.Equal(result.ExecArgs, want) {
+			t.Errorf("result.ExecArgs = %v, want %v", result.ExecArgs, want)
+		}
+		if handoff.newSessionName != "" {
+			t.Errorf("session should not be pre-created, got %q", handoff.newSessionName)
+		}
+	})
+
+	t.Run("creates session first when the handoff cannot create and attach at once", func(t *testing.T) {
+		dir := t.TempDir()
+		handoff := &mockHandoff{mockTmuxClient: mockTmuxClient{existingSessions: map[string]bool{}}}
+		gen := func() (string, error) { return "abc123", nil }
+
+		qs := session.NewQuickStart(&mockGitResolver{}, &mockProjectStore{}, handoff, gen).WithHandoff(handoff)
+
+		result, err := qs.Run(dir, []string{"claude"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if handoff.newSessionName != result.SessionName || handoff.newSessionDir != dir || handoff.newSessionShellCmd == "" {
+			t.Errorf("NewSession(%q, %q, %q), want the prepared session with its command", handoff.newSessionName, handoff.newSessionDir, handoff.newSessionShellCmd)
+		}
+		want := []string{"mux", "attach", result.SessionName}
+		if !slices.Equal(result.ExecArgs, want) {
+			t.Errorf("result.ExecArgs = %v, want %v", result.ExecArgs, want)
+		}
+	})
+
+	t.Run("records the detached session only once it exists", func(t *testing.T) {
+		dir := t.TempDir()
+		handoff := &mockHandoff{mockTmuxClient: mockTmuxClient{existingSessions: map[string]bool{}, newSessionErr: fmt.Errorf("boom")}}
+		gen := func() (string, error) { return "abc123", nil }
+		reg := &mockSessionRecorder{}
+
+		qs := session.NewQuickStart(&mockGitResolver{}, &mockProjectStore{}, handoff, gen).WithHandoff(handoff).WithRegistry(reg)
+
+		if _, err := qs.Run(dir, nil); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if reg.name != "" {
+			t.Errorf("recorded %q, want nothing recorded for a failed create", reg.name)
+		}
+	})
+
+	t.Run("kills the detached session when it cannot be recorded", func(t *testing.T) {
+		dir := t.TempDir()
+		handoff := &mockHandoff{mockTmuxClient: mockTmuxClient{existingSessions: map[string]bool{}}}
+		gen := func() (string, error) { return "abc123", nil }
+		reg := &mockSessionRecorder{err: fmt.Errorf("disk full")}
+
+		qs := session.NewQuickStart(&mockGitResolver{}, &mockProjectStore{}, handoff, gen).WithHandoff(handoff).WithRegistry(reg)
+
+		if _, err := qs.Run(dir, nil); err == nil || err.Error() != "failed to record session: disk full" {
+			t.Fatalf("err = %v, want failed to record session: disk full", err)
+		}
+		if len(handoff.killed) != 1 || handoff.killed[0] != handoff.newSessionName {
+			t.Errorf("killed %v, want the new session %q", handoff.killed, handoff.newSessionName)
+		}
+	})
+
+	t.Run("records nothing when the layout fails to apply", func(t *testing.T) {
+		dir := t.TempDir()
+		checker := &mockSessionChecker{existingSessions: map[string]bool{}}
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		layouts := &mockLayoutApplier{layouts: map[string]bool{filepath.Base(dir): true}, err: fmt.Errorf("bad layout")}
+		gen := func() (string, error) { return "abc123", nil }
+		reg := &mockSessionRecorder{}
+
+		qs := session.NewQuickStart(&mockGitResolver{}, &mockProjectStore{}, checker, gen).WithLayouts(tmuxClient, layouts).WithRegistry(reg)
+
+		if _, err := qs.Run(dir, nil); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if reg.name != "" {
+			t.Errorf("recorded %q, want nothing recorded for a killed session", reg.name)
+		}
+	})
 }
@@ -0,0 +1,107 @@
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/leeovery/portal/internal/layout"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// ErrRunning is returned by Restore when a session with the captured name is
+// already running.
+var ErrRunning = errors.New("already running")
+
+// Target is the set of tmux operations needed to rebuild a session.
+// It is satisfied by *tmux.Client.
+type Target interface {
+	layout.Target
+	HasSession(name string) bool
+	NewSession(name, dir, shellCommand string) error
+	KillSession(name string) error
+}
+
+// Restore rebuilds s as a new detached session with its windows, panes,
+// layouts and directories. When runCommands is true, each pane's captured
+// foreground command is typed into it. A session that fails to rebuild is
+// killed, so it is not skipped as running next time.
+func Restore(t Target, s Session, runCommands bool) error {
+	if t.HasSession(s.Name) {
+		return fmt.Errorf("session %q is %w", s.Name, ErrRunning)
+	}
+	if len(s.Windows) == 0 {
+		return fmt.Errorf("session %q has no windows to restore", s.Name)
+	}
+
+	if err := t.NewSession(s.Name, s.Dir(), ""); err != nil {
+		return err
+	}
+	if err := layout.Apply(t, s.Name, s.Dir(), toLayout(s, runCommands), false); err != nil {
+		_ = t.KillSession(s.Name)
+		return fmt.Errorf("failed to restore session %q: %w", s.Name, err)
+	}
+	return nil
+}
+
+// toLayout describes a captured session as a layout, so it is rebuilt the
+// same way project layouts are. Captured layouts are tmux layout strings,
+// which restore the exact pane geometry.
+func toLayout(s Session, runCommands bool) *layout.Layout {
+	l := &layout.Layout{Windows: make([]layout.Window, len(s.Windows))}
+	for wi, w := range s.Windows {
+		panes := make([]layout.Pane, len(w.Panes))
+		for pi, p := range w.Panes {
+			panes[pi] = layout.Pane{Dir: p.Dir, Focus: p.Active}
+			if runCommands {
+				panes[pi].Command = p.Command
+			}
+		}
+		l.Windows[wi] = layout.Window{Name: w.Name, Layout: w.Layout, Panes: panes, Focus: w.Active}
+	}
+	return l
+}
+
+// Restorer lists the sessions of the saved snapshot and rebuilds them one at
+// a time, for the TUI.
+type Restorer struct {
+	store       *Store
+	target      Target
+	runCommands bool
+}
+
+// NewRestorer creates a Restorer reading store and rebuilding through target.
+// When runCommands is true, restored panes re-run their captured commands.
+func NewRestorer(store *Store, target Target, runCommands bool) *Restorer {
+	return &Restorer{store: store, target: target, runCommands: runCommands}
+}
+
+// SavedSessions returns every session in the saved snapshot, marked exited.
+// It returns none when nothing has been saved.
+func (r *Restorer) SavedSessions() ([]tmux.Session, error) {
+	snap, err := r.store.Load()
+	if errors.Is(err, ErrNoSnapshot) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]tmux.Session, 0, len(snap.Sessions))
+	for _, s := range snap.Sessions {
+		sessions = append(sessions, tmux.Session{Name: s.Name, Windows: len(s.Windows), Path: s.Dir(), Exited: true})
+	}
+	return sessions, nil
+}
+
+// Restore rebuilds the named session from the saved snapshot.
+func (r *Restorer) Restore(name string) error {
+	snap, err := r.store.Load()
+	if err != nil {
+		return err
+	}
+	s, ok := snap.Session(name)
+	if !ok {
+		return fmt.Errorf("session %q is not in the snapshot", name)
+	}
+	return Restore(r.target, s, r.runCommands)
+}
@@ -0,0 +1,139 @@
+package snapshot_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/snapshot"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+func TestRestore(t *testing.T) {
+	snap, err := snapshot.Capture(tmux.NewClient(&recordingCommander{panes: panesOutput}), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	api, _ := snap.Session("api")
+
+	t.Run("rebuilds windows, panes, layouts and focus", func(t *testing.T) {
+		cmd := &recordingCommander{}
+
+		if err := snapshot.Restore(tmux.NewClient(cmd), api, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []string{
+			"has-session -t api",
+			"new-session -d -s api -c /code/api",
+			"display-message -p -t api: #{window_id}|#{pane_id}",
+			"rename-window -t @0 editor",
+			"split-window -d -v -t %0 -c /code/api/src -P -F #{pane_id}",
+			"select-layout -t @0 c1,80x24,0,0{40x24,0,0,1,39x24,41,0,2}",
+			"select-pane -t %1",
+			"new-window -d -t api: -c /code/api -P -F #{window_id}|#{pane_id} -n server",
+			"select-layout -t @1 b2,80x24,0,0,3",
+			"select-pane -t %2",
+			"select-window -t @0",
+		}
+		assertCalls(t, cmd.calls, want)
+	})
+
+	t.Run("re-runs captured commands when asked", func(t *testing.T) {
+		cmd := &recordingCommander{}
+
+		if err := snapshot.Restore(tmux.NewClient(cmd), api, true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		sent := 0
+		for _, c := range cmd.calls {
+			if c == "send-keys -t %1 nvim Enter" || c == "send-keys -t %2 make Enter" {
+				sent++
+			}
+		}
+		if sent != 2 {
+			t.Errorf("calls = %v, want nvim and make sent", cmd.calls)
+		}
+	})
+
+	t.Run("kills a session that fails to rebuild", func(t *testing.T) {
+		cmd := &recordingCommander{fail: "split-window"}
+
+		if err := snapshot.Restore(tmux.NewClient(cmd), api, false); err == nil {
+			t.Fatal("expected an error")
+		}
+		if last := cmd.calls[len(cmd.calls)-1]; last != "kill-session -t api" {
+			t.Errorf("calls = %v, want the session killed last", cmd.calls)
+		}
+	})
+
+	t.Run("refuses a running session", func(t *testing.T) {
+		cmd := &recordingCommander{sessions: []string{"api"}}
+
+		err := snapshot.Restore(tmux.NewClient(cmd), api, false)
+		if !errors.Is(err, snapshot.ErrRunning) {
+			t.Errorf("error = %v, want ErrRunning", err)
+		}
+		if len(cmd.calls) != 1 {
+			t.Errorf("calls = %v, want only the running check", cmd.calls)
+		}
+	})
+}
+
+func TestRestorer(t *testing.T) {
+	store := snapshot.NewStore(filepath.Join(t.TempDir(), "snapshot.json"))
+	cmd := &recordingCommander{}
+	restorer := snapshot.NewRestorer(store, tmux.NewClient(cmd), false)
+
+	t.Run("lists nothing before a snapshot is saved", func(t *testing.T) {
+		saved, err := restorer.SavedSessions()
+		if err != nil || len(saved) != 0 {
+			t.Errorf("SavedSessions() = %v, %v; want none", saved, err)
+		}
+	})
+
+	snap, _ := snapshot.Capture(tmux.NewClient(&recordingCommander{panes: panesOutput}), time.Now())
+	if err := store.Save(snap); err != nil {
+		t.Fatalf("failed to save snapshot: %v", err)
+	}
+
+	t.Run("lists saved sessions as exited", func(t *testing.T) {
+		saved, err := restorer.SavedSessions()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []tmux.Session{
+			{Name: "api", Windows: 2, Path: "/code/api", Exited: true},
+			{Name: "notes", Windows: 1, Path: "/home/me/notes", Exited: true},
+		}
+		if len(saved) != len(want) || saved[0] != want[0] || saved[1] != want[1] {
+			t.Errorf("SavedSessions() = %+v, want %+v", saved, want)
+		}
+	})
+
+	t.Run("restores a saved session by name", func(t *testing.T) {
+		if err := restorer.Restore("notes"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cmd.calls[1] != "new-session -d -s notes -c /home/me/notes" {
+			t.Errorf("calls = %v", cmd.calls)
+		}
+		if err := restorer.Restore("gone"); err == nil {
+			t.Error("expected an error for a session missing from the snapshot")
+		}
+	})
+}
+
+func assertCalls(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d calls, want %d:\ngot:  %q\nwant: %q", len(got), len(want), got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("call %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
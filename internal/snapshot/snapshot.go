@@ -0,0 +1,124 @@
+// Package snapshot captures the layout of running tmux sessions to a file and
+// rebuilds them after the tmux server restarts.
+package snapshot
+
+import (
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// Version is the snapshot file format this package writes. Load rejects
+// files written by a newer Portal.
+const Version = 1
+
+// Snapshot is every session captured at one moment.
+type Snapshot struct {
+	Version  int       `json:"version"`
+	TakenAt  time.Time `json:"taken_at"`
+	Sessions []Session `json:"sessions"`
+}
+
+// Session is one captured session.
+type Session struct {
+	Name    string   `json:"name"`
+	Windows []Window `json:"windows"`
+}
+
+// Window is one captured window, in index order.
+type Window struct {
+	Name string `json:"name"`
+	// Layout is tmux's description of the pane geometry, which select-layout
+	// accepts to restore it exactly.
+	Layout string `json:"layout"`
+	Active bool   `json:"active,omitempty"`
+	Panes  []Pane `json:"panes"`
+}
+
+// Pane is one captured pane, in index order.
+type Pane struct {
+	Dir string `json:"dir"`
+	// Command is the pane's foreground program, empty when it was idle at a
+	// shell prompt.
+	Command string `json:"command,omitempty"`
+	Active  bool   `json:"active,omitempty"`
+}
+
+// Dir returns the directory the session's first pane was in, where a
+// restored session starts.
+func (s Session) Dir() string {
+	if len(s.Windows) == 0 || len(s.Windows[0].Panes) == 0 {
+		return ""
+	}
+	return s.Windows[0].Panes[0].Dir
+}
+
+// Session returns the captured session with the given name.
+func (s Snapshot) Session(name string) (Session, bool) {
+	i := slices.IndexFunc(s.Sessions, func(sess Session) bool { return sess.Name == name })
+	if i < 0 {
+		return Session{}, false
+	}
+	return s.Sessions[i], true
+}
+
+// Match returns the captured sessions whose names match the glob pattern, or
+// every session when pattern is empty. The pattern must be valid; see
+// path.Match.
+func (s Snapshot) Match(pattern string) []Session {
+	if pattern == "" {
+		return s.Sessions
+	}
+	var matched []Session
+	for _, sess := range s.Sessions {
+		if ok, _ := path.Match(pattern, sess.Name); ok {
+			matched = append(matched, sess)
+		}
+	}
+	return matched
+}
+
+// PaneLister lists every pane of every running session. It is satisfied by
+// *tmux.Client.
+type PaneLister interface {
+	ListPanes() ([]tmux.Pane, error)
+}
+
+// shells are foreground commands that mean a pane is idle at a prompt.
+var shells = []string{"sh", "bash", "zsh", "fish", "dash", "ksh", "tcsh", "csh", "nu", "elvish", "xonsh"}
+
+// Capture snapshots every running session's windows, panes, layouts,
+// directories and foreground commands. A server without sessions yields an
+// empty snapshot.
+func Capture(lister PaneLister, now time.Time) (Snapshot, error) {
+	panes, err := lister.ListPanes()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snap := Snapshot{Version: Version, TakenAt: now.UTC(), Sessions: []Session{}}
+	var window int
+	for _, p := range panes {
+		newSession := len(snap.Sessions) == 0 || snap.Sessions[len(snap.Sessions)-1].Name != p.Session
+		if newSession {
+			snap.Sessions = append(snap.Sessions, Session{Name: p.Session})
+		}
+		sess := &snap.Sessions[len(snap.Sessions)-1]
+
+		if newSession || p.WindowIndex != window {
+			sess.Windows = append(sess.Windows, Window{Name: p.WindowName, Layout: p.WindowLayout, Active: p.WindowActive})
+			window = p.WindowIndex
+		}
+		w := &sess.Windows[len(sess.Windows)-1]
+
+		command := p.Command
+		if slices.Contains(shells, strings.TrimPrefix(command, "-")) {
+			command = ""
+		}
+		w.Panes = append(w.Panes, Pane{Dir: p.Path, Command: command, Active: p.PaneActive})
+	}
+	return snap, nil
+}
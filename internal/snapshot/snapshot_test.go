@@ -0,0 +1,118 @@
+package snapshot_test
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/snapshot"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// recordingCommander implements tmux.Commander like a real tmux server: it
+// lists the configured panes, knows the sessions it has created, hands out
+// sequential window and pane IDs, and records every call. Calls to the fail
+// command return an error.
+type recordingCommander struct {
+	panes      string
+	sessions   []string
+	calls      []string
+	nextWindow int
+	nextPane   int
+	fail       string
+}
+
+func (r *recordingCommander) Run(args ...string) (string, error) {
+	r.calls = append(r.calls, strings.Join(args, " "))
+	if args[0] == r.fail {
+		return "", fmt.Errorf("%s failed", r.fail)
+	}
+	switch args[0] {
+	case "list-panes":
+		return r.panes, nil
+	case "has-session":
+		if !slices.Contains(r.sessions, args[2]) {
+			return "", fmt.Errorf("no such session")
+		}
+	case "new-session":
+		r.sessions = append(r.sessions, args[3])
+	case "display-message":
+		return "@0|%0", nil
+	case "new-window":
+		r.nextWindow++
+		r.nextPane++
+		return fmt.Sprintf("@%d|%%%d", r.nextWindow, r.nextPane), nil
+	case "split-window":
+		r.nextPane++
+		return fmt.Sprintf("%%%d", r.nextPane), nil
+	}
+	return "", nil
+}
+
+// panesOutput is list-panes output for two sessions: api with an editor window
+// split in two and a server window, and notes with one idle pane.
+const panesOutput = "1\t1\t0\tc1,80x24,0,0{40x24,0,0,1,39x24,41,0,2}\t-zsh\teditor\t/code/api\tapi\n" +
+	"1\t1\t1\tc1,80x24,0,0{40x24,0,0,1,39x24,41,0,2}\tnvim\teditor\t/code/api/src\tapi\n" +
+	"2\t0\t1\tb2,80x24,0,0,3\tmake\tserver\t/code/api\tapi\n" +
+	"1\t1\t1\ta3,80x24,0,0,4\tbash\tnotes\t/home/me/notes\tnotes"
+
+func TestCapture(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	snap, err := snapshot.Capture(tmux.NewClient(&recordingCommander{panes: panesOutput}), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := snapshot.Snapshot{
+		Version: snapshot.Version,
+		TakenAt: now,
+		Sessions: []snapshot.Session{
+			{Name: "api", Windows: []snapshot.Window{
+				{Name: "editor", Layout: "c1,80x24,0,0{40x24,0,0,1,39x24,41,0,2}", Active: true, Panes: []snapshot.Pane{
+					{Dir: "/code/api"},
+					{Dir: "/code/api/src", Command: "nvim", Active: true},
+				}},
+				{Name: "server", Layout: "b2,80x24,0,0,3", Panes: []snapshot.Pane{
+					{Dir: "/code/api", Command: "make", Active: true},
+				}},
+			}},
+			{Name: "notes", Windows: []snapshot.Window{
+				{Name: "notes", Layout: "a3,80x24,0,0,4", Active: true, Panes: []snapshot.Pane{
+					{Dir: "/home/me/notes", Active: true},
+				}},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(snap, want) {
+		t.Errorf("Capture() = %+v\nwant %+v", snap, want)
+	}
+
+	t.Run("no server yields an empty snapshot", func(t *testing.T) {
+		snap, err := snapshot.Capture(tmux.NewClient(&recordingCommander{}), now)
+		if err != nil || len(snap.Sessions) != 0 {
+			t.Errorf("Capture() = %+v, %v; want no sessions", snap, err)
+		}
+	})
+}
+
+func TestMatch(t *testing.T) {
+	snap := snapshot.Snapshot{Sessions: []snapshot.Session{{Name: "api-1"}, {Name: "api-2"}, {Name: "web"}}}
+
+	names := func(sessions []snapshot.Session) []string {
+		var out []string
+		for _, s := range sessions {
+			out = append(out, s.Name)
+		}
+		return out
+	}
+	if got := names(snap.Match("api-*")); !slices.Equal(got, []string{"api-1", "api-2"}) {
+		t.Errorf("Match(api-*) = %v", got)
+	}
+	if got := names(snap.Match("")); len(got) != 3 {
+		t.Errorf("Match(\"\") = %v, want every session", got)
+	}
+}
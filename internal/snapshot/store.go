@@ -0,0 +1,113 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrNoSnapshot is returned by Load when no snapshot has been saved.
+var ErrNoSnapshot = errors.New("no snapshot saved; run xctl snapshot first")
+
+// Store persists a snapshot to a JSON file.
+type Store struct {
+	path string
+}
+
+// NewStore creates a Store that reads and writes the given file path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path returns the file the store reads and writes.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Stale reports whether the saved snapshot was written more than interval
+// before now, or has never been written.
+func (s *Store) Stale(interval time.Duration, now time.Time) bool {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return true
+	}
+	return now.Sub(info.ModTime()) >= interval
+}
+
+// Drops reports whether saving snap would lose sessions of the saved snapshot
+// that are not in snap, such as the sessions of a server that has not been
+// restored since a reboot. It reports false when nothing readable is saved.
+func (s *Store) Drops(snap Snapshot) bool {
+	saved, err := s.Load()
+	if err != nil {
+		return false
+	}
+	for _, session := range saved.Sessions {
+		if _, ok := snap.Session(session.Name); !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads the saved snapshot. It returns ErrNoSnapshot when the file is
+// missing, and an error for malformed files and unsupported versions.
+func (s *Store) Load() (Snapshot, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Snapshot{}, ErrNoSnapshot
+		}
+		return Snapshot{}, err
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return Snapshot{}, fmt.Errorf("invalid snapshot %s: %w", s.path, err)
+	}
+	if snap.Version < 1 || snap.Version > Version {
+		return Snapshot{}, fmt.Errorf("snapshot %s has unsupported version %d (this Portal reads up to %d)", s.path, snap.Version, Version)
+	}
+	return snap, nil
+}
+
+// Save writes snap to the JSON file using atomic write (temp file + rename).
+// Creates the parent directory if it does not exist.
+func (s *Store) Save(snap Snapshot) error {
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "snapshot-*.json.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	return nil
+}
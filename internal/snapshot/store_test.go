@@ -0,0 +1,89 @@
+package snapshot_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/snapshot"
+)
+
+func TestStore(t *testing.T) {
+	t.Run("round-trips a snapshot", func(t *testing.T) {
+		store := snapshot.NewStore(filepath.Join(t.TempDir(), "nested", "snapshot.json"))
+		snap := snapshot.Snapshot{
+			Version:  snapshot.Version,
+			TakenAt:  time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+			Sessions: []snapshot.Session{{Name: "api", Windows: []snapshot.Window{{Name: "editor", Panes: []snapshot.Pane{{Dir: "/code/api"}}}}}},
+		}
+
+		if err := store.Save(snap); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+		got, err := store.Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if !reflect.DeepEqual(got, snap) {
+			t.Errorf("Load() = %+v, want %+v", got, snap)
+		}
+	})
+
+	t.Run("missing file reports no snapshot", func(t *testing.T) {
+		_, err := snapshot.NewStore(filepath.Join(t.TempDir(), "snapshot.json")).Load()
+		if !errors.Is(err, snapshot.ErrNoSnapshot) {
+			t.Errorf("Load() error = %v, want ErrNoSnapshot", err)
+		}
+	})
+
+	t.Run("rejects unsupported versions", func(t *testing.T) {
+		for _, content := range []string{`{"version": 99, "sessions": []}`, `{"sessions": []}`} {
+			path := filepath.Join(t.TempDir(), "snapshot.json")
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatalf("failed to write snapshot: %v", err)
+			}
+			if _, err := snapshot.NewStore(path).Load(); err == nil {
+				t.Errorf("Load(%s) succeeded, want a version error", content)
+			}
+		}
+	})
+
+	t.Run("drops saved sessions missing from a new snapshot", func(t *testing.T) {
+		store := snapshot.NewStore(filepath.Join(t.TempDir(), "snapshot.json"))
+		api := snapshot.Session{Name: "api"}
+		web := snapshot.Session{Name: "web"}
+		if store.Drops(snapshot.Snapshot{Sessions: []snapshot.Session{web}}) {
+			t.Error("nothing saved should drop nothing")
+		}
+		if err := store.Save(snapshot.Snapshot{Version: snapshot.Version, Sessions: []snapshot.Session{api, web}}); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+
+		if !store.Drops(snapshot.Snapshot{Sessions: []snapshot.Session{web}}) {
+			t.Error("a snapshot without api should drop it")
+		}
+		if store.Drops(snapshot.Snapshot{Sessions: []snapshot.Session{api, web, {Name: "db"}}}) {
+			t.Error("a snapshot with every saved session should drop nothing")
+		}
+	})
+
+	t.Run("stale until saved within the interval", func(t *testing.T) {
+		store := snapshot.NewStore(filepath.Join(t.TempDir(), "snapshot.json"))
+		now := time.Now()
+		if !store.Stale(time.Hour, now) {
+			t.Error("missing snapshot should be stale")
+		}
+		if err := store.Save(snapshot.Snapshot{Version: snapshot.Version}); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+		if store.Stale(time.Hour, now) {
+			t.Error("fresh snapshot should not be stale")
+		}
+		if !store.Stale(time.Hour, now.Add(2*time.Hour)) {
+			t.Error("old snapshot should be stale")
+		}
+	})
+}
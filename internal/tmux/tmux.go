@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Session represents a running tmux session.
@@ -12,6 +13,23 @@ type Session struct {
 	Name     string
 	Windows  int
 	Attached bool
+	// Clients is the number of clients attached to the session.
+	Clients int
+	// Created is when the session was created.
+	Created time.Time
+	// LastAttached is when a client last attached; zero if never attached.
+	LastAttached time.Time
+	// Activity is the time of the last activity in any of the session's windows.
+	Activity time.Time
+	// Path is the session's working directory.
+	Path string
+	// Group is the name of the session group, empty if the session is not grouped.
+	Group string
+	// ActiveWindow is the name of the session's current window.
+	ActiveWindow string
+	// Exited reports a session whose processes have ended but which attaching
+	// resurrects. tmux never lists these; other multiplexers may.
+	Exited bool
 }
 
 // Commander defines the interface for executing tmux commands.
@@ -63,10 +81,18 @@ func (c *Client) NewSession(name, dir, shellCommand string) error {
 	return nil
 }
 
+// sessionFormat is the list-sessions format parsed by parseSession. Fields are
+// tab-separated because window names and paths routinely contain "|"; the
+// session name comes last so names containing a tab still parse intact.
+const sessionFormat = "#{session_windows}\t#{session_attached}\t#{session_created}\t#{session_last_attached}\t#{session_activity}\t#{session_group}\t#{window_name}\t#{session_path}\t#{session_name}"
+
+// sessionFields is the number of fields in sessionFormat.
+const sessionFields = 9
+
 // ListSessions queries tmux for running sessions and returns them as structured data.
 // Returns an empty slice and nil error when no tmux server is running.
 func (c *Client) ListSessions() ([]Session, error) {
-	output, err := c.cmd.Run("list-sessions", "-F", "#{session_name}|#{session_windows}|#{session_attached}")
+	output, err := c.cmd.Run("list-sessions", "-F", sessionFormat)
 	if err != nil {
 		return []Session{}, nil
 	}
@@ -84,29 +110,65 @@ func (c *Client) ListSessions() ([]Session, error) {
 			continue
 		}
 
-		parts := strings.SplitN(line, "|", 3)
-		if len(parts) != 3 {
-			return nil, fmt.Errorf("unexpected session format: %q", line)
-		}
-
-		windows, err := strconv.Atoi(parts[1])
+		session, err := parseSession(line)
 		if err != nil {
-			return nil, fmt.Errorf("invalid window count %q: %w", parts[1], err)
+			return nil, err
 		}
+		sessions = append(sessions, session)
+	}
 
-		attachedCount, err := strconv.Atoi(parts[2])
-		if err != nil {
-			return nil, fmt.Errorf("invalid attached count %q: %w", parts[2], err)
-		}
+	return sessions, nil
+}
 
-		sessions = append(sessions, Session{
-			Name:     parts[0],
-			Windows:  windows,
-			Attached: attachedCount > 0,
-		})
+// parseSession parses one line of list-sessions output in sessionFormat.
+func parseSession(line string) (Session, error) {
+	parts := strings.SplitN(line, "\t", sessionFields)
+	if len(parts) != sessionFields {
+		return Session{}, fmt.Errorf("unexpected session format: %q", line)
 	}
 
-	return sessions, nil
+	windows, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return Session{}, fmt.Errorf("invalid window count %q: %w", parts[0], err)
+	}
+
+	clients, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Session{}, fmt.Errorf("invalid attached count %q: %w", parts[1], err)
+	}
+
+	var times [3]time.Time
+	for i, field := range parts[2:5] {
+		if times[i], err = parseUnixTime(field); err != nil {
+			return Session{}, err
+		}
+	}
+
+	return Session{
+		Name:         parts[8],
+		Windows:      windows,
+		Attached:     clients > 0,
+		Clients:      clients,
+		Created:      times[0],
+		LastAttached: times[1],
+		Activity:     times[2],
+		Group:        parts[5],
+		ActiveWindow: parts[6],
+		Path:         parts[7],
+	}, nil
+}
+
+// parseUnixTime parses a tmux timestamp in seconds since the epoch.
+// tmux prints an empty string or 0 for times that never happened.
+func parseUnixTime(field string) (time.Time, error) {
+	if field == "" || field == "0" {
+		return time.Time{}, nil
+	}
+	secs, err := strconv.ParseInt(field, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", field, err)
+	}
+	return time.Unix(secs, 0), nil
 }
 
 // CurrentSessionName returns the name of the tmux session that the current client
@@ -128,6 +190,25 @@ func (c *Client) KillSession(name string) error {
 	return nil
 }
 
+// DetachSession detaches every client attached to the named session.
+func (c *Client) DetachSession(name string) error {
+	_, err := c.cmd.Run("detach-client", "-s", name)
+	if err != nil {
+		return fmt.Errorf("failed to detach tmux session %q: %w", name, err)
+	}
+	return nil
+}
+
+// CapturePane returns the visible contents of the active pane of the named
+// session's current window, as plain text.
+func (c *Client) CapturePane(name string) (string, error) {
+	output, err := c.cmd.Run("capture-pane", "-p", "-t", name+":")
+	if err != nil {
+		return "", fmt.Errorf("failed to capture pane of session %q: %w", name, err)
+	}
+	return output, nil
+}
+
 // RenameSession renames a tmux session from oldName to newName.
 func (c *Client) RenameSession(oldName, newName string) error {
 	_, err := c.cmd.Run("rename-session", "-t", oldName, newName)
@@ -146,3 +227,172 @@ func (c *Client) SwitchClient(name string) error {
 	}
 	return nil
 }
+
+// PaneRef identifies a tmux window and pane by their server-unique IDs
+// (e.g. "@3" and "%7"), which stay valid regardless of base-index or renames.
+type PaneRef struct {
+	WindowID string
+	PaneID   string
+}
+
+// paneRefFormat is the tmux format string used to print a PaneRef.
+const paneRefFormat = "#{window_id}|#{pane_id}"
+
+// parsePaneRef parses output produced with paneRefFormat.
+func parsePaneRef(output string) (PaneRef, error) {
+	windowID, paneID, found := strings.Cut(strings.TrimSpace(output), "|")
+	if !found || windowID == "" || paneID == "" {
+		return PaneRef{}, fmt.Errorf("unexpected pane format: %q", output)
+	}
+	return PaneRef{WindowID: windowID, PaneID: paneID}, nil
+}
+
+// ActivePane returns the active window and pane of the given target,
+// which may be a session ("name:") or a window ID.
+func (c *Client) ActivePane(target string) (PaneRef, error) {
+	output, err := c.cmd.Run("display-message", "-p", "-t", target, paneRefFormat)
+	if err != nil {
+		return PaneRef{}, fmt.Errorf("failed to query active pane of %q: %w", target, err)
+	}
+	return parsePaneRef(output)
+}
+
+// NewWindow appends a window to the named session, starting in dir.
+// When windowName is non-empty the window is given that name.
+// Returns the IDs of the new window and its initial pane.
+func (c *Client) NewWindow(sessionName, windowName, dir string) (PaneRef, error) {
+	args := []string{"new-window", "-d", "-t", sessionName + ":", "-c", dir, "-P", "-F", paneRefFormat}
+	if windowName != "" {
+		args = append(args, "-n", windowName)
+	}
+	output, err := c.cmd.Run(args...)
+	if err != nil {
+		return PaneRef{}, fmt.Errorf("failed to create window in session %q: %w", sessionName, err)
+	}
+	return parsePaneRef(output)
+}
+
+// RenameWindow renames the target window.
+func (c *Client) RenameWindow(target, name string) error {
+	_, err := c.cmd.Run("rename-window", "-t", target, name)
+	if err != nil {
+		return fmt.Errorf("failed to rename window %q: %w", target, err)
+	}
+	return nil
+}
+
+// SplitWindow splits the target pane, starting the new pane in dir.
+// When horizontal is true the panes are placed side by side, otherwise stacked.
+// When size is non-empty it is passed to tmux -l (e.g. "30%" or "20").
+// Returns the ID of the new pane.
+func (c *Client) SplitWindow(target, dir string, horizontal bool, size string) (string, error) {
+	direction := "-v"
+	if horizontal {
+		direction = "-h"
+	}
+	args := []string{"split-window", "-d", direction, "-t", target, "-c", dir}
+	if size != "" {
+		args = append(args, "-l", size)
+	}
+	args = append(args, "-P", "-F", "#{pane_id}")
+	output, err := c.cmd.Run(args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to split pane %q: %w", target, err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// SendKeys types the given command into the target pane followed by Enter.
+func (c *Client) SendKeys(target, command string) error {
+	_, err := c.cmd.Run("send-keys", "-t", target, command, "Enter")
+	if err != nil {
+		return fmt.Errorf("failed to send keys to %q: %w", target, err)
+	}
+	return nil
+}
+
+// SelectLayout applies a tmux layout (e.g. "tiled", "main-vertical") to the target window.
+func (c *Client) SelectLayout(target, layout string) error {
+	_, err := c.cmd.Run("select-layout", "-t", target, layout)
+	if err != nil {
+		return fmt.Errorf("failed to select layout %q for %q: %w", layout, target, err)
+	}
+	return nil
+}
+
+// SelectPane makes the target pane the active pane of its window.
+func (c *Client) SelectPane(target string) error {
+	_, err := c.cmd.Run("select-pane", "-t", target)
+	if err != nil {
+		return fmt.Errorf("failed to select pane %q: %w", target, err)
+	}
+	return nil
+}
+
+// SelectWindow makes the target window the current window of its session.
+func (c *Client) SelectWindow(target string) error {
+	_, err := c.cmd.Run("select-window", "-t", target)
+	if err != nil {
+		return fmt.Errorf("failed to select window %q: %w", target, err)
+	}
+	return nil
+}
+
+// Pane describes one pane of a running session, as listed by ListPanes.
+type Pane struct {
+	Session      string
+	WindowIndex  int
+	WindowName   string
+	WindowLayout string
+	WindowActive bool
+	PaneActive   bool
+	// Path is the pane's current working directory.
+	Path string
+	// Command is the name of the pane's foreground process.
+	Command string
+}
+
+// paneFormat is the tmux format string used by ListPanes. Fields are
+// tab-separated because names and paths routinely contain "|"; the session
+// name comes last so names containing a tab still parse intact.
+const paneFormat = "#{window_index}\t#{window_active}\t#{pane_active}\t#{window_layout}\t#{pane_current_command}\t#{window_name}\t#{pane_current_path}\t#{session_name}"
+
+// paneFields is the number of fields in paneFormat.
+const paneFields = 8
+
+// ListPanes returns every pane of every session, grouped by session and
+// ordered by window and pane index. Returns an empty slice and nil error when
+// no tmux server is running.
+func (c *Client) ListPanes() ([]Pane, error) {
+	output, err := c.cmd.Run("list-panes", "-a", "-F", paneFormat)
+	if err != nil || output == "" {
+		return []Pane{}, nil
+	}
+
+	lines := strings.Split(output, "\n")
+	panes := make([]Pane, 0, len(lines))
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "\t", paneFields)
+		if len(parts) != paneFields {
+			return nil, fmt.Errorf("unexpected pane format: %q", line)
+		}
+		index, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid window index %q: %w", parts[0], err)
+		}
+		panes = append(panes, Pane{
+			Session:      parts[7],
+			WindowIndex:  index,
+			WindowActive: parts[1] == "1",
+			PaneActive:   parts[2] == "1",
+			WindowLayout: parts[3],
+			Command:      parts[4],
+			WindowName:   parts[5],
+			Path:         parts[6],
+		})
+	}
+	return panes, nil
+}
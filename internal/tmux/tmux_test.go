@@ -2,8 +2,10 @@ package tmux_test
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/leeovery/portal/internal/tmux"
 )
@@ -29,24 +31,24 @@ func (m *MockCommander) Run(args ...string) (string, error) {
 
 func TestListSessions(t *testing.T) {
 	tests := []struct {
-		name     string
-		output   string
-		err      error
-		want     []tmux.Session
-		wantErr  bool
+		name    string
+		output  string
+		err     error
+		want    []tmux.Session
+		wantErr bool
 	}{
 		{
 			name:   "parses multiple sessions correctly",
-			output: "dev|3|1\nwork|5|0\nmisc|1|0",
+			output: "3\t1\t\t\t\t\t\t\tdev\n5\t0\t\t\t\t\t\t\twork\n1\t0\t\t\t\t\t\t\tmisc",
 			want: []tmux.Session{
-				{Name: "dev", Windows: 3, Attached: true},
+				{Name: "dev", Windows: 3, Attached: true, Clients: 1},
 				{Name: "work", Windows: 5, Attached: false},
 				{Name: "misc", Windows: 1, Attached: false},
 			},
 		},
 		{
 			name:   "parses single session",
-			output: "main|2|0",
+			output: "2\t0\t\t\t\t\t\t\tmain",
 			want: []tmux.Session{
 				{Name: "main", Windows: 2, Attached: false},
 			},
@@ -64,25 +66,81 @@ func TestListSessions(t *testing.T) {
 		},
 		{
 			name:   "attached is true when session_attached > 0",
-			output: "session1|2|3",
+			output: "2\t3\t\t\t\t\t\t\tsession1",
 			want: []tmux.Session{
-				{Name: "session1", Windows: 2, Attached: true},
+				{Name: "session1", Windows: 2, Attached: true, Clients: 3},
 			},
 		},
 		{
 			name:   "attached is false when session_attached is 0",
-			output: "session1|2|0",
+			output: "2\t0\t\t\t\t\t\t\tsession1",
 			want: []tmux.Session{
 				{Name: "session1", Windows: 2, Attached: false},
 			},
 		},
 		{
 			name:   "handles session name with special characters",
-			output: "my-project.v2|4|1",
+			output: "4\t1\t\t\t\t\t\t\tmy-project.v2",
 			want: []tmux.Session{
-				{Name: "my-project.v2", Windows: 4, Attached: true},
+				{Name: "my-project.v2", Windows: 4, Attached: true, Clients: 1},
 			},
 		},
+		{
+			name:   "session name may contain the separator",
+			output: "1\t0\t\t\t\t\t\t\tbuild\ttest",
+			want: []tmux.Session{
+				{Name: "build\ttest", Windows: 1},
+			},
+		},
+		{
+			name:   "window name and path may contain a pipe",
+			output: "1\t0\t\t\t\t\ta|b\t/code/x|y\tapi",
+			want: []tmux.Session{
+				{Name: "api", Windows: 1, ActiveWindow: "a|b", Path: "/code/x|y"},
+			},
+		},
+		{
+			name:   "parses times, path, group and active window",
+			output: "2\t1\t1700000000\t1700000600\t1700000900\twork\tnvim\t/home/user/code\tapi-01",
+			want: []tmux.Session{
+				{
+					Name:         "api-01",
+					Windows:      2,
+					Attached:     true,
+					Clients:      1,
+					Created:      time.Unix(1700000000, 0),
+					LastAttached: time.Unix(1700000600, 0),
+					Activity:     time.Unix(1700000900, 0),
+					Group:        "work",
+					ActiveWindow: "nvim",
+					Path:         "/home/user/code",
+				},
+			},
+		},
+		{
+			name:   "never-attached session has zero last attached time",
+			output: "1\t0\t1700000000\t0\t1700000000\t\tzsh\t/tmp\tfresh",
+			want: []tmux.Session{
+				{
+					Name:         "fresh",
+					Windows:      1,
+					Created:      time.Unix(1700000000, 0),
+					Activity:     time.Unix(1700000000, 0),
+					ActiveWindow: "zsh",
+					Path:         "/tmp",
+				},
+			},
+		},
+		{
+			name:    "too few fields is an error",
+			output:  "dev|3|1",
+			wantErr: true,
+		},
+		{
+			name:    "invalid timestamp is an error",
+			output:  "1|0|yesterday|||||/tmp|dev",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -103,20 +161,8 @@ func TestListSessions(t *testing.T) {
 				t.Fatalf("unexpected error: %v", err)
 			}
 
-			if len(got) != len(tt.want) {
-				t.Fatalf("got %d sessions, want %d", len(got), len(tt.want))
-			}
-
-			for i, session := range got {
-				if session.Name != tt.want[i].Name {
-					t.Errorf("session[%d].Name = %q, want %q", i, session.Name, tt.want[i].Name)
-				}
-				if session.Windows != tt.want[i].Windows {
-					t.Errorf("session[%d].Windows = %d, want %d", i, session.Windows, tt.want[i].Windows)
-				}
-				if session.Attached != tt.want[i].Attached {
-					t.Errorf("session[%d].Attached = %v, want %v", i, session.Attached, tt.want[i].Attached)
-				}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListSessions() = %+v, want %+v", got, tt.want)
 			}
 		})
 	}
@@ -312,6 +358,71 @@ func TestKillSession(t *testing.T) {
 	})
 }
 
+func TestDetachSession(t *testing.T) {
+	t.Run("runs detach-client for every client of the session", func(t *testing.T) {
+		mock := &MockCommander{}
+		client := tmux.NewClient(mock)
+
+		err := client.DetachSession("my-session")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(mock.Calls) != 1 {
+			t.Fatalf("expected 1 call, got %d", len(mock.Calls))
+		}
+		wantArgs := "detach-client -s my-session"
+		gotArgs := strings.Join(mock.Calls[0], " ")
+		if gotArgs != wantArgs {
+			t.Errorf("called with %q, want %q", gotArgs, wantArgs)
+		}
+	})
+
+	t.Run("returns error when tmux command fails", func(t *testing.T) {
+		mock := &MockCommander{Err: fmt.Errorf("no such session")}
+		client := tmux.NewClient(mock)
+
+		err := client.DetachSession("nonexistent")
+
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestCapturePane(t *testing.T) {
+	t.Run("captures the active pane of the session", func(t *testing.T) {
+		mock := &MockCommander{Output: "$ make test\nok"}
+		client := tmux.NewClient(mock)
+
+		got, err := client.CapturePane("my-session")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "$ make test\nok" {
+			t.Errorf("CapturePane() = %q, want %q", got, "$ make test\nok")
+		}
+		wantArgs := "capture-pane -p -t my-session:"
+		gotArgs := strings.Join(mock.Calls[0], " ")
+		if gotArgs != wantArgs {
+			t.Errorf("called with %q, want %q", gotArgs, wantArgs)
+		}
+	})
+
+	t.Run("returns error when tmux command fails", func(t *testing.T) {
+		mock := &MockCommander{Err: fmt.Errorf("can't find session")}
+		client := tmux.NewClient(mock)
+
+		_, err := client.CapturePane("nonexistent")
+
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
 func TestSwitchClient(t *testing.T) {
 	t.Run("runs switch-client with session name", func(t *testing.T) {
 		mock := &MockCommander{}
@@ -377,3 +488,146 @@ func TestRenameSession(t *testing.T) {
 		}
 	})
 }
+
+func TestNewWindow(t *testing.T) {
+	t.Run("creates detached window and parses returned IDs", func(t *testing.T) {
+		mock := &MockCommander{Output: "@4|%9"}
+		client := tmux.NewClient(mock)
+
+		got, err := client.NewWindow("my-session", "server", "/home/user/project")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.WindowID != "@4" || got.PaneID != "%9" {
+			t.Errorf("NewWindow() = %+v, want {@4 %%9}", got)
+		}
+		wantArgs := "new-window -d -t my-session: -c /home/user/project -P -F #{window_id}|#{pane_id} -n server"
+		gotArgs := strings.Join(mock.Calls[0], " ")
+		if gotArgs != wantArgs {
+			t.Errorf("called with %q, want %q", gotArgs, wantArgs)
+		}
+	})
+
+	t.Run("returns error on unexpected output", func(t *testing.T) {
+		mock := &MockCommander{Output: "garbage"}
+		client := tmux.NewClient(mock)
+
+		_, err := client.NewWindow("my-session", "", "/tmp")
+
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestSplitWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		horizontal bool
+		size       string
+		wantArgs   string
+	}{
+		{
+			name:     "vertical split without size",
+			wantArgs: "split-window -d -v -t %1 -c /dir -P -F #{pane_id}",
+		},
+		{
+			name:       "horizontal split with percentage size",
+			horizontal: true,
+			size:       "30%",
+			wantArgs:   "split-window -d -h -t %1 -c /dir -l 30% -P -F #{pane_id}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &MockCommander{Output: "%2"}
+			client := tmux.NewClient(mock)
+
+			got, err := client.SplitWindow("%1", "/dir", tt.horizontal, tt.size)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != "%2" {
+				t.Errorf("SplitWindow() = %q, want %q", got, "%2")
+			}
+			gotArgs := strings.Join(mock.Calls[0], " ")
+			if gotArgs != tt.wantArgs {
+				t.Errorf("called with %q, want %q", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSendKeys(t *testing.T) {
+	t.Run("sends command followed by Enter as separate args", func(t *testing.T) {
+		mock := &MockCommander{}
+		client := tmux.NewClient(mock)
+
+		err := client.SendKeys("%3", "npm run dev")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		wantArgs := []string{"send-keys", "-t", "%3", "npm run dev", "Enter"}
+		if strings.Join(mock.Calls[0], "\x00") != strings.Join(wantArgs, "\x00") {
+			t.Errorf("called with %q, want %q", mock.Calls[0], wantArgs)
+		}
+	})
+
+	t.Run("returns error when tmux command fails", func(t *testing.T) {
+		mock := &MockCommander{Err: fmt.Errorf("no such pane")}
+		client := tmux.NewClient(mock)
+
+		if err := client.SendKeys("%99", "ls"); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestListPanes(t *testing.T) {
+	t.Run("parses panes of every session", func(t *testing.T) {
+		mock := &MockCommander{Output: "1\t1\t0\tabcd,80x24,0,0\tzsh\teditor\t/code/api\tapi\n" +
+			"1\t1\t1\tabcd,80x24,0,0\tnvim\teditor\t/code/api/src\tapi\n" +
+			"2\t0\t1\tef01,80x24,0,0\tmake\tserver | logs\t/code/api\tapi\n" +
+			"1\t1\t1\t1234,80x24,0,0\tzsh\tzsh\t/tmp\tmy\tsession"}
+		client := tmux.NewClient(mock)
+
+		got, err := client.ListPanes()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []tmux.Pane{
+			{Session: "api", WindowIndex: 1, WindowName: "editor", WindowLayout: "abcd,80x24,0,0", WindowActive: true, Path: "/code/api", Command: "zsh"},
+			{Session: "api", WindowIndex: 1, WindowName: "editor", WindowLayout: "abcd,80x24,0,0", WindowActive: true, PaneActive: true, Path: "/code/api/src", Command: "nvim"},
+			{Session: "api", WindowIndex: 2, WindowName: "server | logs", WindowLayout: "ef01,80x24,0,0", PaneActive: true, Path: "/code/api", Command: "make"},
+			{Session: "my\tsession", WindowIndex: 1, WindowName: "zsh", WindowLayout: "1234,80x24,0,0", WindowActive: true, PaneActive: true, Path: "/tmp", Command: "zsh"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ListPanes() = %+v, want %+v", got, want)
+		}
+		if gotArgs := strings.Join(mock.Calls[0][:3], " "); gotArgs != "list-panes -a -F" {
+			t.Errorf("called with %q", gotArgs)
+		}
+	})
+
+	t.Run("returns empty slice when tmux server is not running", func(t *testing.T) {
+		client := tmux.NewClient(&MockCommander{Err: fmt.Errorf("no server running")})
+
+		got, err := client.ListPanes()
+		if err != nil || len(got) != 0 {
+			t.Errorf("ListPanes() = %v, %v; want empty", got, err)
+		}
+	})
+
+	t.Run("returns error for malformed output", func(t *testing.T) {
+		client := tmux.NewClient(&MockCommander{Output: "garbage"})
+
+		if _, err := client.ListPanes(); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
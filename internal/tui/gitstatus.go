@@ -0,0 +1,57 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/leeovery/portal/internal/tmux"
+	"github.com/leeovery/portal/internal/ui"
+)
+
+// GitStatusSource looks up the git status of directories.
+type GitStatusSource = ui.GitStatusSource
+
+// WithGitStatus shows the git status of each project, and of each session
+// whose project directory is known. Statuses are looked up in the background
+// whenever sessions or projects load and on every refresh.
+func WithGitStatus(s GitStatusSource) Option {
+	return func(m *Model) {
+		m.gitStatus = s
+	}
+}
+
+// sessionDir returns the project directory of a session: the one it was
+// created from when the registry knows it, otherwise its working directory.
+func (m Model) sessionDir(s tmux.Session) string {
+	if entry, ok := m.sessionProjects[s.Name]; ok {
+		return entry.ProjectPath
+	}
+	return s.Path
+}
+
+// lookupGitStatus returns a command that looks up the git status of every
+// listed project, worktree and session directory.
+func (m Model) lookupGitStatus() tea.Cmd {
+	if m.gitStatus == nil {
+		return nil
+	}
+	projects, _ := m.nestedProjects()
+	dirs := make([]string, 0, len(projects)+len(m.sessions))
+	for _, p := range projects {
+		dirs = append(dirs, p.Path)
+	}
+	for _, s := range m.sessions {
+		if dir := m.sessionDir(s); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return ui.LookupGitStatus(m.gitStatus, dirs)
+}
+
+// gitStatusDetail renders the git status of dir for a row's detail text, or
+// "" when it is unknown.
+func (m Model) gitStatusDetail(dir string) string {
+	status, ok := m.gitStatuses[dir]
+	if !ok {
+		return ""
+	}
+	return "  " + m.styles.detail.Render(status.String())
+}
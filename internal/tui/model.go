@@ -2,22 +2,31 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/leeovery/portal/internal/config"
 	"github.com/leeovery/portal/internal/fuzzy"
+	"github.com/leeovery/portal/internal/gitstatus"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/registry"
 	"github.com/leeovery/portal/internal/tmux"
 	"github.com/leeovery/portal/internal/ui"
+	"github.com/leeovery/portal/internal/worktree"
 )
 
 // viewState tracks which view the TUI is currently displaying.
 type viewState int
 
 const (
-	viewSessionList  viewState = iota
+	viewSessionList viewState = iota
 	viewProjectPicker
 	viewFileBrowser
 )
@@ -40,6 +49,11 @@ type SessionCreator interface {
 	CreateFromDir(dir string, command []string) (string, error)
 }
 
+// SessionDetacher defines the interface for detaching clients from tmux sessions.
+type SessionDetacher interface {
+	DetachSession(name string) error
+}
+
 // SessionRenamer defines the interface for renaming tmux sessions.
 type SessionRenamer interface {
 	RenameSession(oldName, newName string) error
@@ -48,12 +62,32 @@ type SessionRenamer interface {
 // DirLister abstracts directory listing for testability.
 type DirLister = ui.DirLister
 
+// ProjectEditor abstracts project renaming for the edit form.
+type ProjectEditor = ui.ProjectEditor
+
+// AliasEditor abstracts alias management for the edit form.
+type AliasEditor = ui.AliasEditor
+
+// SessionRegistry looks up which project each session was created from.
+type SessionRegistry interface {
+	ByName() (map[string]registry.Entry, error)
+}
+
 // SessionsMsg carries the result of fetching tmux sessions.
+// Projects maps session names to their registry entries when a registry is configured.
 type SessionsMsg struct {
 	Sessions []tmux.Session
+	Projects map[string]registry.Entry
 	Err      error
 }
 
+// actionDoneMsg carries the refreshed session list after killing, renaming or
+// detaching sessions failed, with the failures to show in the status line.
+type actionDoneMsg struct {
+	refreshed SessionsMsg
+	err       error
+}
+
 // SessionCreatedMsg is emitted when a session has been successfully created.
 type SessionCreatedMsg struct {
 	SessionName string
@@ -64,16 +98,47 @@ type sessionCreateErrMsg struct {
 	Err error
 }
 
-// Model is the Bubble Tea model for the session list TUI.
+// itemKind identifies the section a row of the unified list belongs to.
+type itemKind int
+
+const (
+	itemSession itemKind = iota
+	itemProject
+	itemBrowse
+)
+
+// listItem is a single navigable row of the unified list.
+type listItem struct {
+	kind    itemKind
+	session tmux.Session
+	project project.Project
+}
+
+// Model is the Bubble Tea model for the unified session and project list.
 type Model struct {
 	sessions        []tmux.Session
+	projects        []project.Project
 	cursor          int
 	selected        string
 	loaded          bool
 	sessionLister   SessionLister
 	sessionKiller   SessionKiller
 	sessionRenamer  SessionRenamer
+	sessionDetacher SessionDetacher
+	sessionRegistry SessionRegistry
+	sessionProjects map[string]registry.Entry
+	paneCapturer    PaneCapturer
+	sessionRestorer SessionRestorer
+	killedSaved     map[string]bool
+	refreshInterval time.Duration
 	projectStore    ProjectStore
+	projectScanner  ProjectScanner
+	gitStatus       GitStatusSource
+	gitStatuses     map[string]gitstatus.Status
+	worktreeManager WorktreeManager
+	worktrees       map[string][]worktree.Worktree
+	projectEditor   ProjectEditor
+	aliasEditor     AliasEditor
 	sessionCreator  SessionCreator
 	dirLister       DirLister
 	startPath       string
@@ -84,7 +149,15 @@ type Model struct {
 	insideTmux      bool
 	currentSession  string
 	confirmKill     bool
-	pendingKillName string
+	pendingKill     []string
+	marked          map[string]bool
+	cursorAnchor    string
+	status          string
+	confirmRemove   bool
+	pendingRemove   project.Project
+	pendingWorktree bool
+	editMode        bool
+	projectEdit     ui.ProjectEditModel
 	renameMode      bool
 	renameInput     textinput.Model
 	renameTarget    string
@@ -92,6 +165,14 @@ type Model struct {
 	filterText      string
 	command         []string
 	commandPending  bool
+	showHidden      bool
+	width           int
+	height          int
+	previewHidden   bool
+	previewName     string
+	previewText     string
+	previewErr      error
+	styles          styles
 }
 
 // Selected returns the name of the session chosen by the user, or empty if
@@ -124,7 +205,16 @@ func (m Model) WithCommand(command []string) Model {
 		m.commandPending = true
 		m.view = viewProjectPicker
 		if m.projectStore != nil {
-			m.projectPicker = ui.NewProjectPicker(m.projectStore)
+			m.projectPicker = ui.NewProjectPicker(m.projectStore).WithMatchStyle(m.styles.match)
+			if m.projectEditor != nil && m.aliasEditor != nil {
+				m.projectPicker = m.projectPicker.WithEditor(m.projectEditor, m.aliasEditor)
+			}
+			if m.gitStatus != nil {
+				m.projectPicker = m.projectPicker.WithGitStatus(m.gitStatus)
+			}
+			if m.worktreeManager != nil {
+				m.projectPicker = m.projectPicker.WithWorktrees(m.worktreeManager)
+			}
 		}
 	}
 	return m
@@ -157,6 +247,20 @@ func WithRenamer(r SessionRenamer) Option {
 	}
 }
 
+// WithDetacher sets the session detacher dependency used by bulk detach.
+func WithDetacher(d SessionDetacher) Option {
+	return func(m *Model) {
+		m.sessionDetacher = d
+	}
+}
+
+// WithSessionRegistry sets the registry used to show each session's project.
+func WithSessionRegistry(r SessionRegistry) Option {
+	return func(m *Model) {
+		m.sessionRegistry = r
+	}
+}
+
 // WithProjectStore sets the project store dependency.
 func WithProjectStore(s ProjectStore) Option {
 	return func(m *Model) {
@@ -164,6 +268,28 @@ func WithProjectStore(s ProjectStore) Option {
 	}
 }
 
+// WithColors sets the colours used to render the list.
+func WithColors(c config.Colors) Option {
+	return func(m *Model) {
+		m.styles = newStyles(c)
+	}
+}
+
+// WithShowHidden makes the file browser start with hidden directories shown.
+func WithShowHidden(show bool) Option {
+	return func(m *Model) {
+		m.showHidden = show
+	}
+}
+
+// WithProjectEditor enables editing a project's name and aliases from the list.
+func WithProjectEditor(editor ProjectEditor, aliases AliasEditor) Option {
+	return func(m *Model) {
+		m.projectEditor = editor
+		m.aliasEditor = aliases
+	}
+}
+
 // WithSessionCreator sets the session creator dependency.
 func WithSessionCreator(c SessionCreator) Option {
 	return func(m *Model) {
@@ -172,6 +298,7 @@ func WithSessionCreator(c SessionCreator) Option {
 }
 
 // WithDirLister sets the directory lister and starting path for the file browser.
+// The starting path is also where n creates a new session.
 func WithDirLister(d DirLister, startPath string) Option {
 	return func(m *Model) {
 		m.dirLister = d
@@ -184,6 +311,7 @@ func WithDirLister(d DirLister, startPath string) Option {
 func New(lister SessionLister, opts ...Option) Model {
 	m := Model{
 		sessionLister: lister,
+		styles:        newStyles(config.Default().TUI.Colors),
 	}
 	for _, opt := range opts {
 		opt(&m)
@@ -197,6 +325,7 @@ func NewModelWithSessions(sessions []tmux.Session) Model {
 		sessions: sessions,
 		cursor:   0,
 		loaded:   true,
+		styles:   newStyles(config.Default().TUI.Colors),
 	}
 }
 
@@ -214,21 +343,85 @@ func (m Model) filteredSessions() []tmux.Session {
 	return filtered
 }
 
-// totalItems returns the count of navigable items in the session list view.
-// This is the number of sessions plus 1 for the "new in project" option.
-func (m Model) totalItems() int {
-	return len(m.sessions) + 1
+// items returns the navigable rows of the unified list: visible sessions,
+// then visible projects, then the browse option.
+func (m Model) items() []listItem {
+	sessions := m.displaySessions()
+	projects := m.displayProjects()
+	items := make([]listItem, 0, len(sessions)+len(projects)+1)
+	for _, s := range sessions {
+		items = append(items, listItem{kind: itemSession, session: s})
+	}
+	for _, p := range projects {
+		items = append(items, listItem{kind: itemProject, project: p})
+	}
+	return append(items, listItem{kind: itemBrowse})
+}
+
+// currentItem returns the row under the cursor.
+func (m Model) currentItem() listItem {
+	items := m.items()
+	if m.cursor < len(items) {
+		return items[m.cursor]
+	}
+	return items[len(items)-1]
 }
 
-// Init returns a command that fetches tmux sessions, or loads projects
-// when in command-pending mode.
+// clampCursor keeps the cursor within the current rows.
+func (m *Model) clampCursor() {
+	if last := len(m.items()) - 1; m.cursor > last {
+		m.cursor = last
+	}
+}
+
+// Init returns a command that fetches tmux sessions and remembered projects,
+// and schedules the first background refresh when one is configured. In
+// command-pending mode it loads projects into the picker instead.
 func (m Model) Init() tea.Cmd {
+	var scan tea.Cmd
+	if m.projectScanner != nil {
+		scan = m.scanProjects()
+	}
 	if m.commandPending && m.projectStore != nil {
-		return m.projectPicker.Init()
+		return tea.Batch(m.projectPicker.Init(), scan)
+	}
+	cmds := []tea.Cmd{func() tea.Msg {
+		return m.fetchSessions()
+	}}
+	if m.projectStore != nil {
+		cmds = append(cmds, m.loadProjects())
 	}
+	if m.refreshInterval > 0 {
+		cmds = append(cmds, m.scheduleRefresh())
+	}
+	cmds = append(cmds, scan)
+	return tea.Batch(cmds...)
+}
+
+// fetchSessions lists tmux sessions, adds restorable ones from the snapshot
+// and, when a registry is configured, looks up their projects. Registry
+// errors only hide project names.
+func (m Model) fetchSessions() SessionsMsg {
+	sessions, err := m.sessionLister.ListSessions()
+	msg := SessionsMsg{Sessions: sessions, Err: err}
+	if err == nil && m.sessionRestorer != nil {
+		msg.Sessions = m.withSaved(sessions)
+	}
+	if err == nil && m.sessionRegistry != nil {
+		if projects, regErr := m.sessionRegistry.ByName(); regErr == nil {
+			msg.Projects = projects
+		}
+	}
+	return msg
+}
+
+// loadProjects cleans stale projects and loads the remaining ones from the store.
+func (m Model) loadProjects() tea.Cmd {
+	store := m.projectStore
 	return func() tea.Msg {
-		sessions, err := m.sessionLister.ListSessions()
-		return SessionsMsg{Sessions: sessions, Err: err}
+		_, _ = store.CleanStale()
+		projects, err := store.List()
+		return ui.ProjectsLoadedMsg{Projects: projects, Err: err}
 	}
 }
 
@@ -236,6 +429,29 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle cross-view messages regardless of view state
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case paneCapturedMsg:
+		return m.applyCapture(msg), nil
+	case projectsScannedMsg:
+		return m, m.applyScan(msg)
+	case ui.GitStatusMsg:
+		m.gitStatuses = ui.MergeGitStatus(m.gitStatuses, msg)
+		return m.updateProjectPicker(msg)
+	case ui.WorktreesLoadedMsg:
+		m.worktrees = msg.Worktrees
+		m.clampCursor()
+		updated, cmd := m.updateProjectPicker(msg)
+		return updated, tea.Batch(cmd, m.lookupGitStatus())
+	case refreshTickMsg:
+		return m, m.refreshSessions()
+	case sessionsRefreshedMsg:
+		refreshed, cmd := m.applyRefresh(msg)
+		if m.view != viewSessionList {
+			return refreshed, cmd
+		}
+		return refreshed.syncPreview(cmd)
 	case ui.BackMsg:
 		if m.commandPending {
 			return m, tea.Quit
@@ -245,13 +461,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ui.ProjectSelectedMsg:
 		return m, m.createSession(msg.Path)
 	case ui.BrowseSelectedMsg:
-		m.fileBrowser = ui.NewFileBrowser(m.startPath, m.dirLister)
-		m.view = viewFileBrowser
-		return m, nil
+		return m.openFileBrowser()
 	case ui.BrowserDirSelectedMsg:
 		return m, m.createSession(msg.Path)
 	case ui.BrowserCancelMsg:
-		m.view = viewProjectPicker
+		if m.commandPending {
+			m.view = viewProjectPicker
+		} else {
+			m.view = viewSessionList
+		}
 		return m, nil
 	case SessionCreatedMsg:
 		m.selected = msg.SessionName
@@ -269,7 +487,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case viewFileBrowser:
 		return m.updateFileBrowser(msg)
 	default:
-		return m.updateSessionList(msg)
+		updated, cmd := m.updateSessionList(msg)
+		if list, ok := updated.(Model); ok {
+			return list.syncPreview(cmd)
+		}
+		return updated, cmd
 	}
 }
 
@@ -283,6 +505,16 @@ func (m Model) createSession(dir string) tea.Cmd {
 	}
 }
 
+// openFileBrowser switches to the file browser rooted at the start path.
+func (m Model) openFileBrowser() (tea.Model, tea.Cmd) {
+	if m.dirLister == nil {
+		return m, nil
+	}
+	m.fileBrowser = ui.NewFileBrowser(m.startPath, m.dirLister).WithShowHidden(m.showHidden).WithMatchStyle(m.styles.match)
+	m.view = viewFileBrowser
+	return m, nil
+}
+
 func (m Model) updateProjectPicker(msg tea.Msg) (tea.Model, tea.Cmd) {
 	updated, cmd := m.projectPicker.Update(msg)
 	picker, ok := updated.(ui.ProjectPickerModel)
@@ -301,112 +533,179 @@ func (m Model) updateFileBrowser(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) updateSessionList(msg tea.Msg) (tea.Model, tea.Cmd) {
-	// Handle confirmKill state first
-	if m.confirmKill {
-		return m.updateConfirmKill(msg)
-	}
-
-	// Handle rename mode
-	if m.renameMode {
-		return m.updateRename(msg)
-	}
-
-	// Handle filter mode
-	if m.filterMode {
-		return m.updateFilter(msg)
-	}
-
 	switch msg := msg.(type) {
 	case SessionsMsg:
 		if msg.Err != nil {
 			return m, tea.Quit
 		}
+		m.confirmKill = false
+		m.pendingKill = nil
+		onSession := m.cursor < len(m.sessions)
 		m.sessions = msg.Sessions
+		m.sessionProjects = msg.Projects
 		m.sessions = m.filteredSessions()
-		if m.cursor >= len(m.sessions) && len(m.sessions) > 0 {
+		m.pruneMarks()
+		// A cursor on a session that is gone stays among the sessions.
+		if onSession && m.cursor >= len(m.sessions) && len(m.sessions) > 0 {
 			m.cursor = len(m.sessions) - 1
-		} else if len(m.sessions) == 0 {
-			m.cursor = 0
 		}
+		m.clampCursor()
+		m.restoreCursorAnchor()
 		m.loaded = true
 		if m.initialFilter != "" {
 			m.filterMode = true
 			m.filterText = m.initialFilter
 			m.initialFilter = ""
 		}
+		return m, m.lookupGitStatus()
 
-	case ui.ProjectsLoadedMsg:
-		// Forward to project picker if we're transitioning
-		updated, cmd := m.projectPicker.Update(msg)
-		if picker, ok := updated.(ui.ProjectPickerModel); ok {
-			m.projectPicker = picker
+	case actionDoneMsg:
+		if msg.err != nil {
+			m.status = msg.err.Error()
 		}
-		m.view = viewProjectPicker
-		return m, cmd
+		return m.updateSessionList(msg.refreshed)
 
-	case tea.KeyMsg:
-		switch {
-		case msg.Type == tea.KeyCtrlC || msg.Type == tea.KeyEsc:
-			return m, tea.Quit
-		case msg.Type == tea.KeyRunes && string(msg.Runes) == "q":
-			return m, tea.Quit
-		case msg.Type == tea.KeyRunes && string(msg.Runes) == "K":
-			return m.handleKillKey()
-		case msg.Type == tea.KeyRunes && string(msg.Runes) == "R":
-			return m.handleRenameKey()
-		case msg.Type == tea.KeyRunes && string(msg.Runes) == "/":
-			m.filterMode = true
-			m.filterText = ""
-			m.cursor = 0
+	case ui.ProjectsLoadedMsg:
+		if msg.Err != nil {
 			return m, nil
-		case msg.Type == tea.KeyRunes && string(msg.Runes) == "n":
-			// Jump to the "new in project" option
-			m.cursor = len(m.sessions)
-		case msg.Type == tea.KeyDown || (msg.Type == tea.KeyRunes && string(msg.Runes) == "j"):
-			if m.cursor < m.totalItems()-1 {
-				m.cursor++
-			}
-		case msg.Type == tea.KeyUp || (msg.Type == tea.KeyRunes && string(msg.Runes) == "k"):
-			if m.cursor > 0 {
-				m.cursor--
-			}
-		case msg.Type == tea.KeyEnter:
-			return m.handleSessionListEnter()
 		}
+		m.projects = msg.Projects
+		m.clampCursor()
+		return m, tea.Batch(m.lookupGitStatus(), m.lookupWorktrees())
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	m.status = ""
+
+	switch {
+	case m.confirmKill:
+		return m.updateConfirmKill(keyMsg)
+	case m.confirmRemove:
+		return m.updateConfirmRemove(keyMsg)
+	case m.editMode:
+		return m.updateEditMode(keyMsg)
+	case m.renameMode:
+		return m.updateRename(keyMsg)
+	case m.filterMode:
+		return m.updateFilter(keyMsg)
+	}
+
+	switch {
+	case keyMsg.Type == tea.KeyEsc && len(m.marked) > 0:
+		m.marked = nil
+		return m, nil
+	case keyMsg.Type == tea.KeyCtrlC || keyMsg.Type == tea.KeyEsc:
+		return m, tea.Quit
+	case keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "q":
+		return m, tea.Quit
+	case keyMsg.Type == tea.KeySpace:
+		m.toggleMark()
+		return m, nil
+	case keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "K":
+		return m.handleKillKey()
+	case keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "D":
+		return m.handleDetachKey()
+	case keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "R":
+		return m.handleRenameKey()
+	case keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "e":
+		return m.handleEditKey()
+	case keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "x":
+		return m.handleRemoveKey()
+	case keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "/":
+		m.filterMode = true
+		m.filterText = ""
+		m.cursor = 0
+		return m, nil
+	case keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "n":
+		return m.handleNewInCwd()
+	case keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "b":
+		return m.openFileBrowser()
+	case keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "p":
+		return m.togglePreview(), nil
+	case keyMsg.Type == tea.KeyDown || (keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "j"):
+		if m.cursor < len(m.items())-1 {
+			m.cursor++
+		}
+	case keyMsg.Type == tea.KeyUp || (keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "k"):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case keyMsg.Type == tea.KeyEnter:
+		return m.handleEnter()
 	}
 	return m, nil
 }
 
-func (m Model) handleKillKey() (tea.Model, tea.Cmd) {
-	// No-op if cursor is on the [n] new in project option
-	if m.cursor >= len(m.sessions) {
+// handleNewInCwd creates a session in the directory Portal was started from.
+func (m Model) handleNewInCwd() (tea.Model, tea.Cmd) {
+	if m.sessionCreator == nil || m.startPath == "" {
 		return m, nil
 	}
+	return m, m.createSession(m.startPath)
+}
+
+// handleKillKey asks to kill the marked sessions, or the session under the
+// cursor when none are marked.
+func (m Model) handleKillKey() (tea.Model, tea.Cmd) {
 	// No-op if no session killer configured
 	if m.sessionKiller == nil {
 		return m, nil
 	}
-	m.confirmKill = true
-	m.pendingKillName = m.sessions[m.cursor].Name
-	return m, nil
+	if marked := m.markedSessions(); len(marked) > 0 {
+		return m.confirmKillOf(marked), nil
+	}
+	// No-op unless the cursor is on a running session
+	session, ok := m.currentLiveSession()
+	if !ok {
+		return m, nil
+	}
+	return m.confirmKillOf([]string{session.Name}), nil
 }
 
-func (m Model) updateConfirmKill(msg tea.Msg) (tea.Model, tea.Cmd) {
-	keyMsg, ok := msg.(tea.KeyMsg)
-	if !ok {
+// handleKillFiltered asks to kill every session matching the active filter.
+func (m Model) handleKillFiltered() (tea.Model, tea.Cmd) {
+	var names []string
+	for _, s := range m.filterMatchedSessions() {
+		if !s.Exited {
+			names = append(names, s.Name)
+		}
+	}
+	if m.sessionKiller == nil || len(names) == 0 {
 		return m, nil
 	}
+	m.filterMode = false
+	m.filterText = ""
+	m.cursor = 0
+	return m.confirmKillOf(names), nil
+}
+
+// confirmKillOf switches to the kill confirmation prompt for names.
+func (m Model) confirmKillOf(names []string) Model {
+	m.confirmKill = true
+	m.pendingKill = names
+	return m
+}
 
+func (m Model) updateConfirmKill(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "y":
-		name := m.pendingKillName
+		names := m.pendingKill
 		m.confirmKill = false
-		m.pendingKillName = ""
-		return m, m.killAndRefresh(name)
+		m.pendingKill = nil
+		m.forgetSaved(names)
+		if len(names) == 1 {
+			return m, m.killAndRefresh(names[0])
+		}
+		m.cursorAnchor = m.survivingNeighbour(names)
+		m.marked = nil
+		return m, m.bulkAndRefresh(names, m.sessionKiller.KillSession, "kill")
 	case keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "n",
 		keyMsg.Type == tea.KeyEsc:
 		m.confirmKill = false
-		m.pendingKillName = ""
+		m.pendingKill = nil
 		return m, nil
 	}
 	// Ignore all other keys in confirmation mode
@@ -414,18 +713,198 @@ func (m Model) updateConfirmKill(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) killAndRefresh(name string) tea.Cmd {
+	return m.bulkAndRefresh([]string{name}, m.sessionKiller.KillSession, "kill")
+}
+
+// handleDetachKey detaches clients from the marked sessions, or from the
+// session under the cursor when none are marked. Sessions without clients
+// are skipped.
+func (m Model) handleDetachKey() (tea.Model, tea.Cmd) {
+	if m.sessionDetacher == nil {
+		return m, nil
+	}
+	targets := m.markedSessions()
+	if len(targets) == 0 {
+		session, ok := m.currentLiveSession()
+		if !ok {
+			return m, nil
+		}
+		targets = []string{session.Name}
+	}
+
+	var attached []string
+	for _, s := range m.sessions {
+		if s.Attached && slices.Contains(targets, s.Name) {
+			attached = append(attached, s.Name)
+		}
+	}
+	m.marked = nil
+	if len(attached) == 0 {
+		return m, nil
+	}
+	return m, m.bulkAndRefresh(attached, m.sessionDetacher.DetachSession, "detach")
+}
+
+// bulkAndRefresh applies op to every named session, then refreshes the list
+// once. A failure does not stop the remaining sessions; all failures are
+// shown together in the status line rather than closing the list.
+func (m Model) bulkAndRefresh(names []string, op func(string) error, verb string) tea.Cmd {
 	return func() tea.Msg {
-		if err := m.sessionKiller.KillSession(name); err != nil {
-			return SessionsMsg{Err: fmt.Errorf("failed to kill session '%s': %w", name, err)}
+		var errs []error
+		for _, name := range names {
+			if err := op(name); err != nil {
+				errs = append(errs, fmt.Errorf("failed to %s session '%s': %w", verb, name, err))
+			}
+		}
+		if len(errs) > 0 {
+			return actionDoneMsg{refreshed: m.fetchSessions(), err: errors.Join(errs...)}
 		}
-		sessions, err := m.sessionLister.ListSessions()
-		return SessionsMsg{Sessions: sessions, Err: err}
+		return m.fetchSessions()
 	}
 }
 
+// toggleMark marks or unmarks the running session under the cursor.
+func (m *Model) toggleMark() {
+	session, ok := m.currentLiveSession()
+	if !ok {
+		return
+	}
+	if m.marked[session.Name] {
+		delete(m.marked, session.Name)
+		return
+	}
+	if m.marked == nil {
+		m.marked = make(map[string]bool)
+	}
+	m.marked[session.Name] = true
+}
+
+// markedSessions returns the names of marked sessions in list order.
+func (m Model) markedSessions() []string {
+	var names []string
+	for _, s := range m.sessions {
+		if m.marked[s.Name] {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+// pruneMarks drops marks of sessions that no longer exist.
+func (m *Model) pruneMarks() {
+	for name := range m.marked {
+		if !slices.ContainsFunc(m.sessions, func(s tmux.Session) bool { return s.Name == name }) {
+			delete(m.marked, name)
+		}
+	}
+}
+
+// survivingNeighbour returns the session the cursor should land on once
+// removed are gone: the session under the cursor if it survives, otherwise
+// the next surviving session below it, otherwise the nearest one above.
+func (m Model) survivingNeighbour(removed []string) string {
+	sessions := m.displaySessions()
+	start := min(m.cursor, len(sessions)-1)
+	for i := max(start, 0); i < len(sessions); i++ {
+		if !slices.Contains(removed, sessions[i].Name) {
+			return sessions[i].Name
+		}
+	}
+	for i := start - 1; i >= 0; i-- {
+		if !slices.Contains(removed, sessions[i].Name) {
+			return sessions[i].Name
+		}
+	}
+	return ""
+}
+
+// restoreCursorAnchor moves the cursor to the anchored session after a
+// refresh, if it is still listed.
+func (m *Model) restoreCursorAnchor() {
+	anchor := m.cursorAnchor
+	m.cursorAnchor = ""
+	if anchor == "" {
+		return
+	}
+	for i, s := range m.displaySessions() {
+		if s.Name == anchor {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+func (m Model) handleRemoveKey() (tea.Model, tea.Cmd) {
+	item := m.currentItem()
+	// No-op unless the cursor is on a project
+	if item.kind != itemProject || m.projectStore == nil {
+		return m, nil
+	}
+	m.confirmRemove = true
+	m.pendingRemove = item.project
+	m.pendingWorktree = m.worktreeManager != nil && m.worktreeManager.IsWorktree(item.project.Path)
+	return m, nil
+}
+
+func (m Model) updateConfirmRemove(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case keyMsg.Type == tea.KeyRunes && (string(keyMsg.Runes) == "y" || (string(keyMsg.Runes) == "w" && m.pendingWorktree)):
+		path := m.pendingRemove.Path
+		deleteWorktree := string(keyMsg.Runes) == "w"
+		m.confirmRemove = false
+		m.pendingRemove = project.Project{}
+		m.pendingWorktree = false
+		// Keep the project listed when its worktree could not be deleted.
+		if deleteWorktree {
+			if err := m.worktreeManager.Remove(path); err != nil {
+				m.status = fmt.Sprintf("failed to delete worktree: %v", err)
+				return m, nil
+			}
+		}
+		if err := m.projectStore.Remove(path); err != nil {
+			m.status = fmt.Sprintf("failed to remove project: %v", err)
+		}
+		return m, m.loadProjects()
+	case keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "n",
+		keyMsg.Type == tea.KeyEsc:
+		m.confirmRemove = false
+		m.pendingRemove = project.Project{}
+		m.pendingWorktree = false
+		return m, nil
+	}
+	// Ignore all other keys in confirmation mode
+	return m, nil
+}
+
+func (m Model) handleEditKey() (tea.Model, tea.Cmd) {
+	item := m.currentItem()
+	// No-op unless the cursor is on a project and editing is configured
+	if item.kind != itemProject || m.projectEditor == nil || m.aliasEditor == nil {
+		return m, nil
+	}
+	m.editMode = true
+	m.projectEdit = ui.NewProjectEdit(item.project, m.projectEditor, m.aliasEditor)
+	return m, nil
+}
+
+func (m Model) updateEditMode(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var outcome ui.EditOutcome
+	m.projectEdit, outcome = m.projectEdit.Update(keyMsg)
+
+	switch outcome {
+	case ui.EditCancelled:
+		m.editMode = false
+	case ui.EditSaved:
+		m.editMode = false
+		return m, m.loadProjects()
+	}
+	return m, nil
+}
+
 func (m Model) handleRenameKey() (tea.Model, tea.Cmd) {
-	// No-op if cursor is on the [n] new in project option
-	if m.cursor >= len(m.sessions) {
+	// No-op unless the cursor is on a running session
+	session, ok := m.currentLiveSession()
+	if !ok {
 		return m, nil
 	}
 	// No-op if no session renamer configured
@@ -433,7 +912,7 @@ func (m Model) handleRenameKey() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 	m.renameMode = true
-	m.renameTarget = m.sessions[m.cursor].Name
+	m.renameTarget = session.Name
 	ti := textinput.New()
 	ti.Prompt = "Rename: "
 	ti.SetValue(m.renameTarget)
@@ -442,48 +921,39 @@ func (m Model) handleRenameKey() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m Model) updateRename(msg tea.Msg) (tea.Model, tea.Cmd) {
-	keyMsg, ok := msg.(tea.KeyMsg)
-	if ok {
-		switch keyMsg.Type {
-		case tea.KeyEnter:
-			newName := strings.TrimSpace(m.renameInput.Value())
-			if newName == "" {
-				return m, nil
-			}
-			oldName := m.renameTarget
-			m.renameMode = false
-			m.renameTarget = ""
-			return m, m.renameAndRefresh(oldName, newName)
-		case tea.KeyEsc:
-			m.renameMode = false
-			m.renameTarget = ""
+func (m Model) updateRename(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch keyMsg.Type {
+	case tea.KeyEnter:
+		newName := strings.TrimSpace(m.renameInput.Value())
+		if newName == "" {
 			return m, nil
 		}
+		oldName := m.renameTarget
+		m.renameMode = false
+		m.renameTarget = ""
+		return m, m.renameAndRefresh(oldName, newName)
+	case tea.KeyEsc:
+		m.renameMode = false
+		m.renameTarget = ""
+		return m, nil
 	}
 
-	// Delegate to textinput for all other messages
+	// Delegate to textinput for all other keys
 	var cmd tea.Cmd
-	m.renameInput, cmd = m.renameInput.Update(msg)
+	m.renameInput, cmd = m.renameInput.Update(keyMsg)
 	return m, cmd
 }
 
 func (m Model) renameAndRefresh(oldName, newName string) tea.Cmd {
 	return func() tea.Msg {
 		if err := m.sessionRenamer.RenameSession(oldName, newName); err != nil {
-			return SessionsMsg{Err: fmt.Errorf("failed to rename session '%s': %w", oldName, err)}
+			return actionDoneMsg{refreshed: m.fetchSessions(), err: fmt.Errorf("failed to rename session '%s': %w", oldName, err)}
 		}
-		sessions, err := m.sessionLister.ListSessions()
-		return SessionsMsg{Sessions: sessions, Err: err}
+		return m.fetchSessions()
 	}
 }
 
-func (m Model) updateFilter(msg tea.Msg) (tea.Model, tea.Cmd) {
-	keyMsg, ok := msg.(tea.KeyMsg)
-	if !ok {
-		return m, nil
-	}
-
+func (m Model) updateFilter(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch keyMsg.Type {
 	case tea.KeyEsc:
 		m.filterMode = false
@@ -501,25 +971,17 @@ func (m Model) updateFilter(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case tea.KeyEnter:
-		matched := m.filterMatchedSessions()
-		if m.cursor < len(matched) {
-			m.selected = matched[m.cursor].Name
-			return m, tea.Quit
-		}
-		// Cursor on the [n] new in project option
-		if m.cursor == len(matched) {
-			if m.projectStore != nil {
-				m.filterMode = false
-				m.filterText = ""
-				m.projectPicker = ui.NewProjectPicker(m.projectStore)
-				return m, m.projectPicker.Init()
-			}
+		return m.handleEnter()
+	case tea.KeyTab:
+		m.toggleMark()
+		if m.cursor < len(m.items())-1 {
+			m.cursor++
 		}
 		return m, nil
+	case tea.KeyCtrlK:
+		return m.handleKillFiltered()
 	case tea.KeyDown:
-		matched := m.filterMatchedSessions()
-		// +1 for the [n] new in project option
-		if m.cursor < len(matched) {
+		if m.cursor < len(m.items())-1 {
 			m.cursor++
 		}
 		return m, nil
@@ -532,42 +994,92 @@ func (m Model) updateFilter(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.filterText += string(keyMsg.Runes)
 		m.cursor = 0
 		return m, nil
+	case tea.KeySpace:
+		// Space separates filter terms.
+		m.filterText += " "
+		m.cursor = 0
+		return m, nil
 	}
 
 	return m, nil
 }
 
-// filterMatchedSessions returns sessions whose names fuzzy-match the current filter text.
-// Uses subsequence matching: each character in the filter must appear in order in the session name.
+// filterMatchedSessions returns sessions whose names fuzzy-match the current filter text,
+// best match first. Each character in the filter must appear in order in the session name.
+// A #tag term matches the tags of the session's project.
 func (m Model) filterMatchedSessions() []tmux.Session {
-	return fuzzy.Filter(m.sessions, m.filterText, func(s tmux.Session) string { return s.Name })
+	return fuzzy.FilterTagged(m.sessions, m.filterText,
+		func(s tmux.Session) string { return s.Name },
+		m.sessionTags)
 }
 
-func (m Model) handleSessionListEnter() (tea.Model, tea.Cmd) {
-	// Cursor on a session
-	if m.cursor < len(m.sessions) {
-		m.selected = m.sessions[m.cursor].Name
-		return m, tea.Quit
+// filterMatchedProjects returns projects, including listed worktrees, whose names
+// fuzzy-match the current filter text, best match first. A #tag term matches the
+// projects' tags.
+func (m Model) filterMatchedProjects() []project.Project {
+	projects, _ := m.nestedProjects()
+	return fuzzy.FilterTagged(projects, m.filterText,
+		func(p project.Project) string { return p.Name },
+		func(p project.Project) []string { return p.Tags })
+}
+
+// sessionTags returns the tags of the project a session belongs to: the one
+// it was created from when the registry knows it, otherwise the project at
+// the session's working directory.
+func (m Model) sessionTags(s tmux.Session) []string {
+	path := m.sessionDir(s)
+	for _, p := range m.projects {
+		if p.Path == path {
+			return p.Tags
+		}
 	}
+	return nil
+}
 
-	// Cursor on the "new in project" option
-	if m.cursor == len(m.sessions) {
-		if m.projectStore != nil {
-			m.projectPicker = ui.NewProjectPicker(m.projectStore)
-			return m, m.projectPicker.Init()
+// handleEnter acts on the row under the cursor: attach to a session, restore
+// a saved one, create a session in a project, or open the file browser.
+func (m Model) handleEnter() (tea.Model, tea.Cmd) {
+	item := m.currentItem()
+	switch item.kind {
+	case itemSession:
+		if item.session.Exited && m.sessionRestorer != nil {
+			return m, m.restoreSession(item.session.Name)
 		}
+		m.selected = item.session.Name
+		return m, tea.Quit
+	case itemProject:
+		if m.sessionCreator == nil {
+			return m, nil
+		}
+		return m, m.createSession(item.project.Path)
+	default:
+		return m.openFileBrowser()
 	}
+}
 
-	return m, nil
+// styles holds the lipgloss styles used to render the list.
+type styles struct {
+	cursor   lipgloss.Style
+	name     lipgloss.Style
+	detail   lipgloss.Style
+	attached lipgloss.Style
+	header   lipgloss.Style
+	hint     lipgloss.Style
+	match    lipgloss.Style
 }
 
-var (
-	cursorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("212"))
-	nameStyle     = lipgloss.NewStyle().Bold(true)
-	detailStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-	attachedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("76"))
-	dividerStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-)
+// newStyles builds the list styles from configured colours.
+func newStyles(c config.Colors) styles {
+	return styles{
+		cursor:   lipgloss.NewStyle().Foreground(lipgloss.Color(c.Cursor)),
+		name:     lipgloss.NewStyle().Bold(true),
+		detail:   lipgloss.NewStyle().Foreground(lipgloss.Color(c.Detail)),
+		attached: lipgloss.NewStyle().Foreground(lipgloss.Color(c.Attached)),
+		header:   lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(c.Header)),
+		hint:     lipgloss.NewStyle().Foreground(lipgloss.Color(c.Hint)),
+		match:    ui.NewMatchStyle(c.Match),
+	}
+}
 
 // View renders the current view.
 func (m Model) View() string {
@@ -584,6 +1096,9 @@ func (m Model) View() string {
 	case viewFileBrowser:
 		return m.fileBrowser.View()
 	default:
+		if m.editMode {
+			return m.projectEdit.View()
+		}
 		return m.viewSessionList()
 	}
 }
@@ -596,7 +1111,25 @@ func (m Model) displaySessions() []tmux.Session {
 	return m.sessions
 }
 
-// viewSessionList renders the session list with the "new in project" option.
+// displayProjects returns the projects to display, with linked worktrees
+// under their repository, applying filter when in filter mode.
+func (m Model) displayProjects() []project.Project {
+	if m.filterMode {
+		return m.filterMatchedProjects()
+	}
+	projects, _ := m.nestedProjects()
+	return projects
+}
+
+// rowCursor returns the cursor indicator for the row at index i.
+func (m Model) rowCursor(i int) string {
+	if i == m.cursor {
+		return m.styles.cursor.Render("> ")
+	}
+	return "  "
+}
+
+// viewSessionList renders the unified list: sessions, projects and the browse option.
 func (m Model) viewSessionList() string {
 	var b strings.Builder
 
@@ -605,63 +1138,167 @@ func (m Model) viewSessionList() string {
 		b.WriteString("\n\n")
 	}
 
-	visible := m.displaySessions()
+	sessions := m.displaySessions()
+	projects := m.displayProjects()
 
-	if m.loaded && len(visible) == 0 && !m.filterMode {
+	b.WriteString(m.styles.header.Render("Sessions"))
+	if n := len(m.markedSessions()); n > 0 {
+		b.WriteString(m.styles.detail.Render(fmt.Sprintf("  %d selected", n)))
+	}
+	b.WriteString("\n")
+
+	if m.loaded && len(sessions) == 0 && !m.filterMode {
 		if m.insideTmux {
-			b.WriteString("No other sessions")
+			b.WriteString("  No other sessions\n")
 		} else {
-			b.WriteString("No active sessions")
+			b.WriteString("  No active sessions\n")
 		}
-	} else {
-		for i, s := range visible {
-			cursor := "  "
-			if i == m.cursor {
-				cursor = cursorStyle.Render("> ")
-			}
+	}
 
-			windowLabel := fmt.Sprintf("%d windows", s.Windows)
-			if s.Windows == 1 {
-				windowLabel = "1 window"
-			}
+	for i, s := range sessions {
+		windowLabel := fmt.Sprintf("%d windows", s.Windows)
+		if s.Windows == 1 {
+			windowLabel = "1 window"
+		}
 
-			detail := detailStyle.Render(windowLabel)
+		detail := m.styles.detail.Render(windowLabel)
 
-			if s.Attached {
-				detail += "  " + attachedStyle.Render("● attached")
-			}
+		if entry, ok := m.sessionProjects[s.Name]; ok {
+			detail += "  " + m.styles.detail.Render(entry.ProjectName)
+		}
+		if dir := m.sessionDir(s); dir != "" {
+			detail += m.gitStatusDetail(dir)
+		}
 
-			line := fmt.Sprintf("%s%s  %s", cursor, nameStyle.Render(s.Name), detail)
-			b.WriteString(line)
-			b.WriteString("\n")
+		if !s.Activity.IsZero() {
+			detail += "  " + m.styles.detail.Render("active "+ui.RelativeTime(s.Activity, time.Now()))
 		}
+
+		if s.Exited {
+			detail += "  " + m.styles.detail.Render("○ exited")
+		} else if s.Clients > 1 {
+			detail += "  " + m.styles.attached.Render(fmt.Sprintf("● attached (%d)", s.Clients))
+		} else if s.Attached {
+			detail += "  " + m.styles.attached.Render("● attached")
+		}
+
+		fmt.Fprintf(&b, "%s%s%s  %s\n", m.rowCursor(i), m.markColumn(s.Name), m.highlightName(s.Name), detail)
 	}
 
-	// Divider and new option
 	b.WriteString("\n")
-	b.WriteString(dividerStyle.Render("  ─────────────────────────────"))
+	b.WriteString(m.styles.header.Render("Projects"))
 	b.WriteString("\n")
 
-	newCursor := "  "
-	if m.cursor == len(visible) {
-		newCursor = cursorStyle.Render("> ")
+	if len(m.projects) == 0 && !m.filterMode {
+		b.WriteString("  No saved projects yet\n")
 	}
-	fmt.Fprintf(&b, "%s[n] new in project...", newCursor)
 
-	if m.confirmKill {
-		b.WriteString("\n\n")
-		fmt.Fprintf(&b, "Kill session '%s'? (y/n)", m.pendingKillName)
+	_, children := m.nestedProjects()
+	for i, p := range projects {
+		cursor := m.rowCursor(len(sessions) + i)
+		if children[p.Path] && !m.filterMode {
+			cursor += "└ "
+		}
+		detail := displayPath(p.Path)
+		if len(p.Tags) > 0 {
+			detail += "  " + project.FormatTags(p.Tags)
+		}
+		fmt.Fprintf(&b, "%s%s  %s%s\n", cursor, m.highlightName(p.Name), m.styles.detail.Render(detail), m.gitStatusDetail(p.Path))
 	}
 
-	if m.renameMode {
+	fmt.Fprintf(&b, "%s[b] browse for directory...", m.rowCursor(len(sessions)+len(projects)))
+
+	list := b.String()
+	b.Reset()
+
+	switch {
+	case m.confirmKill && len(m.pendingKill) == 1:
+		b.WriteString("\n\n")
+		fmt.Fprintf(&b, "Kill session '%s'? (y/n)", m.pendingKill[0])
+	case m.confirmKill:
+		b.WriteString("\n\n")
+		fmt.Fprintf(&b, "Kill %d sessions: %s? (y/n)", len(m.pendingKill), strings.Join(m.pendingKill, ", "))
+	case m.confirmRemove && m.pendingWorktree:
+		b.WriteString("\n\n")
+		fmt.Fprintf(&b, "Remove project '%s'? (y/n, w to also delete the worktree)", m.pendingRemove.Name)
+	case m.confirmRemove:
+		b.WriteString("\n\n")
+		fmt.Fprintf(&b, "Remove project '%s'? (y/n)", m.pendingRemove.Name)
+	case m.renameMode:
 		b.WriteString("\n\n")
 		b.WriteString(m.renameInput.View())
-	}
-
-	if m.filterMode {
+	case m.filterMode:
 		b.WriteString("\n\n")
 		fmt.Fprintf(&b, "filter: %s", m.filterText)
+		if m.filterText != "" && m.sessionKiller != nil {
+			b.WriteString(m.styles.hint.Render("  [tab] select  [ctrl+k] kill all matching"))
+		}
+	case m.status != "":
+		b.WriteString("\n\n")
+		b.WriteString(m.status)
+	default:
+		b.WriteString("\n\n")
+		b.WriteString(m.styles.hint.Render(m.hints()))
+	}
+
+	footer := b.String()
+	if m.previewVisible() {
+		list = m.withPreview(list, max(lipgloss.Height(list), m.height-lipgloss.Height(footer)))
 	}
+	return list + footer
+}
+
+// highlightName renders a session or project name, highlighting the
+// characters that match the filter while filtering.
+func (m Model) highlightName(name string) string {
+	if !m.filterMode {
+		return m.styles.name.Render(name)
+	}
+	return ui.HighlightMatch(name, m.filterText, m.styles.name, m.styles.match)
+}
+
+// markColumn returns the selection marker for a session row. The column is
+// only shown while at least one session is marked.
+func (m Model) markColumn(name string) string {
+	switch {
+	case len(m.marked) == 0:
+		return ""
+	case m.marked[name]:
+		return m.styles.attached.Render("✓ ")
+	default:
+		return "  "
+	}
+}
 
-	return b.String()
+// hints returns the keybinding hints for the row under the cursor.
+func (m Model) hints() string {
+	if n := len(m.markedSessions()); n > 0 {
+		return fmt.Sprintf("[space] toggle  [K] kill %d  [D] detach %d  [esc] clear selection", n, n)
+	}
+	switch m.currentItem().kind {
+	case itemSession:
+		if m.paneCapturer != nil {
+			return "[enter] attach  [space] select  [R] rename  [K] kill  [D] detach  [p] preview  [n] new here  [/] filter  [q] quit"
+		}
+		return "[enter] attach  [space] select  [R] rename  [K] kill  [D] detach  [n] new here  [/] filter  [q] quit"
+	case itemProject:
+		return "[enter] new session  [e] edit  [x] remove  [n] new here  [/] filter  [q] quit"
+	default:
+		return "[enter] browse  [n] new here  [/] filter  [q] quit"
+	}
+}
+
+// displayPath abbreviates the user's home directory to ~ for display.
+func displayPath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if rest, ok := strings.CutPrefix(path, home+string(os.PathSeparator)); ok {
+		return "~/" + rest
+	}
+	return path
 }
@@ -3,14 +3,19 @@ package tui_test
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/leeovery/portal/internal/browser"
+	"github.com/leeovery/portal/internal/gitstatus"
 	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/registry"
 	"github.com/leeovery/portal/internal/tmux"
 	"github.com/leeovery/portal/internal/tui"
 	"github.com/leeovery/portal/internal/ui"
+	"github.com/leeovery/portal/internal/worktree"
 )
 
 func TestView(t *testing.T) {
@@ -327,7 +332,7 @@ func TestKeyboardNavigation(t *testing.T) {
 			keys: []tea.Msg{
 				tea.KeyMsg{Type: tea.KeyDown},
 			},
-			wantCursorLine: 1,
+			wantCursorLine: 2,
 		},
 		{
 			name:     "j key moves cursor down",
@@ -335,7 +340,7 @@ func TestKeyboardNavigation(t *testing.T) {
 			keys: []tea.Msg{
 				tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}},
 			},
-			wantCursorLine: 1,
+			wantCursorLine: 2,
 		},
 		{
 			name:     "up arrow moves cursor up",
@@ -344,7 +349,7 @@ func TestKeyboardNavigation(t *testing.T) {
 				tea.KeyMsg{Type: tea.KeyDown},
 				tea.KeyMsg{Type: tea.KeyUp},
 			},
-			wantCursorLine: 0,
+			wantCursorLine: 1,
 		},
 		{
 			name:     "k key moves cursor up",
@@ -353,19 +358,19 @@ func TestKeyboardNavigation(t *testing.T) {
 				tea.KeyMsg{Type: tea.KeyDown},
 				tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}},
 			},
-			wantCursorLine: 0,
+			wantCursorLine: 1,
 		},
 		{
-			name:     "cursor does not go below last item including new option",
+			name:     "cursor does not go below browse option",
 			sessions: threeSessions,
 			keys: []tea.Msg{
 				tea.KeyMsg{Type: tea.KeyDown},
 				tea.KeyMsg{Type: tea.KeyDown},
-				tea.KeyMsg{Type: tea.KeyDown}, // lands on [n] new in project...
+				tea.KeyMsg{Type: tea.KeyDown}, // lands on browse option
 				tea.KeyMsg{Type: tea.KeyDown}, // should not go further
 				tea.KeyMsg{Type: tea.KeyDown}, // should not go further
 			},
-			wantCursorLine: 5, // 3 sessions + blank + divider + new option line
+			wantCursorLine: 7, // header + 3 sessions + blank + header + empty projects + browse
 		},
 		{
 			name:     "cursor does not go above first item",
@@ -374,7 +379,7 @@ func TestKeyboardNavigation(t *testing.T) {
 				tea.KeyMsg{Type: tea.KeyUp},
 				tea.KeyMsg{Type: tea.KeyUp},
 			},
-			wantCursorLine: 0,
+			wantCursorLine: 1,
 		},
 		{
 			name: "navigation is no-op with single session",
@@ -387,7 +392,7 @@ func TestKeyboardNavigation(t *testing.T) {
 				tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}},
 				tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}},
 			},
-			wantCursorLine: 0,
+			wantCursorLine: 1,
 		},
 	}
 
@@ -412,20 +417,20 @@ func TestKeyboardNavigation(t *testing.T) {
 		view := m.View()
 
 		lines := strings.Split(view, "\n")
-		if len(lines) < 3 {
-			t.Fatalf("expected 3 lines, got %d", len(lines))
+		if len(lines) < 4 {
+			t.Fatalf("expected at least 4 lines, got %d", len(lines))
 		}
 		// alpha should NOT have cursor
-		if strings.Contains(lines[0], ">") {
-			t.Errorf("alpha line should not have cursor: %q", lines[0])
+		if strings.Contains(lines[1], ">") {
+			t.Errorf("alpha line should not have cursor: %q", lines[1])
 		}
 		// bravo SHOULD have cursor
-		if !strings.Contains(lines[1], ">") {
-			t.Errorf("bravo line should have cursor: %q", lines[1])
+		if !strings.Contains(lines[2], ">") {
+			t.Errorf("bravo line should have cursor: %q", lines[2])
 		}
 		// charlie should NOT have cursor
-		if strings.Contains(lines[2], ">") {
-			t.Errorf("charlie line should not have cursor: %q", lines[2])
+		if strings.Contains(lines[3], ">") {
+			t.Errorf("charlie line should not have cursor: %q", lines[3])
 		}
 	})
 }
@@ -618,316 +623,326 @@ func TestEnterSelection(t *testing.T) {
 	})
 }
 
-func TestNewInProjectOption(t *testing.T) {
-	t.Run("session list includes new in project option", func(t *testing.T) {
-		m := tui.NewModelWithSessions([]tmux.Session{
-			{Name: "dev", Windows: 3, Attached: true},
-			{Name: "work", Windows: 1, Attached: false},
-		})
-		view := m.View()
+// newUnifiedModel returns a model with sessions and projects loaded.
+func newUnifiedModel(sessions []tmux.Session, store *mockProjectStore, opts ...tui.Option) tea.Model {
+	opts = append([]tui.Option{tui.WithProjectStore(store)}, opts...)
+	var model tea.Model = tui.New(&mockSessionLister{sessions: sessions}, opts...)
+	model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
+	model, _ = model.Update(ui.ProjectsLoadedMsg{Projects: store.projects})
+	return model
+}
 
-		if !strings.Contains(view, "[n] new in project...") {
-			t.Errorf("view missing '[n] new in project...' option:\n%s", view)
-		}
-	})
+func TestUnifiedList(t *testing.T) {
+	sessions := []tmux.Session{
+		{Name: "dev", Windows: 1, Attached: false},
+	}
+	projects := []project.Project{
+		{Path: "/code/myapp", Name: "myapp"},
+		{Path: "/code/other", Name: "other"},
+	}
 
-	t.Run("new option appears below sessions with divider", func(t *testing.T) {
-		m := tui.NewModelWithSessions([]tmux.Session{
-			{Name: "dev", Windows: 3, Attached: false},
-			{Name: "work", Windows: 1, Attached: false},
-		})
-		view := m.View()
+	t.Run("sessions and projects render in labelled sections", func(t *testing.T) {
+		model := newUnifiedModel(sessions, &mockProjectStore{projects: projects})
+		view := model.View()
 
-		lastSessionIdx := strings.Index(view, "work")
-		dividerIdx := strings.Index(view, "─")
-		newOptionIdx := strings.Index(view, "[n] new in project...")
+		sessionsIdx := strings.Index(view, "Sessions")
+		devIdx := strings.Index(view, "dev")
+		projectsIdx := strings.Index(view, "Projects")
+		myappIdx := strings.Index(view, "myapp")
+		browseIdx := strings.Index(view, "[b] browse for directory...")
 
-		if lastSessionIdx == -1 || dividerIdx == -1 || newOptionIdx == -1 {
+		if sessionsIdx == -1 || devIdx == -1 || projectsIdx == -1 || myappIdx == -1 || browseIdx == -1 {
 			t.Fatalf("missing elements in view:\n%s", view)
 		}
-		if dividerIdx <= lastSessionIdx {
-			t.Errorf("divider (idx %d) should appear after last session (idx %d)", dividerIdx, lastSessionIdx)
+		if !(sessionsIdx < devIdx && devIdx < projectsIdx && projectsIdx < myappIdx && myappIdx < browseIdx) {
+			t.Errorf("sections out of order:\n%s", view)
 		}
-		if newOptionIdx <= dividerIdx {
-			t.Errorf("new option (idx %d) should appear after divider (idx %d)", newOptionIdx, dividerIdx)
+		if !strings.Contains(view, "/code/myapp") {
+			t.Errorf("expected project path in view:\n%s", view)
 		}
 	})
 
-	t.Run("n key jumps to new option", func(t *testing.T) {
-		sessions := []tmux.Session{
-			{Name: "alpha", Windows: 1, Attached: false},
-			{Name: "bravo", Windows: 2, Attached: false},
-			{Name: "charlie", Windows: 3, Attached: false},
+	t.Run("Init loads sessions and projects", func(t *testing.T) {
+		store := &mockProjectStore{projects: projects}
+		m := tui.New(&mockSessionLister{sessions: sessions}, tui.WithProjectStore(store))
+
+		cmd := m.Init()
+		if cmd == nil {
+			t.Fatal("expected init command, got nil")
+		}
+		batch, ok := cmd().(tea.BatchMsg)
+		if !ok {
+			t.Fatalf("expected tea.BatchMsg, got %T", cmd())
 		}
-		var m tea.Model = tui.NewModelWithSessions(sessions)
-		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
 
-		view := m.View()
-		// The [n] new in project... line should have the cursor
-		lines := strings.Split(view, "\n")
-		var newOptionLine string
-		for _, line := range lines {
-			if strings.Contains(line, "new in project") {
-				newOptionLine = line
-				break
-			}
+		var model tea.Model = m
+		for _, c := range batch {
+			model, _ = model.Update(c())
 		}
-		if !strings.Contains(newOptionLine, ">") {
-			t.Errorf("n key should move cursor to new option line: %q", newOptionLine)
+		view := model.View()
+		if !strings.Contains(view, "dev") || !strings.Contains(view, "myapp") {
+			t.Errorf("expected sessions and projects after init:\n%s", view)
 		}
 	})
 
-	t.Run("enter on new option switches to project picker", func(t *testing.T) {
-		sessions := []tmux.Session{
-			{Name: "dev", Windows: 1, Attached: false},
-		}
-		store := &mockProjectStore{
-			projects: []project.Project{
-				{Path: "/code/myapp", Name: "myapp"},
-			},
+	t.Run("empty projects shows empty state", func(t *testing.T) {
+		model := newUnifiedModel(sessions, &mockProjectStore{})
+		view := model.View()
+		if !strings.Contains(view, "No saved projects yet") {
+			t.Errorf("expected empty projects message:\n%s", view)
 		}
-		creator := &mockSessionCreator{}
+	})
 
-		m := tui.New(
-			&mockSessionLister{sessions: sessions},
-			tui.WithProjectStore(store),
-			tui.WithSessionCreator(creator),
-		)
-		// Load sessions
-		var model tea.Model = m
-		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
+	t.Run("cursor moves from sessions into projects", func(t *testing.T) {
+		model := newUnifiedModel(sessions, &mockProjectStore{projects: projects})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
 
-		// Press n to jump to new option, then Enter
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		for _, line := range strings.Split(model.View(), "\n") {
+			if strings.Contains(line, "myapp") && !strings.Contains(line, ">") {
+				t.Errorf("myapp line should have cursor: %q", line)
+			}
+			if strings.Contains(line, "dev") && strings.Contains(line, ">") {
+				t.Errorf("dev line should not have cursor: %q", line)
+			}
+		}
+	})
 
-		// Load projects into the picker
-		model, _ = model.Update(ui.ProjectsLoadedMsg{
-			Projects: store.projects,
-		})
+	t.Run("enter on project creates a session in its directory", func(t *testing.T) {
+		creator := &mockSessionCreator{sessionName: "myapp-abc123"}
+		model := newUnifiedModel(sessions, &mockProjectStore{projects: projects}, tui.WithSessionCreator(creator))
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
 
-		view := model.View()
-		if !strings.Contains(view, "Select a project") {
-			t.Errorf("expected project picker view, got:\n%s", view)
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if cmd == nil {
+			t.Fatal("expected command from enter on project, got nil")
+		}
+		createdMsg, ok := cmd().(tui.SessionCreatedMsg)
+		if !ok {
+			t.Fatalf("expected SessionCreatedMsg, got %T", cmd())
+		}
+		if createdMsg.SessionName != "myapp-abc123" {
+			t.Errorf("session name = %q, want %q", createdMsg.SessionName, "myapp-abc123")
+		}
+		if creator.createdDir != "/code/myapp" {
+			t.Errorf("CreateFromDir dir = %q, want %q", creator.createdDir, "/code/myapp")
+		}
+		if creator.createdCommand != nil {
+			t.Errorf("expected nil command, got %v", creator.createdCommand)
 		}
 	})
 
-	t.Run("esc in project picker returns to session list", func(t *testing.T) {
-		sessions := []tmux.Session{
-			{Name: "dev", Windows: 1, Attached: false},
+	t.Run("session created message selects session and quits", func(t *testing.T) {
+		model := newUnifiedModel(sessions, &mockProjectStore{projects: projects})
+		model, cmd := model.Update(tui.SessionCreatedMsg{SessionName: "myapp-abc123"})
+		if cmd == nil {
+			t.Fatal("expected quit command, got nil")
 		}
-		store := &mockProjectStore{
-			projects: []project.Project{
-				{Path: "/code/myapp", Name: "myapp"},
-			},
+		if _, ok := cmd().(tea.QuitMsg); !ok {
+			t.Errorf("expected tea.QuitMsg, got %T", cmd())
 		}
-		creator := &mockSessionCreator{}
+		if got := model.(tui.Model).Selected(); got != "myapp-abc123" {
+			t.Errorf("Selected() = %q, want %q", got, "myapp-abc123")
+		}
+	})
 
-		m := tui.New(
-			&mockSessionLister{sessions: sessions},
-			tui.WithProjectStore(store),
+	t.Run("n creates a session in the start directory", func(t *testing.T) {
+		creator := &mockSessionCreator{sessionName: "here-abc123"}
+		model := newUnifiedModel(sessions, &mockProjectStore{projects: projects},
 			tui.WithSessionCreator(creator),
+			tui.WithDirLister(&mockDirLister{}, "/home/user/here"),
 		)
-		var model tea.Model = m
-		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
 
-		// Navigate to project picker
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
-		model, _ = model.Update(ui.ProjectsLoadedMsg{Projects: store.projects})
-
-		// Esc should return to session list
-		model, _ = model.Update(ui.BackMsg{})
-
-		view := model.View()
-		if !strings.Contains(view, "dev") {
-			t.Errorf("expected session list with 'dev', got:\n%s", view)
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+		if cmd == nil {
+			t.Fatal("expected command from n, got nil")
 		}
-		if strings.Contains(view, "Select a project") {
-			t.Errorf("should not show project picker after Esc:\n%s", view)
+		if _, ok := cmd().(tui.SessionCreatedMsg); !ok {
+			t.Fatalf("expected SessionCreatedMsg, got %T", cmd())
+		}
+		if creator.createdDir != "/home/user/here" {
+			t.Errorf("CreateFromDir dir = %q, want %q", creator.createdDir, "/home/user/here")
 		}
 	})
 
-	t.Run("project selection triggers creation and returns session name", func(t *testing.T) {
-		sessions := []tmux.Session{
-			{Name: "dev", Windows: 1, Attached: false},
+	t.Run("n without session creator is no-op", func(t *testing.T) {
+		model := newUnifiedModel(sessions, &mockProjectStore{projects: projects})
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+		if cmd != nil {
+			t.Errorf("expected nil command, got %v", cmd)
 		}
-		store := &mockProjectStore{
-			projects: []project.Project{
-				{Path: "/code/myapp", Name: "myapp"},
+	})
+
+	t.Run("b opens the file browser", func(t *testing.T) {
+		dirLister := &mockDirLister{
+			entries: map[string][]browser.DirEntry{
+				"/home/user": {{Name: "code"}},
 			},
 		}
-		creator := &mockSessionCreator{
-			sessionName: "myapp-abc123",
+		model := newUnifiedModel(sessions, &mockProjectStore{projects: projects},
+			tui.WithDirLister(dirLister, "/home/user"),
+		)
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+
+		view := model.View()
+		if !strings.Contains(view, "/home/user") || strings.Contains(view, "[b] browse for directory...") {
+			t.Errorf("expected file browser view, got:\n%s", view)
 		}
+	})
 
-		m := tui.New(
-			&mockSessionLister{sessions: sessions},
-			tui.WithProjectStore(store),
-			tui.WithSessionCreator(creator),
+	t.Run("enter on browse option opens the file browser", func(t *testing.T) {
+		model := newUnifiedModel(sessions, &mockProjectStore{projects: projects},
+			tui.WithDirLister(&mockDirLister{}, "/home/user"),
 		)
-		var model tea.Model = m
-		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
-
-		// Navigate to project picker
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+		for range 3 {
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		}
 		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
-		model, _ = model.Update(ui.ProjectsLoadedMsg{Projects: store.projects})
 
-		// Select a project
-		_, cmd := model.Update(ui.ProjectSelectedMsg{Path: "/code/myapp"})
-		if cmd == nil {
-			t.Fatal("expected command from project selection, got nil")
+		view := model.View()
+		if !strings.Contains(view, "/home/user") || strings.Contains(view, "[b] browse for directory...") {
+			t.Errorf("expected file browser view, got:\n%s", view)
 		}
+	})
 
-		// The command should trigger session creation
-		msg := cmd()
-		createdMsg, ok := msg.(tui.SessionCreatedMsg)
-		if !ok {
-			t.Fatalf("expected SessionCreatedMsg, got %T", msg)
+	t.Run("filter narrows sessions and projects together", func(t *testing.T) {
+		model := newUnifiedModel([]tmux.Session{
+			{Name: "myapp-dev", Windows: 1},
+			{Name: "scratch", Windows: 1},
+		}, &mockProjectStore{projects: projects})
+
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+		for _, r := range "myapp" {
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
 		}
-		if createdMsg.SessionName != "myapp-abc123" {
-			t.Errorf("expected session name %q, got %q", "myapp-abc123", createdMsg.SessionName)
+
+		view := model.View()
+		if !strings.Contains(view, "myapp-dev") || !strings.Contains(view, "/code/myapp") {
+			t.Errorf("expected matching session and project, got:\n%s", view)
 		}
-		if creator.createdDir != "/code/myapp" {
-			t.Errorf("expected CreateFromDir called with %q, got %q", "/code/myapp", creator.createdDir)
+		if strings.Contains(view, "scratch") || strings.Contains(view, "/code/other") {
+			t.Errorf("non-matching rows should be hidden, got:\n%s", view)
 		}
 	})
 
-	t.Run("project selection forwards command to session creator", func(t *testing.T) {
-		sessions := []tmux.Session{
-			{Name: "dev", Windows: 1, Attached: false},
+	t.Run("enter in filter mode acts on a matching project", func(t *testing.T) {
+		creator := &mockSessionCreator{sessionName: "other-abc123"}
+		model := newUnifiedModel(sessions, &mockProjectStore{projects: projects}, tui.WithSessionCreator(creator))
+
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+		for _, r := range "oth" {
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
 		}
-		store := &mockProjectStore{
-			projects: []project.Project{
-				{Path: "/code/myapp", Name: "myapp"},
-			},
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if cmd == nil {
+			t.Fatal("expected command from enter, got nil")
 		}
-		creator := &mockSessionCreator{
-			sessionName: "myapp-abc123",
+		cmd()
+		if creator.createdDir != "/code/other" {
+			t.Errorf("CreateFromDir dir = %q, want %q", creator.createdDir, "/code/other")
 		}
+	})
 
-		m := tui.New(
-			&mockSessionLister{sessions: sessions},
-			tui.WithProjectStore(store),
-			tui.WithSessionCreator(creator),
-		).WithCommand([]string{"claude", "--resume"})
-		var model tea.Model = m
-		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
-
-		// Navigate to project picker
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
-		model, _ = model.Update(ui.ProjectsLoadedMsg{Projects: store.projects})
+	t.Run("hint bar follows the row under the cursor", func(t *testing.T) {
+		model := newUnifiedModel(sessions, &mockProjectStore{projects: projects})
+		if view := model.View(); !strings.Contains(view, "[K] kill") {
+			t.Errorf("expected session hints, got:\n%s", view)
+		}
 
-		// Select a project
-		_, cmd := model.Update(ui.ProjectSelectedMsg{Path: "/code/myapp"})
-		if cmd == nil {
-			t.Fatal("expected command from project selection, got nil")
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		view := model.View()
+		if !strings.Contains(view, "[e] edit") || strings.Contains(view, "[K] kill") {
+			t.Errorf("expected project hints, got:\n%s", view)
 		}
+	})
 
-		// Execute the command to trigger session creation
-		cmd()
+	t.Run("x removes project after confirmation", func(t *testing.T) {
+		store := &mockProjectStore{projects: projects}
+		model := newUnifiedModel(sessions, store)
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
 
-		// Verify command was forwarded to session creator
-		wantCmd := []string{"claude", "--resume"}
-		if len(creator.createdCommand) != len(wantCmd) {
-			t.Fatalf("command = %v, want %v", creator.createdCommand, wantCmd)
-		}
-		for i, arg := range creator.createdCommand {
-			if arg != wantCmd[i] {
-				t.Errorf("command[%d] = %q, want %q", i, arg, wantCmd[i])
-			}
+		if view := model.View(); !strings.Contains(view, "Remove project 'myapp'? (y/n)") {
+			t.Fatalf("expected remove confirmation, got:\n%s", view)
 		}
-	})
 
-	t.Run("no command set passes nil to session creator", func(t *testing.T) {
-		sessions := []tmux.Session{
-			{Name: "dev", Windows: 1, Attached: false},
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+		if store.removedPath != "/code/myapp" {
+			t.Errorf("Remove path = %q, want %q", store.removedPath, "/code/myapp")
 		}
-		store := &mockProjectStore{
-			projects: []project.Project{
-				{Path: "/code/myapp", Name: "myapp"},
-			},
+		if cmd == nil {
+			t.Fatal("expected refresh command after removal, got nil")
 		}
-		creator := &mockSessionCreator{
-			sessionName: "myapp-abc123",
+		if _, ok := cmd().(ui.ProjectsLoadedMsg); !ok {
+			t.Errorf("expected ui.ProjectsLoadedMsg, got %T", cmd())
 		}
+	})
 
-		m := tui.New(
-			&mockSessionLister{sessions: sessions},
-			tui.WithProjectStore(store),
-			tui.WithSessionCreator(creator),
-		)
-		var model tea.Model = m
-		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
-
-		// Navigate to project picker
+	t.Run("n cancels project removal", func(t *testing.T) {
+		store := &mockProjectStore{projects: projects}
+		model := newUnifiedModel(sessions, store)
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
 		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
-		model, _ = model.Update(ui.ProjectsLoadedMsg{Projects: store.projects})
 
-		// Select a project
-		_, cmd := model.Update(ui.ProjectSelectedMsg{Path: "/code/myapp"})
-		if cmd == nil {
-			t.Fatal("expected command from project selection, got nil")
+		if view := model.View(); strings.Contains(view, "Remove project") {
+			t.Errorf("confirmation should be cleared, got:\n%s", view)
 		}
+		if store.removedPath != "" {
+			t.Errorf("Remove should not be called, got %q", store.removedPath)
+		}
+	})
 
-		// Execute the command to trigger session creation
-		cmd()
-
-		// Verify nil command was passed (no command set on model)
-		if creator.createdCommand != nil {
-			t.Errorf("expected nil command, got %v", creator.createdCommand)
+	t.Run("x on a session is no-op", func(t *testing.T) {
+		model := newUnifiedModel(sessions, &mockProjectStore{projects: projects})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+		if view := model.View(); strings.Contains(view, "Remove project") {
+			t.Errorf("x on a session should be no-op, got:\n%s", view)
 		}
 	})
 
-	t.Run("empty session list still shows new option", func(t *testing.T) {
-		m := tui.NewModelWithSessions([]tmux.Session{})
-		view := m.View()
+	t.Run("e edits the project under the cursor", func(t *testing.T) {
+		editor := &mockProjectEditor{}
+		aliases := &mockAliasEditor{aliases: map[string]string{}}
+		model := newUnifiedModel(sessions, &mockProjectStore{projects: projects},
+			tui.WithProjectEditor(editor, aliases),
+		)
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
 
-		if !strings.Contains(view, "[n] new in project...") {
-			t.Errorf("empty session list should show new option:\n%s", view)
-		}
-		if !strings.Contains(view, "No active sessions") {
-			t.Errorf("empty session list should show no sessions message:\n%s", view)
+		if view := model.View(); !strings.Contains(view, "Edit project: myapp") {
+			t.Fatalf("expected edit view, got:\n%s", view)
 		}
-	})
 
-	t.Run("combined empty state navigable", func(t *testing.T) {
-		store := &mockProjectStore{
-			projects: []project.Project{},
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if editor.renamedPath != "/code/myapp" || editor.renamedName != "myap" {
+			t.Errorf("Rename(%q, %q), want (%q, %q)", editor.renamedPath, editor.renamedName, "/code/myapp", "myap")
 		}
-		creator := &mockSessionCreator{}
+		if cmd == nil {
+			t.Fatal("expected refresh command after save, got nil")
+		}
+	})
 
-		m := tui.New(
-			&mockSessionLister{sessions: []tmux.Session{}},
-			tui.WithProjectStore(store),
-			tui.WithSessionCreator(creator),
+	t.Run("esc leaves edit mode", func(t *testing.T) {
+		model := newUnifiedModel(sessions, &mockProjectStore{projects: projects},
+			tui.WithProjectEditor(&mockProjectEditor{}, &mockAliasEditor{aliases: map[string]string{}}),
 		)
-		var model tea.Model = m
-		model, _ = model.Update(tui.SessionsMsg{Sessions: []tmux.Session{}})
-
-		// n should jump to new option
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
-		// Enter should switch to project picker
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
-		// Load empty projects
-		model, _ = model.Update(ui.ProjectsLoadedMsg{Projects: []project.Project{}})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
 
 		view := model.View()
-		if !strings.Contains(view, "Select a project") {
-			t.Errorf("expected project picker in combined empty state, got:\n%s", view)
-		}
-		if !strings.Contains(view, "No saved projects yet.") {
-			t.Errorf("expected empty projects message, got:\n%s", view)
+		if strings.Contains(view, "Edit project") || !strings.Contains(view, "myapp") {
+			t.Errorf("expected list after esc, got:\n%s", view)
 		}
+	})
 
-		// Esc should return to session list
-		model, _ = model.Update(ui.BackMsg{})
-		view = model.View()
-		if !strings.Contains(view, "No active sessions") {
-			t.Errorf("expected session list after Esc, got:\n%s", view)
+	t.Run("e without editor is no-op", func(t *testing.T) {
+		model := newUnifiedModel(sessions, &mockProjectStore{projects: projects})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+		if view := model.View(); strings.Contains(view, "Edit project") {
+			t.Errorf("e without editor should be no-op, got:\n%s", view)
 		}
 	})
 }
@@ -935,18 +950,32 @@ func TestNewInProjectOption(t *testing.T) {
 // mockSessionKiller implements tui.SessionKiller for testing.
 type mockSessionKiller struct {
 	killedName string
+	killed     []string
 	err        error
 }
 
 func (m *mockSessionKiller) KillSession(name string) error {
 	m.killedName = name
+	m.killed = append(m.killed, name)
 	return m.err
 }
 
+// mockSessionDetacher implements tui.SessionDetacher for testing.
+type mockSessionDetacher struct {
+	detached []string
+}
+
+func (m *mockSessionDetacher) DetachSession(name string) error {
+	m.detached = append(m.detached, name)
+	return nil
+}
+
 // mockProjectStore implements ui.ProjectStore for tui testing.
 type mockProjectStore struct {
-	projects []project.Project
-	listErr  error
+	projects    []project.Project
+	listErr     error
+	removedPath string
+	removeErr   error
 }
 
 func (m *mockProjectStore) List() ([]project.Project, error) {
@@ -957,7 +986,51 @@ func (m *mockProjectStore) CleanStale() ([]project.Project, error) {
 	return nil, nil
 }
 
-func (m *mockProjectStore) Remove(_ string) error {
+func (m *mockProjectStore) Remove(path string) error {
+	m.removedPath = path
+	return m.removeErr
+}
+
+// mockProjectEditor implements tui.ProjectEditor for testing.
+type mockProjectEditor struct {
+	renamedPath string
+	renamedName string
+	taggedPath  string
+	tags        []string
+}
+
+func (m *mockProjectEditor) Rename(path, newName string) error {
+	m.renamedPath = path
+	m.renamedName = newName
+	return nil
+}
+
+func (m *mockProjectEditor) SetTags(path string, tags []string) error {
+	m.taggedPath = path
+	m.tags = tags
+	return nil
+}
+
+// mockAliasEditor implements tui.AliasEditor for testing.
+type mockAliasEditor struct {
+	aliases map[string]string
+}
+
+func (m *mockAliasEditor) Load() (map[string]string, error) {
+	return m.aliases, nil
+}
+
+func (m *mockAliasEditor) Set(name, path string) {
+	m.aliases[name] = path
+}
+
+func (m *mockAliasEditor) Delete(name string) bool {
+	_, ok := m.aliases[name]
+	delete(m.aliases, name)
+	return ok
+}
+
+func (m *mockAliasEditor) Save() error {
 	return nil
 }
 
@@ -980,14 +1053,19 @@ func (m *mockSessionCreator) CreateFromDir(dir string, command []string) (string
 
 // mockDirLister implements ui.DirLister for tui testing.
 type mockDirLister struct {
-	entries map[string][]browser.DirEntry
+	entries       map[string][]browser.DirEntry
+	hiddenEntries map[string][]browser.DirEntry
 }
 
 func (m *mockDirLister) ListDirectories(path string, showHidden bool) ([]browser.DirEntry, error) {
+	result := []browser.DirEntry{}
 	if entries, ok := m.entries[path]; ok {
-		return entries, nil
+		result = append(result, entries...)
+	}
+	if showHidden {
+		result = append(result, m.hiddenEntries[path]...)
 	}
-	return []browser.DirEntry{}, nil
+	return result, nil
 }
 
 func TestFileBrowserIntegration(t *testing.T) {
@@ -1016,9 +1094,7 @@ func TestFileBrowserIntegration(t *testing.T) {
 		var model tea.Model = m
 		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
 
-		// Navigate to project picker
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		// Load projects into the list
 		model, _ = model.Update(ui.ProjectsLoadedMsg{Projects: store.projects})
 
 		// Trigger browse selection
@@ -1033,9 +1109,9 @@ func TestFileBrowserIntegration(t *testing.T) {
 		if !strings.Contains(view, "code") {
 			t.Errorf("expected file browser to show directory entries, got:\n%s", view)
 		}
-		// Should NOT show project picker
-		if strings.Contains(view, "Select a project") {
-			t.Errorf("should not show project picker when file browser is open:\n%s", view)
+		// Should NOT show the session list
+		if strings.Contains(view, "[b] browse for directory...") {
+			t.Errorf("should not show session list when file browser is open:\n%s", view)
 		}
 	})
 
@@ -1151,7 +1227,7 @@ func TestFileBrowserIntegration(t *testing.T) {
 		}
 	})
 
-	t.Run("cancel in file browser returns to project picker", func(t *testing.T) {
+	t.Run("cancel in file browser returns to session list", func(t *testing.T) {
 		sessions := []tmux.Session{
 			{Name: "dev", Windows: 1, Attached: false},
 		}
@@ -1176,9 +1252,7 @@ func TestFileBrowserIntegration(t *testing.T) {
 		var model tea.Model = m
 		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
 
-		// Navigate to project picker
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		// Load projects into the list
 		model, _ = model.Update(ui.ProjectsLoadedMsg{Projects: store.projects})
 
 		// Open file browser
@@ -1188,12 +1262,12 @@ func TestFileBrowserIntegration(t *testing.T) {
 		model, _ = model.Update(ui.BrowserCancelMsg{})
 
 		view := model.View()
-		// Should be back in project picker
-		if !strings.Contains(view, "Select a project") {
-			t.Errorf("expected project picker after cancel, got:\n%s", view)
+		// Should be back in the session list with projects still shown
+		if !strings.Contains(view, "dev") {
+			t.Errorf("expected session 'dev' after cancel, got:\n%s", view)
 		}
 		if !strings.Contains(view, "myapp") {
-			t.Errorf("expected project 'myapp' in picker, got:\n%s", view)
+			t.Errorf("expected project 'myapp' after cancel, got:\n%s", view)
 		}
 	})
 
@@ -1216,9 +1290,7 @@ func TestFileBrowserIntegration(t *testing.T) {
 		var model tea.Model = m
 		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
 
-		// Navigate to project picker (empty)
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		// Load projects into the list
 		model, _ = model.Update(ui.ProjectsLoadedMsg{Projects: []project.Project{}})
 
 		// In empty project list, cursor is on browse option. Trigger browse.
@@ -1295,9 +1367,9 @@ func TestInitialFilter(t *testing.T) {
 		if strings.Contains(view, "other") {
 			t.Errorf("'other' should be filtered out, got:\n%s", view)
 		}
-		// Should show new in project option
-		if !strings.Contains(view, "[n] new in project...") {
-			t.Errorf("expected '[n] new in project...' in view, got:\n%s", view)
+		// Should show browse option
+		if !strings.Contains(view, "[b] browse for directory...") {
+			t.Errorf("expected '[b] browse for directory...' in view, got:\n%s", view)
 		}
 	})
 
@@ -1373,9 +1445,9 @@ func TestEmptyState(t *testing.T) {
 		if !strings.Contains(view, "No active sessions") {
 			t.Errorf("expected view to contain 'No active sessions', got %q", view)
 		}
-		// Should still show the new option
-		if !strings.Contains(view, "[n] new in project...") {
-			t.Errorf("expected view to contain '[n] new in project...', got %q", view)
+		// Should still show the browse option
+		if !strings.Contains(view, "[b] browse for directory...") {
+			t.Errorf("expected view to contain '[b] browse for directory...', got %q", view)
 		}
 	})
 
@@ -1387,9 +1459,9 @@ func TestEmptyState(t *testing.T) {
 		if !strings.Contains(view, "No active sessions") {
 			t.Errorf("expected view to contain 'No active sessions', got %q", view)
 		}
-		// Should still show the new option
-		if !strings.Contains(view, "[n] new in project...") {
-			t.Errorf("expected view to contain '[n] new in project...', got %q", view)
+		// Should still show the browse option
+		if !strings.Contains(view, "[b] browse for directory...") {
+			t.Errorf("expected view to contain '[b] browse for directory...', got %q", view)
 		}
 	})
 
@@ -1586,19 +1658,19 @@ func TestInsideTmuxSessionExclusion(t *testing.T) {
 		}
 	})
 
-	t.Run("new in project option visible when inside tmux", func(t *testing.T) {
+	t.Run("browse option visible when inside tmux", func(t *testing.T) {
 		sessions := []tmux.Session{
 			{Name: "current-sess", Windows: 1, Attached: true},
 		}
 		m := tui.NewModelWithSessions(sessions).WithInsideTmux("current-sess")
 		view := m.View()
 
-		if !strings.Contains(view, "[n] new in project...") {
-			t.Errorf("new in project option should be visible inside tmux, got:\n%s", view)
+		if !strings.Contains(view, "[b] browse for directory...") {
+			t.Errorf("browse option should be visible inside tmux, got:\n%s", view)
 		}
 	})
 
-	t.Run("new in project option visible with sessions filtered inside tmux", func(t *testing.T) {
+	t.Run("browse option visible with sessions filtered inside tmux", func(t *testing.T) {
 		sessions := []tmux.Session{
 			{Name: "alpha", Windows: 1, Attached: false},
 			{Name: "current-sess", Windows: 2, Attached: true},
@@ -1606,8 +1678,8 @@ func TestInsideTmuxSessionExclusion(t *testing.T) {
 		m := tui.NewModelWithSessions(sessions).WithInsideTmux("current-sess")
 		view := m.View()
 
-		if !strings.Contains(view, "[n] new in project...") {
-			t.Errorf("new in project option should be visible, got:\n%s", view)
+		if !strings.Contains(view, "[b] browse for directory...") {
+			t.Errorf("browse option should be visible, got:\n%s", view)
 		}
 	})
 }
@@ -1804,7 +1876,31 @@ func TestKillSession(t *testing.T) {
 		}
 	})
 
-	t.Run("K on new-in-project option is no-op", func(t *testing.T) {
+	t.Run("cursor on a project stays there when sessions refresh", func(t *testing.T) {
+		sessions := []tmux.Session{
+			{Name: "alpha", Windows: 1, Attached: false},
+		}
+		store := &mockProjectStore{projects: []project.Project{
+			{Path: "/code/api", Name: "api"},
+			{Path: "/code/web", Name: "web"},
+		}}
+		m := tui.New(&mockSessionLister{sessions: sessions}, tui.WithProjectStore(store))
+		var model tea.Model = m
+		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
+		model, _ = model.Update(ui.ProjectsLoadedMsg{Projects: store.projects})
+
+		// Navigate to web, the second project
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
+
+		view := model.View()
+		if !strings.Contains(view, "> web") {
+			t.Errorf("cursor should stay on 'web' after sessions refresh, got:\n%s", view)
+		}
+	})
+
+	t.Run("K on browse option is no-op", func(t *testing.T) {
 		sessions := []tmux.Session{
 			{Name: "alpha", Windows: 1, Attached: false},
 		}
@@ -1814,19 +1910,19 @@ func TestKillSession(t *testing.T) {
 		var model tea.Model = m
 		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
 
-		// Navigate to the [n] option
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+		// Navigate to the browse option
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
 
 		// Press K — should be no-op
 		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'K'}})
 
 		view := model.View()
 		if strings.Contains(view, "Kill session") {
-			t.Errorf("K on new option should be no-op, got:\n%s", view)
+			t.Errorf("K on browse option should be no-op, got:\n%s", view)
 		}
 	})
 
-	t.Run("kill error returns error in SessionsMsg", func(t *testing.T) {
+	t.Run("kill error shows in the status line without quitting", func(t *testing.T) {
 		sessions := []tmux.Session{
 			{Name: "alpha", Windows: 1, Attached: false},
 			{Name: "bravo", Windows: 2, Attached: false},
@@ -1845,21 +1941,24 @@ func TestKillSession(t *testing.T) {
 			t.Fatal("expected command from kill confirmation, got nil")
 		}
 
-		// Execute the command — it should return a SessionsMsg with the kill error
-		msg := cmd()
-		sessionsMsg, ok := msg.(tui.SessionsMsg)
-		if !ok {
-			t.Fatalf("expected SessionsMsg, got %T", msg)
+		// Execute the command and feed the refreshed list back
+		model, cmd = model.Update(cmd())
+		if cmd != nil {
+			if _, ok := cmd().(tea.QuitMsg); ok {
+				t.Fatal("a failed kill should not quit")
+			}
 		}
-		if sessionsMsg.Err == nil {
-			t.Fatal("expected error in SessionsMsg when kill fails, got nil")
+
+		view := model.View()
+		if !strings.Contains(view, "failed to kill session 'alpha': session not found") {
+			t.Errorf("expected the kill error in the status line, got:\n%s", view)
 		}
-		if sessionsMsg.Err.Error() != "failed to kill session 'alpha': session not found" {
-			t.Errorf("unexpected error message: %q", sessionsMsg.Err.Error())
+		if !strings.Contains(view, "alpha") || !strings.Contains(view, "bravo") {
+			t.Errorf("expected both sessions still listed, got:\n%s", view)
 		}
 	})
 
-	t.Run("kill error clears confirmation state via SessionsMsg", func(t *testing.T) {
+	t.Run("kill error clears confirmation state", func(t *testing.T) {
 		sessions := []tmux.Session{
 			{Name: "alpha", Windows: 1, Attached: false},
 			{Name: "bravo", Windows: 2, Attached: false},
@@ -1878,8 +1977,6 @@ func TestKillSession(t *testing.T) {
 		msg := cmd()
 		model, _ = model.Update(msg)
 
-		// SessionsMsg with error triggers quit, so the model should exit.
-		// But the confirmation prompt should not still be showing.
 		view := model.View()
 		if strings.Contains(view, "Kill session") {
 			t.Errorf("confirmation prompt should be cleared after kill error, got:\n%s", view)
@@ -2105,7 +2202,7 @@ func TestRenameSession(t *testing.T) {
 		}
 	})
 
-	t.Run("R on new-in-project option is no-op", func(t *testing.T) {
+	t.Run("R on browse option is no-op", func(t *testing.T) {
 		sessions := []tmux.Session{
 			{Name: "alpha", Windows: 1, Attached: false},
 		}
@@ -2115,15 +2212,15 @@ func TestRenameSession(t *testing.T) {
 		var model tea.Model = m
 		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
 
-		// Navigate to the [n] option
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+		// Navigate to the browse option
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
 
 		// Press R — should be no-op
 		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
 
 		view := model.View()
 		if strings.Contains(view, "Rename:") {
-			t.Errorf("R on new option should be no-op, got:\n%s", view)
+			t.Errorf("R on browse option should be no-op, got:\n%s", view)
 		}
 	})
 
@@ -2206,7 +2303,7 @@ func TestRenameSession(t *testing.T) {
 		}
 	})
 
-	t.Run("rename error from tmux is returned in SessionsMsg", func(t *testing.T) {
+	t.Run("rename error from tmux shows in the status line without quitting", func(t *testing.T) {
 		sessions := []tmux.Session{
 			{Name: "alpha", Windows: 1, Attached: false},
 		}
@@ -2222,19 +2319,21 @@ func TestRenameSession(t *testing.T) {
 		for _, r := range "bravo" {
 			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
 		}
-		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
 
 		if cmd == nil {
 			t.Fatal("expected command from rename, got nil")
 		}
 
-		msg := cmd()
-		sessionsMsg, ok := msg.(tui.SessionsMsg)
-		if !ok {
-			t.Fatalf("expected SessionsMsg, got %T", msg)
+		model, cmd = model.Update(cmd())
+		if cmd != nil {
+			if _, ok := cmd().(tea.QuitMsg); ok {
+				t.Fatal("a failed rename should not quit")
+			}
 		}
-		if sessionsMsg.Err == nil {
-			t.Fatal("expected error in SessionsMsg when rename fails, got nil")
+		view := model.View()
+		if !strings.Contains(view, "failed to rename session 'alpha': duplicate session name") {
+			t.Errorf("expected the rename error in the status line, got:\n%s", view)
 		}
 	})
 
@@ -2370,7 +2469,7 @@ func TestFilterMode(t *testing.T) {
 		}
 	})
 
-	t.Run("new-in-project option always visible during filter", func(t *testing.T) {
+	t.Run("browse option always visible during filter", func(t *testing.T) {
 		sessions := []tmux.Session{
 			{Name: "alpha", Windows: 1, Attached: false},
 			{Name: "bravo", Windows: 2, Attached: false},
@@ -2384,8 +2483,8 @@ func TestFilterMode(t *testing.T) {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
 
 		view := m.View()
-		if !strings.Contains(view, "[n] new in project...") {
-			t.Errorf("new-in-project should always be visible during filter, got:\n%s", view)
+		if !strings.Contains(view, "[b] browse for directory...") {
+			t.Errorf("browse option should always be visible during filter, got:\n%s", view)
 		}
 		if strings.Contains(view, "alpha") {
 			t.Errorf("alpha should not be visible with filter 'xyz', got:\n%s", view)
@@ -2477,7 +2576,7 @@ func TestFilterMode(t *testing.T) {
 		}
 	})
 
-	t.Run("no sessions match filter shows empty filtered list with new-in-project visible", func(t *testing.T) {
+	t.Run("no sessions match filter shows empty filtered list with browse option visible", func(t *testing.T) {
 		sessions := []tmux.Session{
 			{Name: "alpha", Windows: 1, Attached: false},
 			{Name: "bravo", Windows: 2, Attached: false},
@@ -2498,21 +2597,21 @@ func TestFilterMode(t *testing.T) {
 		if strings.Contains(view, "bravo") {
 			t.Errorf("no sessions should match 'zzz', got:\n%s", view)
 		}
-		// [n] new in project should still be visible
-		if !strings.Contains(view, "[n] new in project...") {
-			t.Errorf("[n] new in project should be visible even when no sessions match, got:\n%s", view)
+		// Browse option should still be visible
+		if !strings.Contains(view, "[b] browse for directory...") {
+			t.Errorf("browse option should be visible even when no sessions match, got:\n%s", view)
 		}
-		// Cursor should be on [n] since no sessions match (cursor 0 == len(matched) == 0)
+		// Cursor should be on the browse option since nothing matches
 		lines := strings.Split(view, "\n")
 		var newLine string
 		for _, line := range lines {
-			if strings.Contains(line, "new in project") {
+			if strings.Contains(line, "browse for directory") {
 				newLine = line
 				break
 			}
 		}
 		if !strings.Contains(newLine, ">") {
-			t.Errorf("[n] should have cursor when no sessions match, got:\n%s", view)
+			t.Errorf("Browse option should have cursor when nothing matches, got:\n%s", view)
 		}
 	})
 
@@ -2867,8 +2966,8 @@ func TestCommandPendingMode(t *testing.T) {
 		if strings.Contains(view, "No active sessions") {
 			t.Errorf("session list should not be shown in command-pending mode, got:\n%s", view)
 		}
-		if strings.Contains(view, "[n] new in project...") {
-			t.Errorf("session list new option should not be shown in command-pending mode, got:\n%s", view)
+		if strings.Contains(view, "[b] browse for directory...") {
+			t.Errorf("session list browse option should not be shown in command-pending mode, got:\n%s", view)
 		}
 	})
 
@@ -3241,7 +3340,7 @@ func TestNewWithFunctionalOptions(t *testing.T) {
 		}
 	})
 
-	t.Run("WithProjectStore and WithSessionCreator enable project picker", func(t *testing.T) {
+	t.Run("WithProjectStore lists projects below sessions", func(t *testing.T) {
 		sessions := []tmux.Session{
 			{Name: "dev", Windows: 1, Attached: false},
 		}
@@ -3260,14 +3359,12 @@ func TestNewWithFunctionalOptions(t *testing.T) {
 		var model tea.Model = m
 		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
 
-		// Navigate to project picker
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		// Load projects into the list
 		model, _ = model.Update(ui.ProjectsLoadedMsg{Projects: store.projects})
 
 		view := model.View()
-		if !strings.Contains(view, "Select a project") {
-			t.Errorf("expected project picker view, got:\n%s", view)
+		if !strings.Contains(view, "myapp") {
+			t.Errorf("expected project 'myapp' in list, got:\n%s", view)
 		}
 	})
 
@@ -3296,9 +3393,7 @@ func TestNewWithFunctionalOptions(t *testing.T) {
 		var model tea.Model = m
 		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
 
-		// Navigate to project picker
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
-		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		// Load projects into the list
 		model, _ = model.Update(ui.ProjectsLoadedMsg{Projects: store.projects})
 
 		// Trigger browse
@@ -3313,6 +3408,26 @@ func TestNewWithFunctionalOptions(t *testing.T) {
 		}
 	})
 
+	t.Run("WithShowHidden opens file browser with hidden directories", func(t *testing.T) {
+		dirLister := &mockDirLister{
+			entries:       map[string][]browser.DirEntry{"/home/user": {{Name: "code"}}},
+			hiddenEntries: map[string][]browser.DirEntry{"/home/user": {{Name: ".dotfiles"}}},
+		}
+
+		for _, show := range []bool{false, true} {
+			var model tea.Model = tui.New(&mockSessionLister{},
+				tui.WithDirLister(dirLister, "/home/user"),
+				tui.WithShowHidden(show),
+			)
+			model, _ = model.Update(tui.SessionsMsg{})
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+
+			if got := strings.Contains(model.View(), ".dotfiles"); got != show {
+				t.Errorf("show hidden %v: hidden directory visible = %v\n%s", show, got, model.View())
+			}
+		}
+	})
+
 	t.Run("all options combined", func(t *testing.T) {
 		sessions := []tmux.Session{
 			{Name: "alpha", Windows: 1, Attached: false},
@@ -3350,3 +3465,998 @@ func TestNewWithFunctionalOptions(t *testing.T) {
 		}
 	})
 }
+
+// mockSessionRegistry implements tui.SessionRegistry for testing.
+type mockSessionRegistry struct {
+	entries map[string]registry.Entry
+	err     error
+}
+
+func (m *mockSessionRegistry) ByName() (map[string]registry.Entry, error) {
+	return m.entries, m.err
+}
+
+func TestSessionRegistry(t *testing.T) {
+	t.Run("Init includes registry entries in SessionsMsg", func(t *testing.T) {
+		lister := &mockSessionLister{sessions: []tmux.Session{{Name: "api-x1", Windows: 1}}}
+		reg := &mockSessionRegistry{entries: map[string]registry.Entry{
+			"api-x1": {Name: "api-x1", ProjectPath: "/code/api", ProjectName: "api"},
+		}}
+		m := tui.New(lister, tui.WithSessionRegistry(reg))
+
+		msg, ok := m.Init()().(tui.SessionsMsg)
+		if !ok {
+			t.Fatal("expected SessionsMsg")
+		}
+		if msg.Projects["api-x1"].ProjectPath != "/code/api" {
+			t.Errorf("Projects = %+v, want api-x1 -> /code/api", msg.Projects)
+		}
+	})
+
+	t.Run("registry error still delivers sessions", func(t *testing.T) {
+		lister := &mockSessionLister{sessions: []tmux.Session{{Name: "api-x1", Windows: 1}}}
+		reg := &mockSessionRegistry{err: fmt.Errorf("unreadable")}
+		m := tui.New(lister, tui.WithSessionRegistry(reg))
+
+		msg, ok := m.Init()().(tui.SessionsMsg)
+		if !ok {
+			t.Fatal("expected SessionsMsg")
+		}
+		if msg.Err != nil || len(msg.Sessions) != 1 {
+			t.Errorf("got err=%v sessions=%d, want no error and 1 session", msg.Err, len(msg.Sessions))
+		}
+	})
+
+	t.Run("session rows show the registered project name", func(t *testing.T) {
+		sessions := []tmux.Session{
+			{Name: "renamed-session", Windows: 1},
+			{Name: "untracked", Windows: 1},
+		}
+		var model tea.Model = tui.New(&mockSessionLister{sessions: sessions})
+		model, _ = model.Update(tui.SessionsMsg{
+			Sessions: sessions,
+			Projects: map[string]registry.Entry{
+				"renamed-session": {Name: "renamed-session", ProjectPath: "/code/billing", ProjectName: "billing"},
+			},
+		})
+
+		view := model.View()
+		for _, line := range strings.Split(view, "\n") {
+			if strings.Contains(line, "renamed-session") && !strings.Contains(line, "billing") {
+				t.Errorf("registered session line missing project name: %q", line)
+			}
+			if strings.Contains(line, "untracked") && strings.Contains(line, "billing") {
+				t.Errorf("untracked session line should not show a project: %q", line)
+			}
+		}
+	})
+}
+
+func TestMultiSelect(t *testing.T) {
+	space := tea.KeyMsg{Type: tea.KeySpace}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	keyK := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'K'}}
+	keyY := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}
+	sessions := []tmux.Session{
+		{Name: "alpha", Windows: 1},
+		{Name: "bravo", Windows: 1, Attached: true},
+		{Name: "charlie", Windows: 1},
+		{Name: "delta", Windows: 1, Attached: true},
+	}
+
+	newModel := func(opts ...tui.Option) (tea.Model, *mockSessionLister) {
+		lister := &mockSessionLister{sessions: sessions}
+		var model tea.Model = tui.New(lister, opts...)
+		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
+		return model, lister
+	}
+
+	t.Run("space toggles selection and shows the count", func(t *testing.T) {
+		model, _ := newModel()
+		model, _ = model.Update(space)
+		model, _ = model.Update(down)
+		model, _ = model.Update(down)
+		model, _ = model.Update(space)
+
+		view := model.View()
+		if !strings.Contains(view, "2 selected") {
+			t.Errorf("expected selection count, got:\n%s", view)
+		}
+		if !strings.Contains(view, "✓ ") {
+			t.Errorf("expected selection marker, got:\n%s", view)
+		}
+
+		model, _ = model.Update(space)
+		if view := model.View(); !strings.Contains(view, "1 selected") {
+			t.Errorf("expected count to drop after toggling off, got:\n%s", view)
+		}
+	})
+
+	t.Run("esc clears the selection before quitting", func(t *testing.T) {
+		model, _ := newModel()
+		model, _ = model.Update(space)
+		model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+		if cmd != nil {
+			t.Fatal("esc with a selection should not quit")
+		}
+		if view := model.View(); strings.Contains(view, "selected") {
+			t.Errorf("selection should be cleared, got:\n%s", view)
+		}
+	})
+
+	t.Run("bulk kill confirms once and refreshes once", func(t *testing.T) {
+		killer := &mockSessionKiller{}
+		model, lister := newModel(tui.WithKiller(killer))
+		model, _ = model.Update(space)
+		model, _ = model.Update(down)
+		model, _ = model.Update(space)
+		model, _ = model.Update(keyK)
+
+		view := model.View()
+		if !strings.Contains(view, "Kill 2 sessions: alpha, bravo? (y/n)") {
+			t.Fatalf("expected one confirmation listing both names, got:\n%s", view)
+		}
+
+		model, cmd := model.Update(keyY)
+		lister.sessions = sessions[2:]
+		msg := cmd()
+		if _, ok := msg.(tea.BatchMsg); ok || msg == nil {
+			t.Fatalf("expected a single refresh message, got %T", msg)
+		}
+		model, _ = model.Update(msg)
+
+		if strings.Join(killer.killed, ",") != "alpha,bravo" {
+			t.Errorf("killed %v, want [alpha bravo]", killer.killed)
+		}
+		view = model.View()
+		if strings.Contains(view, "selected") {
+			t.Errorf("selection should be cleared after the kill, got:\n%s", view)
+		}
+		if !strings.Contains(view, "> charlie") {
+			t.Errorf("cursor should land on the next surviving session, got:\n%s", view)
+		}
+	})
+
+	t.Run("bulk kill failures show in the status line and refresh", func(t *testing.T) {
+		killer := &mockSessionKiller{err: fmt.Errorf("boom")}
+		model, lister := newModel(tui.WithKiller(killer))
+		model, _ = model.Update(space)
+		model, _ = model.Update(down)
+		model, _ = model.Update(space)
+		model, _ = model.Update(keyK)
+
+		model, cmd := model.Update(keyY)
+		lister.sessions = sessions[1:]
+		model, cmd = model.Update(cmd())
+		if cmd != nil {
+			if _, ok := cmd().(tea.QuitMsg); ok {
+				t.Fatal("a failed kill should not quit")
+			}
+		}
+
+		view := model.View()
+		for _, want := range []string{"failed to kill session 'alpha': boom", "failed to kill session 'bravo': boom"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("expected %q in the status line, got:\n%s", want, view)
+			}
+		}
+		if list, _, _ := strings.Cut(view, "failed to kill"); strings.Contains(list, "alpha") {
+			t.Errorf("list should be refreshed without alpha, got:\n%s", view)
+		}
+
+		model, _ = model.Update(down)
+		if strings.Contains(model.View(), "boom") {
+			t.Error("status line should clear on the next key")
+		}
+	})
+
+	t.Run("declining bulk kill keeps the selection", func(t *testing.T) {
+		killer := &mockSessionKiller{}
+		model, _ := newModel(tui.WithKiller(killer))
+		model, _ = model.Update(space)
+		model, _ = model.Update(keyK)
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+
+		if len(killer.killed) != 0 {
+			t.Errorf("killed %v, want none", killer.killed)
+		}
+		if view := model.View(); !strings.Contains(view, "1 selected") {
+			t.Errorf("selection should survive a declined kill, got:\n%s", view)
+		}
+	})
+
+	t.Run("bulk detach only detaches attached sessions", func(t *testing.T) {
+		detacher := &mockSessionDetacher{}
+		model, _ := newModel(tui.WithDetacher(detacher))
+		for range sessions {
+			model, _ = model.Update(space)
+			model, _ = model.Update(down)
+		}
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+		if cmd == nil {
+			t.Fatal("expected a detach command")
+		}
+		cmd()
+
+		if strings.Join(detacher.detached, ",") != "bravo,delta" {
+			t.Errorf("detached %v, want [bravo delta]", detacher.detached)
+		}
+	})
+
+	t.Run("ctrl+k kills every session matching the filter", func(t *testing.T) {
+		killer := &mockSessionKiller{}
+		model, _ := newModel(tui.WithKiller(killer))
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ha")})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+
+		if view := model.View(); !strings.Contains(view, "Kill 2 sessions: alpha, charlie? (y/n)") {
+			t.Fatalf("expected confirmation for the filtered sessions, got:\n%s", view)
+		}
+		_, cmd := model.Update(keyY)
+		cmd()
+		if len(killer.killed) != 2 {
+			t.Errorf("killed %v, want the two matching sessions", killer.killed)
+		}
+	})
+
+	t.Run("tab selects while filtering", func(t *testing.T) {
+		model, _ := newModel()
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+		if view := model.View(); !strings.Contains(view, "1 selected") {
+			t.Errorf("expected one session selected, got:\n%s", view)
+		}
+	})
+}
+
+func TestSessionMetadataInRows(t *testing.T) {
+	sessions := []tmux.Session{
+		{Name: "busy", Windows: 2, Attached: true, Clients: 3, Activity: time.Now().Add(-2 * time.Hour)},
+		{Name: "idle", Windows: 1},
+		{Name: "gone", Exited: true},
+	}
+	m := tui.NewModelWithSessions(sessions)
+	view := m.View()
+
+	if !strings.Contains(view, "○ exited") {
+		t.Errorf("expected exited marker, got:\n%s", view)
+	}
+	if !strings.Contains(view, "active 2h ago") {
+		t.Errorf("expected relative activity time, got:\n%s", view)
+	}
+	if !strings.Contains(view, "● attached (3)") {
+		t.Errorf("expected client count for multiply attached session, got:\n%s", view)
+	}
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, "idle") && strings.Contains(line, "active") {
+			t.Errorf("session without activity time should not show one: %q", line)
+		}
+	}
+}
+
+type mockPaneCapturer struct {
+	contents map[string]string
+	err      error
+	captured []string
+}
+
+func (m *mockPaneCapturer) CapturePane(name string) (string, error) {
+	m.captured = append(m.captured, name)
+	return m.contents[name], m.err
+}
+
+// runCmd runs cmd and feeds every message it produces, including those of
+// batched commands, back into model.
+func runCmd(model tea.Model, cmd tea.Cmd) tea.Model {
+	if cmd == nil {
+		return model
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			model = runCmd(model, c)
+		}
+		return model
+	}
+	model, cmd = model.Update(msg)
+	return runCmd(model, cmd)
+}
+
+func TestPanePreview(t *testing.T) {
+	sessions := []tmux.Session{
+		{Name: "api-a1b2c3", Windows: 1},
+		{Name: "api-d4e5f6", Windows: 1},
+	}
+	wide := tea.WindowSizeMsg{Width: 160, Height: 40}
+
+	newModel := func(capturer *mockPaneCapturer) tea.Model {
+		var model tea.Model = tui.New(&mockSessionLister{sessions: sessions}, tui.WithPaneCapturer(capturer))
+		model, _ = model.Update(tui.SessionsMsg{Sessions: sessions})
+		return model
+	}
+
+	t.Run("shows the highlighted session's pane and follows the cursor", func(t *testing.T) {
+		capturer := &mockPaneCapturer{contents: map[string]string{
+			"api-a1b2c3": "$ make test\nPASS",
+			"api-d4e5f6": "$ tail -f server.log",
+		}}
+		model := newModel(capturer)
+		model = runCmd(model.Update(wide))
+
+		if view := model.View(); !strings.Contains(view, "PASS") {
+			t.Errorf("expected preview of first session, got:\n%s", view)
+		}
+
+		model = runCmd(model.Update(tea.KeyMsg{Type: tea.KeyDown}))
+		view := model.View()
+		if !strings.Contains(view, "tail -f server.log") || strings.Contains(view, "PASS") {
+			t.Errorf("expected preview to follow the cursor, got:\n%s", view)
+		}
+		if strings.Join(capturer.captured, ",") != "api-a1b2c3,api-d4e5f6" {
+			t.Errorf("captured %v, want one capture per session", capturer.captured)
+		}
+	})
+
+	t.Run("capture runs asynchronously and stale results are dropped", func(t *testing.T) {
+		capturer := &mockPaneCapturer{contents: map[string]string{
+			"api-a1b2c3": "first",
+			"api-d4e5f6": "second",
+		}}
+		model := newModel(capturer)
+		model, firstCapture := model.Update(wide)
+		if len(capturer.captured) != 0 {
+			t.Fatal("capture should not run inside Update")
+		}
+
+		model = runCmd(model.Update(tea.KeyMsg{Type: tea.KeyDown}))
+		model = runCmd(model, firstCapture)
+
+		view := model.View()
+		if strings.Contains(view, "first") || !strings.Contains(view, "second") {
+			t.Errorf("late capture of a previous session should be ignored, got:\n%s", view)
+		}
+	})
+
+	t.Run("hidden on narrow terminals", func(t *testing.T) {
+		capturer := &mockPaneCapturer{contents: map[string]string{"api-a1b2c3": "PASS"}}
+		model := newModel(capturer)
+		model = runCmd(model.Update(tea.WindowSizeMsg{Width: 80, Height: 40}))
+
+		if strings.Contains(model.View(), "PASS") || len(capturer.captured) != 0 {
+			t.Error("preview should not be shown or captured on a narrow terminal")
+		}
+	})
+
+	t.Run("p toggles the preview", func(t *testing.T) {
+		capturer := &mockPaneCapturer{contents: map[string]string{"api-a1b2c3": "PASS"}}
+		model := newModel(capturer)
+		model = runCmd(model.Update(wide))
+
+		model = runCmd(model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}}))
+		if strings.Contains(model.View(), "PASS") {
+			t.Error("preview should be hidden after p")
+		}
+
+		model = runCmd(model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}}))
+		if !strings.Contains(model.View(), "PASS") {
+			t.Error("preview should be shown again after a second p")
+		}
+		if len(capturer.captured) != 2 {
+			t.Errorf("captured %d times, want a fresh capture when shown again", len(capturer.captured))
+		}
+	})
+
+	t.Run("capture errors are shown in the panel", func(t *testing.T) {
+		capturer := &mockPaneCapturer{err: fmt.Errorf("can't find pane")}
+		model := newModel(capturer)
+		model = runCmd(model.Update(wide))
+
+		if view := model.View(); !strings.Contains(view, "Preview unavailable") {
+			t.Errorf("expected unavailable message, got:\n%s", view)
+		}
+	})
+}
+
+func TestAutoRefresh(t *testing.T) {
+	initial := []tmux.Session{
+		{Name: "alpha", Windows: 1},
+		{Name: "bravo", Windows: 1},
+		{Name: "charlie", Windows: 1},
+	}
+
+	// start loads the initial sessions and returns the model with the command
+	// that fires the first refresh tick.
+	start := func(lister *mockSessionLister, opts ...tui.Option) (tea.Model, tea.Cmd) {
+		opts = append([]tui.Option{tui.WithRefreshInterval(time.Millisecond)}, opts...)
+		m := tui.New(lister, opts...)
+		batch, ok := m.Init()().(tea.BatchMsg)
+		if !ok || len(batch) != 2 {
+			t.Fatalf("expected fetch and refresh tick from Init, got %v", batch)
+		}
+		var model tea.Model = m
+		model, _ = model.Update(batch[0]())
+		return model, batch[1]
+	}
+
+	// refresh fires one tick and applies the resulting refresh.
+	refresh := func(t *testing.T, model tea.Model, tick tea.Cmd) (tea.Model, tea.Cmd) {
+		t.Helper()
+		model, cmd := model.Update(tick())
+		if cmd == nil {
+			t.Fatal("expected a refresh command after the tick")
+		}
+		model, next := model.Update(cmd())
+		if next == nil {
+			t.Fatal("expected the next tick to be scheduled")
+		}
+		return model, next
+	}
+
+	t.Run("without an interval Init does not schedule refreshes", func(t *testing.T) {
+		m := tui.New(&mockSessionLister{sessions: initial})
+		if _, ok := m.Init()().(tui.SessionsMsg); !ok {
+			t.Error("expected Init to only fetch sessions")
+		}
+	})
+
+	t.Run("picks up sessions created and killed elsewhere", func(t *testing.T) {
+		lister := &mockSessionLister{sessions: initial}
+		model, tick := start(lister)
+
+		lister.sessions = []tmux.Session{{Name: "alpha", Windows: 1}, {Name: "delta", Windows: 2}}
+		model, tick = refresh(t, model, tick)
+
+		view := model.View()
+		if !strings.Contains(view, "delta") || strings.Contains(view, "bravo") {
+			t.Errorf("expected refreshed sessions, got:\n%s", view)
+		}
+
+		lister.sessions = append(lister.sessions, tmux.Session{Name: "echo", Windows: 1})
+		model, _ = refresh(t, model, tick)
+		if !strings.Contains(model.View(), "echo") {
+			t.Error("expected refreshing to continue after the first tick")
+		}
+	})
+
+	t.Run("cursor stays on the same session by name", func(t *testing.T) {
+		lister := &mockSessionLister{sessions: initial}
+		model, tick := start(lister)
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+
+		lister.sessions = []tmux.Session{{Name: "aardvark", Windows: 1}, {Name: "alpha", Windows: 1}, {Name: "bravo", Windows: 1}, {Name: "charlie", Windows: 1}}
+		model, _ = refresh(t, model, tick)
+
+		if view := model.View(); !strings.Contains(view, "> charlie") {
+			t.Errorf("cursor should stay on charlie, got:\n%s", view)
+		}
+	})
+
+	t.Run("failed refresh keeps the list and does not quit", func(t *testing.T) {
+		lister := &mockSessionLister{sessions: initial}
+		model, tick := start(lister)
+
+		lister.err = fmt.Errorf("server exited")
+		model, _ = refresh(t, model, tick)
+
+		if view := model.View(); !strings.Contains(view, "bravo") {
+			t.Errorf("expected the previous sessions to remain, got:\n%s", view)
+		}
+	})
+
+	t.Run("kill prompt survives a refresh and drops vanished sessions", func(t *testing.T) {
+		lister := &mockSessionLister{sessions: initial}
+		model, tick := start(lister, tui.WithKiller(&mockSessionKiller{}))
+		for range initial {
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeySpace})
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		}
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'K'}})
+
+		lister.sessions = initial[1:]
+		model, _ = refresh(t, model, tick)
+
+		if view := model.View(); !strings.Contains(view, "Kill 2 sessions: bravo, charlie? (y/n)") {
+			t.Errorf("expected the prompt without the vanished session, got:\n%s", view)
+		}
+	})
+}
+
+func TestFilterHighlightKeepsNamesIntact(t *testing.T) {
+	sessions := []tmux.Session{{Name: "web-api", Windows: 1}, {Name: "portal", Windows: 1}}
+	model := newUnifiedModel(sessions, &mockProjectStore{projects: []project.Project{{Path: "/code/api-gateway", Name: "api-gateway"}}})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("api")})
+
+	view := model.View()
+	for _, name := range []string{"> web-api", "api-gateway"} {
+		if !strings.Contains(view, name) {
+			t.Errorf("expected %q rendered intact while filtering, got:\n%s", name, view)
+		}
+	}
+}
+
+func TestExtendedFilterSyntax(t *testing.T) {
+	sessions := []tmux.Session{
+		{Name: "api-gateway", Windows: 1},
+		{Name: "web-api", Windows: 1},
+		{Name: "api-test", Windows: 1},
+	}
+	model := newUnifiedModel(sessions, &mockProjectStore{})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("^api")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeySpace})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("!test")})
+
+	view := model.View()
+	if !strings.Contains(view, "filter: ^api !test") {
+		t.Errorf("expected space to be typed into the filter, got:\n%s", view)
+	}
+	if !strings.Contains(view, "api-gateway") || strings.Contains(view, "web-api") || strings.Contains(view, "api-test") {
+		t.Errorf("expected only api-gateway to match, got:\n%s", view)
+	}
+}
+
+func TestTagFilter(t *testing.T) {
+	sessions := []tmux.Session{
+		{Name: "api-x1", Windows: 1, Path: "/code/api"},
+		{Name: "renamed", Windows: 1, Path: "/tmp"},
+		{Name: "web-x1", Windows: 1, Path: "/code/web"},
+	}
+	projects := []project.Project{
+		{Path: "/code/api", Name: "api", Tags: []string{"client", "work"}},
+		{Path: "/code/web", Name: "web"},
+	}
+	store := &mockProjectStore{projects: projects}
+
+	var model tea.Model = tui.New(&mockSessionLister{sessions: sessions}, tui.WithProjectStore(store)).WithInitialFilter("#client")
+	model, _ = model.Update(tui.SessionsMsg{Sessions: sessions, Projects: map[string]registry.Entry{
+		"renamed": {Name: "renamed", ProjectPath: "/code/api", ProjectName: "api"},
+	}})
+	model, _ = model.Update(ui.ProjectsLoadedMsg{Projects: projects})
+
+	view := model.View()
+	if !strings.Contains(view, "filter: #client") {
+		t.Errorf("expected the tag filter to be applied, got:\n%s", view)
+	}
+	for _, want := range []string{"api-x1", "renamed", "#client #work"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in view, got:\n%s", want, view)
+		}
+	}
+	for _, unwanted := range []string{"web-x1", "/code/web"} {
+		if strings.Contains(view, unwanted) {
+			t.Errorf("expected %q to be filtered out, got:\n%s", unwanted, view)
+		}
+	}
+}
+
+// mockProjectScanner implements tui.ProjectScanner, adding its projects to
+// a mock store when it scans.
+type mockProjectScanner struct {
+	store *mockProjectStore
+	found []project.Project
+	err   error
+	calls int
+}
+
+func (m *mockProjectScanner) Scan() (int, error) {
+	m.calls++
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.store.projects = append(m.store.projects, m.found...)
+	return len(m.found), nil
+}
+
+func TestProjectScanner(t *testing.T) {
+	sessions := []tmux.Session{{Name: "dev", Windows: 1}}
+
+	t.Run("background scan reloads the project list", func(t *testing.T) {
+		store := &mockProjectStore{projects: []project.Project{{Path: "/code/api", Name: "api"}}}
+		scanner := &mockProjectScanner{store: store, found: []project.Project{{Path: "/code/discovered", Name: "discovered"}}}
+		m := tui.New(&mockSessionLister{sessions: sessions}, tui.WithProjectStore(store), tui.WithProjectScanner(scanner))
+
+		model := runCmd(m, m.Init())
+
+		if scanner.calls != 1 {
+			t.Errorf("scanner called %d times, want 1", scanner.calls)
+		}
+		if view := model.View(); !strings.Contains(view, "api") || !strings.Contains(view, "discovered") {
+			t.Errorf("expected scanned project in view, got:\n%s", view)
+		}
+	})
+
+	t.Run("scan errors leave the list alone", func(t *testing.T) {
+		store := &mockProjectStore{projects: []project.Project{{Path: "/code/api", Name: "api"}}}
+		scanner := &mockProjectScanner{store: store, err: fmt.Errorf("boom")}
+		m := tui.New(&mockSessionLister{sessions: sessions}, tui.WithProjectStore(store), tui.WithProjectScanner(scanner))
+
+		model := runCmd(m, m.Init())
+
+		if view := model.View(); !strings.Contains(view, "api") {
+			t.Errorf("expected projects to stay listed, got:\n%s", view)
+		}
+	})
+
+	t.Run("scan also runs from the project picker", func(t *testing.T) {
+		store := &mockProjectStore{}
+		scanner := &mockProjectScanner{store: store, found: []project.Project{{Path: "/code/discovered", Name: "discovered"}}}
+		m := tui.New(&mockSessionLister{sessions: sessions}, tui.WithProjectStore(store), tui.WithProjectScanner(scanner)).
+			WithCommand([]string{"claude"})
+
+		model := runCmd(m, m.Init())
+
+		if view := model.View(); !strings.Contains(view, "discovered") {
+			t.Errorf("expected scanned project in picker, got:\n%s", view)
+		}
+	})
+}
+
+// mockGitStatus implements tui.GitStatusSource, recording every lookup.
+type mockGitStatus struct {
+	mu       sync.Mutex
+	statuses map[string]gitstatus.Status
+	looked   [][]string
+}
+
+func (m *mockGitStatus) Lookup(dirs []string) map[string]gitstatus.Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.looked = append(m.looked, dirs)
+	found := make(map[string]gitstatus.Status)
+	for _, dir := range dirs {
+		if status, ok := m.statuses[dir]; ok {
+			found[dir] = status
+		}
+	}
+	return found
+}
+
+func TestGitStatus(t *testing.T) {
+	sessions := []tmux.Session{
+		{Name: "api-x1", Windows: 1, Path: "/code/api/cmd"},
+		{Name: "scratch", Windows: 1, Path: "/tmp"},
+	}
+	source := func() *mockGitStatus {
+		return &mockGitStatus{statuses: map[string]gitstatus.Status{
+			"/code/api": {Branch: "main", Dirty: true, Behind: 2},
+			"/code/web": {Branch: "feature", Stashes: 1},
+		}}
+	}
+
+	t.Run("shows status on projects and registered sessions", func(t *testing.T) {
+		store := &mockProjectStore{projects: []project.Project{
+			{Path: "/code/api", Name: "api"},
+			{Path: "/code/web", Name: "web"},
+			{Path: "/code/notes", Name: "notes"},
+		}}
+		reg := &mockSessionRegistry{entries: map[string]registry.Entry{
+			"api-x1": {ProjectPath: "/code/api", ProjectName: "api"},
+		}}
+		m := tui.New(&mockSessionLister{sessions: sessions},
+			tui.WithProjectStore(store), tui.WithSessionRegistry(reg), tui.WithGitStatus(source()))
+
+		view := runCmd(m, m.Init()).View()
+
+		for _, want := range []string{"api-x1  1 window  api  main* ↓2", "api  /code/api  main* ↓2", "web  /code/web  feature ⚑1"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("view missing %q:\n%s", want, view)
+			}
+		}
+		for _, line := range strings.Split(view, "\n") {
+			if strings.Contains(line, "scratch") && strings.Contains(line, "main") {
+				t.Errorf("session outside a repository shows a status: %q", line)
+			}
+			if strings.Contains(line, "notes") && strings.Contains(line, "main") {
+				t.Errorf("project outside a repository shows a status: %q", line)
+			}
+		}
+	})
+
+	t.Run("refresh checks statuses again", func(t *testing.T) {
+		src := source()
+		m := tui.New(&mockSessionLister{sessions: sessions}, tui.WithGitStatus(src), tui.WithRefreshInterval(time.Millisecond))
+		batch, ok := m.Init()().(tea.BatchMsg)
+		if !ok || len(batch) != 2 {
+			t.Fatalf("expected fetch and refresh tick from Init, got %v", batch)
+		}
+		var model tea.Model = m
+		model, _ = model.Update(batch[0]())
+
+		src.statuses["/code/api/cmd"] = gitstatus.Status{Branch: "hotfix"}
+		model, cmd := model.Update(batch[1]())
+		model, cmd = model.Update(cmd())
+		refreshed, ok := cmd().(tea.BatchMsg)
+		if !ok {
+			t.Fatalf("expected the next tick and a status lookup after a refresh, got %T", refreshed)
+		}
+		for _, c := range refreshed {
+			model, _ = model.Update(c())
+		}
+
+		if view := model.View(); !strings.Contains(view, "hotfix") {
+			t.Errorf("expected refreshed status, got:\n%s", view)
+		}
+	})
+
+	t.Run("picker shows status in command-pending mode", func(t *testing.T) {
+		store := &mockProjectStore{projects: []project.Project{{Path: "/code/api", Name: "api"}}}
+		m := tui.New(&mockSessionLister{sessions: sessions},
+			tui.WithProjectStore(store), tui.WithGitStatus(source())).
+			WithCommand([]string{"claude"})
+
+		if view := runCmd(m, m.Init()).View(); !strings.Contains(view, "api  main* ↓2") {
+			t.Errorf("expected status in picker, got:\n%s", view)
+		}
+	})
+}
+
+// mockWorktreeManager implements tui.WorktreeManager for testing.
+type mockWorktreeManager struct {
+	worktrees map[string][]worktree.Worktree
+	removed   []string
+	removeErr error
+}
+
+func (m *mockWorktreeManager) Lookup(dirs []string) map[string][]worktree.Worktree {
+	return m.worktrees
+}
+
+func (m *mockWorktreeManager) IsWorktree(path string) bool {
+	for _, linked := range m.worktrees {
+		for _, w := range linked {
+			if w.Path == path {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *mockWorktreeManager) Remove(path string) error {
+	m.removed = append(m.removed, path)
+	return m.removeErr
+}
+
+func TestWorktrees(t *testing.T) {
+	newModel := func(manager *mockWorktreeManager) tea.Model {
+		store := &mockProjectStore{projects: []project.Project{
+			{Path: "/code/api", Name: "api"},
+			{Path: "/code/web", Name: "web"},
+		}}
+		m := tui.New(&mockSessionLister{}, tui.WithProjectStore(store), tui.WithWorktrees(manager))
+		return runCmd(m, m.Init())
+	}
+	manager := func() *mockWorktreeManager {
+		return &mockWorktreeManager{worktrees: map[string][]worktree.Worktree{
+			"/code/api": {{Path: "/code/api-feature", Branch: "feature"}},
+		}}
+	}
+
+	t.Run("lists worktrees under their repository", func(t *testing.T) {
+		view := newModel(manager()).View()
+
+		api := strings.Index(view, "api  /code/api")
+		feature := strings.Index(view, "  └ api@feature  /code/api-feature")
+		web := strings.Index(view, "web  /code/web")
+		if api < 0 || feature < api || web < feature {
+			t.Errorf("expected api, its worktree, then web:\n%s", view)
+		}
+	})
+
+	t.Run("enter on a worktree opens a session there", func(t *testing.T) {
+		creator := &mockSessionCreator{sessionName: "api@feature-x1"}
+		store := &mockProjectStore{projects: []project.Project{{Path: "/code/api", Name: "api"}}}
+		m := tui.New(&mockSessionLister{}, tui.WithProjectStore(store), tui.WithWorktrees(manager()), tui.WithSessionCreator(creator))
+		model := runCmd(m, m.Init())
+
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if cmd == nil {
+			t.Fatal("expected a create command")
+		}
+		cmd()
+		if creator.createdDir != "/code/api-feature" {
+			t.Errorf("created in %q, want /code/api-feature", creator.createdDir)
+		}
+	})
+
+	t.Run("removing a worktree offers to delete it", func(t *testing.T) {
+		wm := manager()
+		model := newModel(wm)
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+
+		if view := model.View(); !strings.Contains(view, "Remove project 'api@feature'? (y/n, w to also delete the worktree)") {
+			t.Fatalf("expected worktree removal prompt:\n%s", view)
+		}
+		model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+		if len(wm.removed) != 1 || wm.removed[0] != "/code/api-feature" {
+			t.Errorf("removed %v, want [/code/api-feature]", wm.removed)
+		}
+	})
+	t.Run("failed worktree deletion keeps the project and shows the error", func(t *testing.T) {
+		wm := manager()
+		wm.removeErr = fmt.Errorf("worktree is dirty")
+		store := &mockProjectStore{projects: []project.Project{{Path: "/code/api", Name: "api"}}}
+		m := tui.New(&mockSessionLister{}, tui.WithProjectStore(store), tui.WithWorktrees(wm))
+		model := runCmd(m, m.Init())
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+
+		if store.removedPath != "" {
+			t.Errorf("removed project %q, want it kept", store.removedPath)
+		}
+		if view := model.View(); !strings.Contains(view, "failed to delete worktree: worktree is dirty") {
+			t.Errorf("expected the error in the status line:\n%s", view)
+		}
+	})
+
+	t.Run("failed project removal shows the error", func(t *testing.T) {
+		store := &mockProjectStore{
+			projects:  []project.Project{{Path: "/code/api", Name: "api"}},
+			removeErr: fmt.Errorf("disk full"),
+		}
+		m := tui.New(&mockSessionLister{}, tui.WithProjectStore(store))
+		model := runCmd(m, m.Init())
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+		model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+		model = runCmd(model, cmd)
+
+		if view := model.View(); !strings.Contains(view, "failed to remove project: disk full") {
+			t.Errorf("expected the error in the status line:\n%s", view)
+		}
+	})
+}
+
+type mockSessionRestorer struct {
+	saved    []tmux.Session
+	err      error
+	restored []string
+}
+
+func (m *mockSessionRestorer) SavedSessions() ([]tmux.Session, error) {
+	return m.saved, nil
+}
+
+func (m *mockSessionRestorer) Restore(name string) error {
+	m.restored = append(m.restored, name)
+	return m.err
+}
+
+func TestSessionRestorer(t *testing.T) {
+	saved := func() *mockSessionRestorer {
+		return &mockSessionRestorer{saved: []tmux.Session{
+			{Name: "api", Windows: 2, Exited: true},
+			{Name: "web", Windows: 1, Exited: true},
+		}}
+	}
+
+	t.Run("lists saved sessions that are not running", func(t *testing.T) {
+		lister := &mockSessionLister{sessions: []tmux.Session{{Name: "api", Windows: 3, Attached: true}}}
+		m := tui.New(lister, tui.WithRestorer(saved()))
+
+		msg, ok := m.Init()().(tui.SessionsMsg)
+		if !ok {
+			t.Fatal("expected SessionsMsg")
+		}
+		if len(msg.Sessions) != 2 {
+			t.Fatalf("got %d sessions, want 2: %+v", len(msg.Sessions), msg.Sessions)
+		}
+		if msg.Sessions[0].Exited || msg.Sessions[0].Windows != 3 {
+			t.Errorf("running api = %+v, want the live session", msg.Sessions[0])
+		}
+		if msg.Sessions[1].Name != "web" || !msg.Sessions[1].Exited {
+			t.Errorf("saved session = %+v, want exited web", msg.Sessions[1])
+		}
+	})
+
+	t.Run("enter on a saved session restores then selects it", func(t *testing.T) {
+		restorer := saved()
+		m := tui.New(&mockSessionLister{}, tui.WithRestorer(restorer))
+		model := runCmd(m, m.Init())
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if cmd == nil {
+			t.Fatal("expected a restore command")
+		}
+		msg := cmd()
+		if len(restorer.restored) != 1 || restorer.restored[0] != "api" {
+			t.Errorf("restored %v, want [api]", restorer.restored)
+		}
+		created, ok := msg.(tui.SessionCreatedMsg)
+		if !ok || created.SessionName != "api" {
+			t.Fatalf("got %#v, want SessionCreatedMsg for api", msg)
+		}
+		model, _ = model.Update(created)
+		if got := model.(tui.Model).Selected(); got != "api" {
+			t.Errorf("Selected() = %q, want api", got)
+		}
+	})
+
+	t.Run("failed restore stays in the list", func(t *testing.T) {
+		restorer := saved()
+		restorer.err = fmt.Errorf("boom")
+		m := tui.New(&mockSessionLister{}, tui.WithRestorer(restorer))
+		model := runCmd(m, m.Init())
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		model, _ = model.Update(cmd())
+		if got := model.(tui.Model).Selected(); got != "" {
+			t.Errorf("Selected() = %q, want none", got)
+		}
+	})
+
+	t.Run("exited sessions cannot be killed, renamed, marked or detached", func(t *testing.T) {
+		killer := &mockSessionKiller{}
+		renamer := &mockSessionRenamer{}
+		detacher := &mockSessionDetacher{}
+		m := tui.New(&mockSessionLister{}, tui.WithRestorer(saved()),
+			tui.WithKiller(killer), tui.WithRenamer(renamer), tui.WithDetacher(detacher))
+		model := runCmd(m, m.Init())
+
+		for _, key := range []tea.KeyMsg{
+			{Type: tea.KeyRunes, Runes: []rune{'K'}},
+			{Type: tea.KeyRunes, Runes: []rune{'y'}},
+			{Type: tea.KeyRunes, Runes: []rune{'R'}},
+			{Type: tea.KeySpace},
+			{Type: tea.KeyRunes, Runes: []rune{'D'}},
+			{Type: tea.KeyRunes, Runes: []rune{'K'}},
+			{Type: tea.KeyRunes, Runes: []rune{'y'}},
+		} {
+			var cmd tea.Cmd
+			model, cmd = model.Update(key)
+			model = runCmd(model, cmd)
+		}
+
+		if len(killer.killed) != 0 {
+			t.Errorf("killed %v, want none", killer.killed)
+		}
+		if len(detacher.detached) != 0 {
+			t.Errorf("detached %v, want none", detacher.detached)
+		}
+		if view := model.View(); strings.Contains(view, "Rename:") {
+			t.Errorf("expected no rename prompt, got:\n%s", view)
+		}
+	})
+
+	t.Run("killed session is not listed again as exited", func(t *testing.T) {
+		lister := &mockSessionLister{sessions: []tmux.Session{{Name: "api", Windows: 1}}}
+		m := tui.New(lister, tui.WithRestorer(saved()), tui.WithKiller(&mockSessionKiller{}))
+		model := runCmd(m, m.Init())
+
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'K'}})
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+		lister.sessions = nil
+
+		msg, ok := cmd().(tui.SessionsMsg)
+		if !ok {
+			t.Fatal("expected SessionsMsg")
+		}
+		if len(msg.Sessions) != 1 || msg.Sessions[0].Name != "web" {
+			t.Errorf("sessions = %+v, want only exited web", msg.Sessions)
+		}
+	})
+
+	t.Run("enter on a running session attaches without restoring", func(t *testing.T) {
+		restorer := saved()
+		lister := &mockSessionLister{sessions: []tmux.Session{{Name: "api", Windows: 1}}}
+		m := tui.New(lister, tui.WithRestorer(restorer))
+		model := runCmd(m, m.Init())
+
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if got := model.(tui.Model).Selected(); got != "api" {
+			t.Errorf("Selected() = %q, want api", got)
+		}
+		if len(restorer.restored) != 0 {
+			t.Errorf("restored %v, want nothing", restorer.restored)
+		}
+	})
+}
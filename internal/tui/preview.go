@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// minPreviewWidth is the narrowest terminal the preview panel is shown in.
+// Below it the list gets the full width.
+const minPreviewWidth = 100
+
+// PaneCapturer defines the interface for capturing a session's active pane.
+type PaneCapturer interface {
+	CapturePane(name string) (string, error)
+}
+
+// WithPaneCapturer enables the preview panel, which shows the active pane of
+// the highlighted session.
+func WithPaneCapturer(c PaneCapturer) Option {
+	return func(m *Model) {
+		m.paneCapturer = c
+	}
+}
+
+// paneCapturedMsg carries the captured contents of a session's active pane.
+type paneCapturedMsg struct {
+	name    string
+	content string
+	err     error
+}
+
+// previewVisible reports whether the preview panel is shown: it needs a pane
+// capturer, must not be toggled off, and the terminal must be wide enough.
+func (m Model) previewVisible() bool {
+	return m.paneCapturer != nil && !m.previewHidden && m.width >= minPreviewWidth
+}
+
+// togglePreview shows or hides the preview panel. Showing it again captures
+// the highlighted session afresh.
+func (m Model) togglePreview() Model {
+	m.previewHidden = !m.previewHidden
+	m.previewName = ""
+	m.previewText = ""
+	m.previewErr = nil
+	return m
+}
+
+// syncPreview starts capturing the highlighted session's pane when the preview
+// is visible and shows a different session. The capture runs asynchronously
+// alongside cmd, so moving the cursor never waits on tmux.
+func (m Model) syncPreview(cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	if !m.previewVisible() {
+		return m, cmd
+	}
+	name := ""
+	if item := m.currentItem(); item.kind == itemSession {
+		name = item.session.Name
+	}
+	if name == m.previewName {
+		return m, cmd
+	}
+	m.previewName = name
+	m.previewText = ""
+	m.previewErr = nil
+	if name == "" {
+		return m, cmd
+	}
+	return m, tea.Batch(cmd, m.capturePane(name))
+}
+
+// capturePane returns a command that captures the active pane of name.
+func (m Model) capturePane(name string) tea.Cmd {
+	capturer := m.paneCapturer
+	return func() tea.Msg {
+		content, err := capturer.CapturePane(name)
+		return paneCapturedMsg{name: name, content: content, err: err}
+	}
+}
+
+// applyCapture stores a capture result unless the cursor has since moved to
+// another session.
+func (m Model) applyCapture(msg paneCapturedMsg) Model {
+	if msg.name == m.previewName {
+		m.previewText = msg.content
+		m.previewErr = msg.err
+	}
+	return m
+}
+
+// withPreview lays the preview panel out to the right of the list. The list
+// takes half the terminal and lines too long for it are cut short; the panel
+// shows the last lines of the pane that fit in height.
+func (m Model) withPreview(list string, height int) string {
+	listWidth := m.width / 2
+	panel := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(m.styles.detail.GetForeground()).
+		PaddingLeft(1).
+		MarginLeft(1)
+	contentWidth := m.width - listWidth - panel.GetHorizontalFrameSize()
+
+	var b strings.Builder
+	switch {
+	case m.previewName == "":
+		b.WriteString(m.styles.detail.Render("No session highlighted"))
+	case m.previewErr != nil:
+		b.WriteString(m.styles.header.Render(m.previewName))
+		b.WriteString("\n")
+		b.WriteString(m.styles.detail.Render("Preview unavailable"))
+	default:
+		b.WriteString(m.styles.header.Render(m.previewName))
+		lines := strings.Split(strings.ReplaceAll(m.previewText, "\t", "    "), "\n")
+		if keep := height - 1; len(lines) > keep {
+			lines = lines[len(lines)-max(keep, 0):]
+		}
+		for _, line := range lines {
+			b.WriteString("\n")
+			b.WriteString(line)
+		}
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().MaxWidth(listWidth).Render(list),
+		panel.Height(height).Render(lipgloss.NewStyle().MaxWidth(contentWidth).Render(b.String())),
+	)
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// WithRefreshInterval keeps the session list live by re-listing sessions every
+// interval, so sessions created, renamed or killed elsewhere show up without
+// restarting. Zero disables refreshing.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(m *Model) {
+		m.refreshInterval = interval
+	}
+}
+
+// refreshTickMsg asks the model to re-list sessions in the background.
+type refreshTickMsg struct{}
+
+// sessionsRefreshedMsg carries the result of a background refresh. Unlike
+// SessionsMsg it never quits on error or interrupts a prompt.
+type sessionsRefreshedMsg SessionsMsg
+
+// scheduleRefresh returns a command that ticks after the refresh interval.
+// The next tick is only scheduled once a refresh completes, so slow tmux
+// calls never pile up.
+func (m Model) scheduleRefresh() tea.Cmd {
+	return tea.Tick(m.refreshInterval, func(time.Time) tea.Msg {
+		return refreshTickMsg{}
+	})
+}
+
+// refreshSessions returns a command that re-lists sessions.
+func (m Model) refreshSessions() tea.Cmd {
+	return func() tea.Msg {
+		return sessionsRefreshedMsg(m.fetchSessions())
+	}
+}
+
+// key identifies a row independently of its position in the list.
+func (i listItem) key() string {
+	switch i.kind {
+	case itemSession:
+		return "session:" + i.session.Name
+	case itemProject:
+		return "project:" + i.project.Path
+	default:
+		return "browse"
+	}
+}
+
+// applyRefresh replaces the session list with a background refresh, keeping
+// the cursor on the same row by identity rather than index, and re-checks git
+// statuses. A failed refresh keeps the current list.
+func (m Model) applyRefresh(msg sessionsRefreshedMsg) (Model, tea.Cmd) {
+	next := m.scheduleRefresh()
+	if msg.Err != nil {
+		return m, next
+	}
+
+	current := m.currentItem().key()
+	m.sessions = msg.Sessions
+	m.sessionProjects = msg.Projects
+	m.sessions = m.filteredSessions()
+	m.pruneMarks()
+	m.prunePendingKill()
+	m.cursorTo(current)
+
+	// Re-capture the previewed session so the panel stays live too. The old
+	// content stays on screen until the new capture arrives.
+	if m.previewVisible() && m.previewName != "" && m.currentItem().key() == "session:"+m.previewName {
+		return m, tea.Batch(next, m.capturePane(m.previewName), m.lookupGitStatus())
+	}
+	return m, tea.Batch(next, m.lookupGitStatus())
+}
+
+// cursorTo moves the cursor to the row with the given key, or keeps it within
+// bounds when that row is gone.
+func (m *Model) cursorTo(key string) {
+	for i, item := range m.items() {
+		if item.key() == key {
+			m.cursor = i
+			return
+		}
+	}
+	m.clampCursor()
+}
+
+// prunePendingKill drops sessions that have disappeared from a pending kill
+// confirmation, cancelling it when none remain.
+func (m *Model) prunePendingKill() {
+	if !m.confirmKill {
+		return
+	}
+	m.pendingKill = slices.DeleteFunc(m.pendingKill, func(name string) bool {
+		return !slices.ContainsFunc(m.sessions, func(s tmux.Session) bool { return s.Name == name })
+	})
+	if len(m.pendingKill) == 0 {
+		m.confirmKill = false
+		m.pendingKill = nil
+	}
+}
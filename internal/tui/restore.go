@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"maps"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// SessionRestorer defines the interface for listing the sessions of a saved
+// snapshot and rebuilding one of them.
+type SessionRestorer interface {
+	SavedSessions() ([]tmux.Session, error)
+	Restore(name string) error
+}
+
+// WithRestorer lists sessions from the saved snapshot that are no longer
+// running alongside the live ones, marked as exited. Choosing one restores it
+// before attaching.
+func WithRestorer(r SessionRestorer) Option {
+	return func(m *Model) {
+		m.sessionRestorer = r
+	}
+}
+
+// withSaved appends the saved sessions that are not currently running and
+// were not killed from this list. Snapshot errors leave the list as it is.
+func (m Model) withSaved(sessions []tmux.Session) []tmux.Session {
+	saved, err := m.sessionRestorer.SavedSessions()
+	if err != nil {
+		return sessions
+	}
+	running := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		running[s.Name] = true
+	}
+	for _, s := range saved {
+		if !running[s.Name] && !m.killedSaved[s.Name] {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions
+}
+
+// forgetSaved stops listing the saved sessions of names once they are
+// killed, so a killed session does not reappear as exited. The set is
+// replaced rather than changed, since pending fetches may still read it.
+func (m *Model) forgetSaved(names []string) {
+	killed := maps.Clone(m.killedSaved)
+	if killed == nil {
+		killed = make(map[string]bool, len(names))
+	}
+	for _, name := range names {
+		killed[name] = true
+	}
+	m.killedSaved = killed
+}
+
+// currentLiveSession returns the session under the cursor unless it is an
+// exited one from the snapshot, which can only be restored.
+func (m Model) currentLiveSession() (tmux.Session, bool) {
+	item := m.currentItem()
+	if item.kind != itemSession || item.session.Exited {
+		return tmux.Session{}, false
+	}
+	return item.session, true
+}
+
+// restoreSession returns a command that rebuilds the named session from the
+// snapshot, then selects it like a newly created one.
+func (m Model) restoreSession(name string) tea.Cmd {
+	restorer := m.sessionRestorer
+	return func() tea.Msg {
+		if err := restorer.Restore(name); err != nil {
+			return sessionCreateErrMsg{Err: err}
+		}
+		return SessionCreatedMsg{SessionName: name}
+	}
+}
@@ -0,0 +1,41 @@
+package tui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// ProjectScanner discovers projects on disk and adds new ones to the store.
+type ProjectScanner interface {
+	// Scan returns how many projects were added.
+	Scan() (int, error)
+}
+
+// WithProjectScanner scans for new projects in the background when the TUI
+// starts, reloading the project list if any were added.
+func WithProjectScanner(s ProjectScanner) Option {
+	return func(m *Model) {
+		m.projectScanner = s
+	}
+}
+
+// projectsScannedMsg carries the result of a background project scan.
+type projectsScannedMsg struct {
+	added int
+	err   error
+}
+
+// scanProjects returns a command that runs the project scanner.
+func (m Model) scanProjects() tea.Cmd {
+	scanner := m.projectScanner
+	return func() tea.Msg {
+		added, err := scanner.Scan()
+		return projectsScannedMsg{added: added, err: err}
+	}
+}
+
+// applyScan reloads the project list when a scan added projects. Scan
+// errors are ignored: discovery is best-effort and never blocks the picker.
+func (m Model) applyScan(msg projectsScannedMsg) tea.Cmd {
+	if msg.err != nil || msg.added == 0 || m.projectStore == nil {
+		return nil
+	}
+	return m.loadProjects()
+}
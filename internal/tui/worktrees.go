@@ -0,0 +1,34 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/ui"
+)
+
+// WorktreeManager lists and removes git worktrees.
+type WorktreeManager = ui.WorktreeManager
+
+// WithWorktrees lists each repository's linked worktrees under its project
+// and offers to delete a worktree when its project is removed.
+func WithWorktrees(w WorktreeManager) Option {
+	return func(m *Model) {
+		m.worktreeManager = w
+	}
+}
+
+// lookupWorktrees returns a command that lists the linked worktrees of every
+// remembered project.
+func (m Model) lookupWorktrees() tea.Cmd {
+	paths := make([]string, len(m.projects))
+	for i, p := range m.projects {
+		paths[i] = p.Path
+	}
+	return ui.LookupWorktrees(m.worktreeManager, paths)
+}
+
+// nestedProjects returns the projects with each repository's linked
+// worktrees under it, and the set of worktree paths.
+func (m Model) nestedProjects() ([]project.Project, map[string]bool) {
+	return ui.NestWorktrees(m.projects, m.worktrees)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/leeovery/portal/internal/browser"
 	"github.com/leeovery/portal/internal/fuzzy"
 )
@@ -68,6 +69,7 @@ type FileBrowserModel struct {
 	resolveGit  GitRootResolver
 	aliasPrompt bool
 	aliasInput  string
+	matchStyle  lipgloss.Style
 }
 
 // defaultPathChecker uses os.Stat to verify a directory exists.
@@ -79,9 +81,10 @@ func defaultPathChecker(path string) error {
 // NewFileBrowser creates a FileBrowserModel starting at the given path.
 func NewFileBrowser(startPath string, lister DirLister) FileBrowserModel {
 	m := FileBrowserModel{
-		path:      startPath,
-		lister:    lister,
-		checkPath: defaultPathChecker,
+		path:       startPath,
+		lister:     lister,
+		checkPath:  defaultPathChecker,
+		matchStyle: NewMatchStyle(""),
 	}
 	m.loadEntries()
 	return m
@@ -90,9 +93,10 @@ func NewFileBrowser(startPath string, lister DirLister) FileBrowserModel {
 // NewFileBrowserWithChecker creates a FileBrowserModel with a custom path checker for testability.
 func NewFileBrowserWithChecker(startPath string, lister DirLister, checker PathChecker) FileBrowserModel {
 	m := FileBrowserModel{
-		path:      startPath,
-		lister:    lister,
-		checkPath: checker,
+		path:       startPath,
+		lister:     lister,
+		checkPath:  checker,
+		matchStyle: NewMatchStyle(""),
 	}
 	m.loadEntries()
 	return m
@@ -106,11 +110,29 @@ func NewFileBrowserWithAlias(startPath string, lister DirLister, checker PathChe
 		checkPath:  checker,
 		aliasStore: aliasStore,
 		resolveGit: resolveGit,
+		matchStyle: NewMatchStyle(""),
 	}
 	m.loadEntries()
 	return m
 }
 
+// WithShowHidden returns a copy of the FileBrowserModel with hidden directories
+// shown or hidden, reloading the listing. The . key still toggles the setting.
+func (m FileBrowserModel) WithShowHidden(show bool) FileBrowserModel {
+	if m.showHidden != show {
+		m.showHidden = show
+		m.loadEntries()
+	}
+	return m
+}
+
+// WithMatchStyle returns a copy of the FileBrowserModel that highlights the
+// characters of each entry that match the filter in style.
+func (m FileBrowserModel) WithMatchStyle(style lipgloss.Style) FileBrowserModel {
+	m.matchStyle = style
+	return m
+}
+
 // loadEntries refreshes the directory listing for the current path.
 func (m *FileBrowserModel) loadEntries() {
 	entries, err := m.lister.ListDirectories(m.path, m.showHidden)
@@ -121,7 +143,7 @@ func (m *FileBrowserModel) loadEntries() {
 	m.entries = entries
 }
 
-// filteredEntries returns the directory entries that match the current filter text.
+// filteredEntries returns the directory entries that match the current filter text, best match first.
 func (m FileBrowserModel) filteredEntries() []browser.DirEntry {
 	return fuzzy.Filter(m.entries, m.filterText, func(e browser.DirEntry) string { return e.Name })
 }
@@ -174,7 +196,11 @@ func (m FileBrowserModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeySpace:
-		if m.cursor == 0 {
+		// While filtering, space separates filter terms.
+		if m.filterText != "" {
+			m.filterText += " "
+			m.cursor = 0
+		} else if m.cursor == 0 {
 			return m.handleSelectCurrentDir()
 		}
 
@@ -350,7 +376,7 @@ func (m FileBrowserModel) View() string {
 		if i+1 == m.cursor { // +1 because index 0 is the "." entry
 			cursor = "> "
 		}
-		fmt.Fprintf(&b, "%s%s\n", cursor, entry.Name)
+		fmt.Fprintf(&b, "%s%s\n", cursor, HighlightMatch(entry.Name, m.filterText, lipgloss.NewStyle(), m.matchStyle))
 	}
 
 	if m.aliasPrompt {
@@ -321,6 +321,25 @@ func TestFileBrowser_TypingFiltersDirectoryListing(t *testing.T) {
 	}
 }
 
+func TestFileBrowser_SpaceSeparatesFilterTerms(t *testing.T) {
+	m := newTestBrowser("/home/user/code", standardEntries())
+
+	var model tea.Model = m
+	model, cmd := sendBrowserKeys(model, keyRune('a')).Update(keySpace())
+	if cmd != nil {
+		t.Fatal("space while filtering should not select the directory")
+	}
+	model = sendBrowserKeys(model, keyRune('!'), keyRune('g'))
+
+	view := model.View()
+	if !strings.Contains(view, "alpha") || !strings.Contains(view, "beta") {
+		t.Errorf("expected entries containing a but not g:\n%s", view)
+	}
+	if strings.Contains(view, "gamma") {
+		t.Errorf("negated term should exclude 'gamma':\n%s", view)
+	}
+}
+
 func TestFileBrowser_BackspaceRemovesFilterChar(t *testing.T) {
 	m := newTestBrowser("/home/user/code", standardEntries())
 
@@ -520,6 +539,30 @@ func TestFileBrowser_DotKeyTogglesHiddenVisibility(t *testing.T) {
 	}
 }
 
+func TestFileBrowser_WithShowHiddenStartsWithHiddenVisible(t *testing.T) {
+	entries := map[string][]browser.DirEntry{
+		"/home/user/code": {{Name: "alpha"}},
+	}
+	hidden := map[string][]browser.DirEntry{
+		"/home/user/code": {{Name: ".hidden"}},
+	}
+	m := ui.NewFileBrowser("/home/user/code", &mockDirLister{entries: entries, hiddenEntries: hidden}).WithShowHidden(true)
+
+	view := m.View()
+	if !strings.Contains(view, ".hidden") {
+		t.Errorf("hidden dirs should be visible initially:\n%s", view)
+	}
+
+	// Press "." to toggle showHidden off
+	var model tea.Model = m
+	model = sendBrowserKeys(model, keyRune('.'))
+
+	view = model.View()
+	if strings.Contains(view, ".hidden") {
+		t.Errorf("hidden dirs should be hidden after toggle:\n%s", view)
+	}
+}
+
 func TestFileBrowser_SpaceOnDotEntryEmitsSelection(t *testing.T) {
 	m := newTestBrowser("/home/user/code", standardEntries())
 
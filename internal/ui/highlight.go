@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/leeovery/portal/internal/fuzzy"
+)
+
+// NewMatchStyle returns the style for characters that matched a filter:
+// bold and underlined, in color unless it is empty. Under NO_COLOR or on
+// terminals without colour support lipgloss drops the styling entirely, so
+// names render as plain text.
+func NewMatchStyle(color string) lipgloss.Style {
+	s := lipgloss.NewStyle().Bold(true).Underline(true)
+	if color != "" {
+		s = s.Foreground(lipgloss.Color(color))
+	}
+	return s
+}
+
+// Highlight renders text in base, with the runes at positions (rune indices,
+// as reported by the fuzzy package) in match layered over base.
+func Highlight(text string, positions []int, base, match lipgloss.Style) string {
+	if len(positions) == 0 {
+		return base.Render(text)
+	}
+
+	runes := []rune(text)
+	matched := make([]bool, len(runes))
+	for _, p := range positions {
+		if p >= 0 && p < len(runes) {
+			matched[p] = true
+		}
+	}
+	match = match.Inherit(base)
+
+	var b strings.Builder
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && matched[end] == matched[start] {
+			end++
+		}
+		style := base
+		if matched[start] {
+			style = match
+		}
+		b.WriteString(style.Render(string(runes[start:end])))
+		start = end
+	}
+	return b.String()
+}
+
+// HighlightMatch renders name in base with the characters that matched
+// filter, in the fuzzy package's extended syntax, highlighted in match.
+// Tag terms are ignored, since they match tags rather than the name.
+// Without a filter, or when name does not match, it is rendered in base alone.
+func HighlightMatch(name, filter string, base, match lipgloss.Style) string {
+	if filter == "" {
+		return base.Render(name)
+	}
+	res, ok := fuzzy.ParseQuery(filter).WithoutTags().Match(name)
+	if !ok {
+		return base.Render(name)
+	}
+	return Highlight(name, res.Positions, base, match)
+}
@@ -0,0 +1,81 @@
+package ui_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/leeovery/portal/internal/browser"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/ui"
+)
+
+// upper stands in for a highlight style: it shows which characters were
+// styled even though tests render without colour.
+var upper = lipgloss.NewStyle().Transform(strings.ToUpper)
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		positions []int
+		want      string
+	}{
+		{"no positions", "my-api", nil, "my-api"},
+		{"single run", "my-api", []int{3, 4, 5}, "my-API"},
+		{"scattered", "my-api", []int{0, 3, 5}, "My-ApI"},
+		{"rune indices", "über-café", []int{0, 8}, "Über-cafÉ"},
+		{"out of range ignored", "api", []int{0, 7}, "Api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ui.Highlight(tt.text, tt.positions, lipgloss.NewStyle(), upper); got != tt.want {
+				t.Errorf("Highlight() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlightMatch(t *testing.T) {
+	base := lipgloss.NewStyle()
+	if got := ui.HighlightMatch("web-api", "api", base, upper); got != "web-API" {
+		t.Errorf("HighlightMatch() = %q, want %q", got, "web-API")
+	}
+	if got := ui.HighlightMatch("web-api", "", base, upper); got != "web-api" {
+		t.Errorf("empty filter should not highlight, got %q", got)
+	}
+	if got := ui.HighlightMatch("web-api", "xyz", base, upper); got != "web-api" {
+		t.Errorf("non-matching filter should not highlight, got %q", got)
+	}
+}
+
+func TestMatchStyleWithoutColour(t *testing.T) {
+	// Tests run without a terminal, as under NO_COLOR: styling must not
+	// leave escape codes or alter the text.
+	if got := ui.Highlight("api-gateway", []int{0, 1, 2}, lipgloss.NewStyle(), ui.NewMatchStyle("214")); got != "api-gateway" {
+		t.Errorf("Highlight() = %q, want plain text", got)
+	}
+}
+
+func TestFilterHighlighting(t *testing.T) {
+	t.Run("project picker", func(t *testing.T) {
+		store := &mockProjectStore{projects: []project.Project{{Path: "/code/web-api", Name: "web-api"}}}
+		m := ui.NewProjectPicker(store).WithMatchStyle(upper).WithFilter("api")
+		updated, _ := m.Update(projectsLoaded(store.projects))
+
+		if view := updated.View(); !strings.Contains(view, "> web-API") {
+			t.Errorf("expected matched characters highlighted, got:\n%s", view)
+		}
+	})
+
+	t.Run("file browser", func(t *testing.T) {
+		b := newTestBrowser("/code", map[string][]browser.DirEntry{
+			"/code": {{Name: "portal"}, {Name: "web-api"}},
+		}).WithMatchStyle(upper)
+		model := sendKeys(b, keyRune('a'), keyRune('p'), keyRune('i'))
+
+		if view := model.View(); !strings.Contains(view, "web-API") {
+			t.Errorf("expected matched characters highlighted, got:\n%s", view)
+		}
+	})
+}
@@ -0,0 +1,254 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/leeovery/portal/internal/project"
+)
+
+// EditOutcome reports what happened to a ProjectEditModel after a key press.
+type EditOutcome int
+
+const (
+	// EditContinue means the form is still open.
+	EditContinue EditOutcome = iota
+	// EditSaved means the changes were persisted and the form closed.
+	EditSaved
+	// EditCancelled means the form was closed without saving.
+	EditCancelled
+)
+
+// ProjectEditModel is the form for editing a project's name, aliases and tags.
+// It is shared by the project picker and the unified session list.
+type ProjectEditModel struct {
+	editor      ProjectEditor
+	aliasStore  AliasEditor
+	project     project.Project
+	name        string
+	aliases     []string // current alias names for the project's directory
+	removed     []string // alias names removed during this edit session
+	newAlias    string   // text input for adding a new alias
+	tags        string   // text input for the project's tags, space or comma separated
+	focus       editField
+	aliasCursor int
+	err         string
+}
+
+// NewProjectEdit opens an edit form for p, loading the aliases that point
+// at the project's directory.
+func NewProjectEdit(p project.Project, editor ProjectEditor, aliasStore AliasEditor) ProjectEditModel {
+	m := ProjectEditModel{
+		editor:     editor,
+		aliasStore: aliasStore,
+		project:    p,
+		name:       p.Name,
+		tags:       strings.Join(p.Tags, " "),
+		focus:      editFieldName,
+	}
+
+	// Load aliases matching this project's directory
+	allAliases, err := aliasStore.Load()
+	if err == nil {
+		var matching []string
+		for name, path := range allAliases {
+			if path == p.Path {
+				matching = append(matching, name)
+			}
+		}
+		m.aliases = matching
+	}
+
+	return m
+}
+
+// Update handles a key press and reports whether the form is still open.
+func (m ProjectEditModel) Update(msg tea.KeyMsg) (ProjectEditModel, EditOutcome) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.err = ""
+		return m, EditCancelled
+
+	case tea.KeyTab:
+		switch m.focus {
+		case editFieldName:
+			m.focus = editFieldAliases
+		case editFieldAliases:
+			m.focus = editFieldTags
+		default:
+			m.focus = editFieldName
+		}
+
+	case tea.KeyEnter:
+		return m.confirm()
+
+	case tea.KeyBackspace:
+		if m.focus == editFieldName {
+			if len(m.name) > 0 {
+				m.name = m.name[:len(m.name)-1]
+			}
+		} else if m.focus == editFieldTags {
+			if len(m.tags) > 0 {
+				m.tags = m.tags[:len(m.tags)-1]
+			}
+		} else if m.aliasCursor == len(m.aliases) {
+			// On Add input
+			if len(m.newAlias) > 0 {
+				m.newAlias = m.newAlias[:len(m.newAlias)-1]
+			}
+		}
+
+	case tea.KeyDown:
+		if m.focus == editFieldAliases && m.aliasCursor < len(m.aliases) {
+			m.aliasCursor++
+		}
+
+	case tea.KeyUp:
+		if m.focus == editFieldAliases && m.aliasCursor > 0 {
+			m.aliasCursor--
+		}
+
+	case tea.KeySpace:
+		// Space separates tags.
+		if m.focus == editFieldTags {
+			m.tags += " "
+		}
+
+	case tea.KeyRunes:
+		text := string(msg.Runes)
+		// In alias area, on an existing alias entry: x removes it
+		if m.focus == editFieldAliases && text == "x" && m.aliasCursor < len(m.aliases) {
+			removed := m.aliases[m.aliasCursor]
+			m.removed = append(m.removed, removed)
+			m.aliases = append(m.aliases[:m.aliasCursor], m.aliases[m.aliasCursor+1:]...)
+			if m.aliasCursor > len(m.aliases) {
+				m.aliasCursor = len(m.aliases)
+			}
+			return m, EditContinue
+		}
+		// In alias area, on Add input: type into new alias
+		if m.focus == editFieldAliases && m.aliasCursor == len(m.aliases) {
+			m.newAlias += text
+			m.err = ""
+			return m, EditContinue
+		}
+		switch m.focus {
+		case editFieldName:
+			m.name += text
+		case editFieldTags:
+			m.tags += text
+		}
+		m.err = ""
+	}
+	return m, EditContinue
+}
+
+// confirm validates and persists the form.
+func (m ProjectEditModel) confirm() (ProjectEditModel, EditOutcome) {
+	name := strings.TrimSpace(m.name)
+	if name == "" {
+		m.err = "Project name cannot be empty"
+		return m, EditContinue
+	}
+
+	// Save project name if changed
+	if name != m.project.Name {
+		if err := m.editor.Rename(m.project.Path, name); err != nil {
+			m.err = "Failed to save project name"
+			return m, EditContinue
+		}
+	}
+
+	// Save tags if changed
+	if tags := project.NormalizeTags([]string{m.tags}); !slices.Equal(tags, m.project.Tags) {
+		if err := m.editor.SetTags(m.project.Path, tags); err != nil {
+			m.err = "Failed to save project tags"
+			return m, EditContinue
+		}
+	}
+
+	// Handle alias removals
+	for _, removed := range m.removed {
+		m.aliasStore.Delete(removed)
+	}
+
+	// Handle new alias addition
+	newAlias := strings.TrimSpace(m.newAlias)
+	if newAlias != "" {
+		// Check for collision
+		allAliases, err := m.aliasStore.Load()
+		if err == nil {
+			if existingPath, ok := allAliases[newAlias]; ok && existingPath != m.project.Path {
+				m.err = fmt.Sprintf("Alias '%s' already exists", newAlias)
+				return m, EditContinue
+			}
+		}
+		m.aliasStore.Set(newAlias, m.project.Path)
+	}
+
+	// Save alias changes
+	if err := m.aliasStore.Save(); err != nil {
+		m.err = "Failed to save aliases"
+		return m, EditContinue
+	}
+
+	m.err = ""
+	return m, EditSaved
+}
+
+// View renders the edit form.
+func (m ProjectEditModel) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Edit project: %s\n\n", m.project.Name)
+
+	nameIndicator := "  "
+	if m.focus == editFieldName {
+		nameIndicator = "> "
+	}
+	fmt.Fprintf(&b, "%sName: %s\n", nameIndicator, m.name)
+
+	b.WriteString("\n")
+
+	aliasIndicator := "  "
+	if m.focus == editFieldAliases {
+		aliasIndicator = "> "
+	}
+	b.WriteString(aliasIndicator + "Aliases:\n")
+
+	if len(m.aliases) == 0 {
+		b.WriteString("    (none)\n")
+	} else {
+		for i, a := range m.aliases {
+			marker := "    "
+			if m.focus == editFieldAliases && m.aliasCursor == i {
+				marker = "  > "
+			}
+			fmt.Fprintf(&b, "%s[x] %s\n", marker, a)
+		}
+	}
+
+	addMarker := "    "
+	if m.focus == editFieldAliases && m.aliasCursor == len(m.aliases) {
+		addMarker = "  > "
+	}
+	fmt.Fprintf(&b, "%sAdd: %s\n", addMarker, m.newAlias)
+
+	b.WriteString("\n")
+
+	tagsIndicator := "  "
+	if m.focus == editFieldTags {
+		tagsIndicator = "> "
+	}
+	fmt.Fprintf(&b, "%sTags: %s\n", tagsIndicator, m.tags)
+
+	if m.err != "" {
+		fmt.Fprintf(&b, "\n  Error: %s\n", m.err)
+	}
+
+	b.WriteString("\n  [Enter] Save  [Esc] Cancel  [Tab] Switch field")
+
+	return b.String()
+}
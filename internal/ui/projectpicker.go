@@ -3,11 +3,15 @@ package ui
 
 import (
 	"fmt"
+	"maps"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/leeovery/portal/internal/fuzzy"
+	"github.com/leeovery/portal/internal/gitstatus"
 	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/worktree"
 )
 
 // ProjectStore defines the interface for loading and cleaning projects.
@@ -17,9 +21,10 @@ type ProjectStore interface {
 	Remove(path string) error
 }
 
-// ProjectEditor defines the interface for renaming projects.
+// ProjectEditor defines the interface for renaming and tagging projects.
 type ProjectEditor interface {
 	Rename(path, newName string) error
+	SetTags(path string, tags []string) error
 }
 
 // AliasEditor defines the interface for managing aliases in edit mode.
@@ -30,6 +35,38 @@ type AliasEditor interface {
 	Save() error
 }
 
+// GitStatusSource looks up the git status of directories. Directories that
+// are not git working trees are left out of the result.
+type GitStatusSource interface {
+	Lookup(dirs []string) map[string]gitstatus.Status
+}
+
+// GitStatusMsg carries git statuses looked up in the background, keyed by
+// directory.
+type GitStatusMsg struct {
+	Statuses map[string]gitstatus.Status
+}
+
+// LookupGitStatus returns a command that looks up the git status of dirs.
+func LookupGitStatus(source GitStatusSource, dirs []string) tea.Cmd {
+	if source == nil || len(dirs) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return GitStatusMsg{Statuses: source.Lookup(dirs)}
+	}
+}
+
+// MergeGitStatus adds the statuses in msg to statuses, allocating the map
+// when it is nil.
+func MergeGitStatus(statuses map[string]gitstatus.Status, msg GitStatusMsg) map[string]gitstatus.Status {
+	if statuses == nil {
+		statuses = make(map[string]gitstatus.Status, len(msg.Statuses))
+	}
+	maps.Copy(statuses, msg.Statuses)
+	return statuses
+}
+
 // ProjectsLoadedMsg carries the result of loading projects from the store.
 type ProjectsLoadedMsg struct {
 	Projects []project.Project
@@ -53,6 +90,7 @@ type editField int
 const (
 	editFieldName editField = iota
 	editFieldAliases
+	editFieldTags
 )
 
 // ProjectPickerModel is the Bubble Tea model for the project picker view.
@@ -67,27 +105,30 @@ type ProjectPickerModel struct {
 	filterText string
 
 	// Confirm remove state
-	confirmRemove      bool
-	pendingRemovePath  string
-	pendingRemoveName  string
-	afterRemove        bool // set after removal to adjust cursor on refresh
+	confirmRemove     bool
+	pendingRemovePath string
+	pendingRemoveName string
+	pendingWorktree   bool // the pending project is a linked worktree
+	afterRemove       bool // set after removal to adjust cursor on refresh
 
 	// Edit mode state
-	editMode        bool
-	editProject     project.Project
-	editName        string
-	editAliases     []string // current alias names for the project's directory
-	editRemoved     []string // alias names removed during this edit session
-	editNewAlias    string   // text input for adding a new alias
-	editFocus       editField
-	editAliasCursor int
-	editError       string
+	editMode bool
+	edit     ProjectEditModel
+
+	matchStyle lipgloss.Style
+
+	gitStatus   GitStatusSource
+	gitStatuses map[string]gitstatus.Status
+
+	worktreeManager WorktreeManager
+	worktrees       map[string][]worktree.Worktree
 }
 
 // NewProjectPicker creates a new ProjectPickerModel with the given store.
 func NewProjectPicker(store ProjectStore) ProjectPickerModel {
 	return ProjectPickerModel{
-		store: store,
+		store:      store,
+		matchStyle: NewMatchStyle(""),
 	}
 }
 
@@ -98,6 +139,28 @@ func (m ProjectPickerModel) WithEditor(editor ProjectEditor, aliasStore AliasEdi
 	return m
 }
 
+// WithMatchStyle returns a copy of the ProjectPickerModel that highlights the
+// characters of each project name that match the filter in style.
+func (m ProjectPickerModel) WithMatchStyle(style lipgloss.Style) ProjectPickerModel {
+	m.matchStyle = style
+	return m
+}
+
+// WithGitStatus returns a copy of the ProjectPickerModel that shows each
+// project's git status, looked up in the background whenever projects load.
+func (m ProjectPickerModel) WithGitStatus(source GitStatusSource) ProjectPickerModel {
+	m.gitStatus = source
+	return m
+}
+
+// WithWorktrees returns a copy of the ProjectPickerModel that lists each
+// repository's linked worktrees under it and offers to delete a worktree
+// when its project is removed.
+func (m ProjectPickerModel) WithWorktrees(manager WorktreeManager) ProjectPickerModel {
+	m.worktreeManager = manager
+	return m
+}
+
 // WithFilter returns a copy of the ProjectPickerModel with the filter pre-filled.
 // The picker starts in filtering mode with the given text.
 func (m ProjectPickerModel) WithFilter(text string) ProjectPickerModel {
@@ -117,9 +180,28 @@ func (m ProjectPickerModel) Init() tea.Cmd {
 	}
 }
 
-// filteredProjects returns the projects that match the current filter text.
+// nestedProjects returns the projects with each repository's linked
+// worktrees under it, and the set of worktree paths.
+func (m ProjectPickerModel) nestedProjects() ([]project.Project, map[string]bool) {
+	return NestWorktrees(m.projects, m.worktrees)
+}
+
+// filteredProjects returns the projects that match the current filter text, best match first.
+// A #tag term matches the projects' tags.
 func (m ProjectPickerModel) filteredProjects() []project.Project {
-	return fuzzy.Filter(m.projects, m.filterText, func(p project.Project) string { return p.Name })
+	projects, _ := m.nestedProjects()
+	return fuzzy.FilterTagged(projects, m.filterText,
+		func(p project.Project) string { return p.Name },
+		func(p project.Project) []string { return p.Tags })
+}
+
+// projectPaths returns the paths of all loaded projects.
+func (m ProjectPickerModel) projectPaths() []string {
+	paths := make([]string, len(m.projects))
+	for i, p := range m.projects {
+		paths[i] = p.Path
+	}
+	return paths
 }
 
 // totalItems returns the count of visible items (filtered projects + browse option).
@@ -147,6 +229,23 @@ func (m ProjectPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.cursor = 0
 		}
+		return m, tea.Batch(
+			LookupGitStatus(m.gitStatus, m.projectPaths()),
+			LookupWorktrees(m.worktreeManager, m.projectPaths()),
+		)
+
+	case WorktreesLoadedMsg:
+		m.worktrees = msg.Worktrees
+		var paths []string
+		for _, linked := range msg.Worktrees {
+			for _, w := range linked {
+				paths = append(paths, w.Path)
+			}
+		}
+		return m, LookupGitStatus(m.gitStatus, paths)
+
+	case GitStatusMsg:
+		m.gitStatuses = MergeGitStatus(m.gitStatuses, msg)
 
 	case tea.KeyMsg:
 		if m.confirmRemove {
@@ -229,6 +328,11 @@ func (m ProjectPickerModel) updateFiltering(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 	case tea.KeyRunes:
 		m.filterText += string(msg.Runes)
 		m.cursor = 0 // Reset cursor when filter changes
+
+	case tea.KeySpace:
+		// Space separates filter terms
+		m.filterText += " "
+		m.cursor = 0
 	}
 	return m, nil
 }
@@ -244,19 +348,25 @@ func (m ProjectPickerModel) handleRemoveKey() (tea.Model, tea.Cmd) {
 	m.confirmRemove = true
 	m.pendingRemovePath = p.Path
 	m.pendingRemoveName = p.Name
+	m.pendingWorktree = m.worktreeManager != nil && m.worktreeManager.IsWorktree(p.Path)
 	return m, nil
 }
 
 func (m ProjectPickerModel) updateConfirmRemove(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
-	case msg.Type == tea.KeyRunes && string(msg.Runes) == "y":
+	case msg.Type == tea.KeyRunes && (string(msg.Runes) == "y" || (string(msg.Runes) == "w" && m.pendingWorktree)):
 		path := m.pendingRemovePath
+		deleteWorktree := string(msg.Runes) == "w"
 		m.confirmRemove = false
 		m.pendingRemovePath = ""
 		m.pendingRemoveName = ""
+		m.pendingWorktree = false
 		m.afterRemove = true
 
 		_ = m.store.Remove(path)
+		if deleteWorktree {
+			_ = m.worktreeManager.Remove(path)
+		}
 
 		return m, func() tea.Msg {
 			_, _ = m.store.CleanStale()
@@ -269,6 +379,7 @@ func (m ProjectPickerModel) updateConfirmRemove(msg tea.KeyMsg) (tea.Model, tea.
 		m.confirmRemove = false
 		m.pendingRemovePath = ""
 		m.pendingRemoveName = ""
+		m.pendingWorktree = false
 		return m, nil
 	}
 
@@ -283,154 +394,30 @@ func (m ProjectPickerModel) handleEditKey() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	p := filtered[m.cursor]
 	m.editMode = true
-	m.editProject = p
-	m.editName = p.Name
-	m.editFocus = editFieldName
-	m.editNewAlias = ""
-	m.editError = ""
-	m.editRemoved = nil
-	m.editAliasCursor = 0
-
-	// Load aliases matching this project's directory
-	allAliases, err := m.aliasStore.Load()
-	if err != nil {
-		m.editAliases = nil
-	} else {
-		var matching []string
-		for name, path := range allAliases {
-			if path == p.Path {
-				matching = append(matching, name)
-			}
-		}
-		m.editAliases = matching
-	}
-
+	m.edit = NewProjectEdit(filtered[m.cursor], m.editor, m.aliasStore)
 	return m, nil
 }
 
 func (m ProjectPickerModel) updateEditMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.Type {
-	case tea.KeyEsc:
-		m.editMode = false
-		m.editError = ""
-		return m, nil
+	var outcome EditOutcome
+	m.edit, outcome = m.edit.Update(msg)
 
-	case tea.KeyTab:
-		if m.editFocus == editFieldName {
-			m.editFocus = editFieldAliases
-		} else {
-			m.editFocus = editFieldName
-		}
-		return m, nil
-
-	case tea.KeyEnter:
-		return m.handleEditConfirm()
-
-	case tea.KeyBackspace:
-		if m.editFocus == editFieldName {
-			if len(m.editName) > 0 {
-				m.editName = m.editName[:len(m.editName)-1]
-			}
-		} else if m.editAliasCursor == len(m.editAliases) {
-			// On Add input
-			if len(m.editNewAlias) > 0 {
-				m.editNewAlias = m.editNewAlias[:len(m.editNewAlias)-1]
-			}
-		}
-		return m, nil
-
-	case tea.KeyDown:
-		if m.editFocus == editFieldAliases && m.editAliasCursor < len(m.editAliases) {
-			m.editAliasCursor++
-		}
-		return m, nil
-
-	case tea.KeyUp:
-		if m.editFocus == editFieldAliases && m.editAliasCursor > 0 {
-			m.editAliasCursor--
-		}
-		return m, nil
-
-	case tea.KeyRunes:
-		text := string(msg.Runes)
-		// In alias area, on an existing alias entry: x removes it
-		if m.editFocus == editFieldAliases && text == "x" && m.editAliasCursor < len(m.editAliases) {
-			removed := m.editAliases[m.editAliasCursor]
-			m.editRemoved = append(m.editRemoved, removed)
-			m.editAliases = append(m.editAliases[:m.editAliasCursor], m.editAliases[m.editAliasCursor+1:]...)
-			if m.editAliasCursor > len(m.editAliases) {
-				m.editAliasCursor = len(m.editAliases)
-			}
-			return m, nil
-		}
-		// In alias area, on Add input: type into new alias
-		if m.editFocus == editFieldAliases && m.editAliasCursor == len(m.editAliases) {
-			m.editNewAlias += text
-			m.editError = ""
-			return m, nil
-		}
-		if m.editFocus == editFieldName {
-			m.editName += text
+	switch outcome {
+	case EditCancelled:
+		m.editMode = false
+	case EditSaved:
+		m.editMode = false
+		// Refresh project list
+		return m, func() tea.Msg {
+			_, _ = m.store.CleanStale()
+			projects, err := m.store.List()
+			return ProjectsLoadedMsg{Projects: projects, Err: err}
 		}
-		m.editError = ""
-		return m, nil
 	}
 	return m, nil
 }
 
-func (m ProjectPickerModel) handleEditConfirm() (tea.Model, tea.Cmd) {
-	name := strings.TrimSpace(m.editName)
-	if name == "" {
-		m.editError = "Project name cannot be empty"
-		return m, nil
-	}
-
-	// Save project name if changed
-	if name != m.editProject.Name {
-		if err := m.editor.Rename(m.editProject.Path, name); err != nil {
-			m.editError = "Failed to save project name"
-			return m, nil
-		}
-	}
-
-	// Handle alias removals
-	for _, removed := range m.editRemoved {
-		m.aliasStore.Delete(removed)
-	}
-
-	// Handle new alias addition
-	newAlias := strings.TrimSpace(m.editNewAlias)
-	if newAlias != "" {
-		// Check for collision
-		allAliases, err := m.aliasStore.Load()
-		if err == nil {
-			if existingPath, ok := allAliases[newAlias]; ok && existingPath != m.editProject.Path {
-				m.editError = fmt.Sprintf("Alias '%s' already exists", newAlias)
-				return m, nil
-			}
-		}
-		m.aliasStore.Set(newAlias, m.editProject.Path)
-	}
-
-	// Save alias changes
-	if err := m.aliasStore.Save(); err != nil {
-		m.editError = "Failed to save aliases"
-		return m, nil
-	}
-
-	m.editMode = false
-	m.editError = ""
-
-	// Refresh project list
-	return m, func() tea.Msg {
-		_, _ = m.store.CleanStale()
-		projects, err := m.store.List()
-		return ProjectsLoadedMsg{Projects: projects, Err: err}
-	}
-}
-
 func (m ProjectPickerModel) handleEnter() (tea.Model, tea.Cmd) {
 	filtered := m.filteredProjects()
 	if m.cursor < len(filtered) {
@@ -456,11 +443,16 @@ func (m ProjectPickerModel) View() string {
 	b.WriteString("Select a project:\n\n")
 
 	if m.confirmRemove {
-		fmt.Fprintf(&b, "  Remove project '%s'? (y/n)\n", m.pendingRemoveName)
+		if m.pendingWorktree {
+			fmt.Fprintf(&b, "  Remove project '%s'? (y/n, w to also delete the worktree)\n", m.pendingRemoveName)
+		} else {
+			fmt.Fprintf(&b, "  Remove project '%s'? (y/n)\n", m.pendingRemoveName)
+		}
 		return b.String()
 	}
 
 	filtered := m.filteredProjects()
+	_, children := m.nestedProjects()
 
 	if len(m.projects) == 0 && !m.filtering {
 		b.WriteString("  No saved projects yet.\n")
@@ -472,7 +464,17 @@ func (m ProjectPickerModel) View() string {
 			if i == m.cursor {
 				cursor = "> "
 			}
-			fmt.Fprintf(&b, "%s%s\n", cursor, p.Name)
+			if children[p.Path] && m.filterText == "" {
+				cursor += "└ "
+			}
+			fmt.Fprintf(&b, "%s%s", cursor, HighlightMatch(p.Name, m.filterText, lipgloss.NewStyle(), m.matchStyle))
+			if len(p.Tags) > 0 {
+				fmt.Fprintf(&b, "  %s", project.FormatTags(p.Tags))
+			}
+			if status, ok := m.gitStatuses[p.Path]; ok {
+				fmt.Fprintf(&b, "  %s", status)
+			}
+			b.WriteString("\n")
 		}
 	}
 
@@ -492,47 +494,5 @@ func (m ProjectPickerModel) View() string {
 }
 
 func (m ProjectPickerModel) viewEditMode() string {
-	var b strings.Builder
-
-	fmt.Fprintf(&b, "Edit project: %s\n\n", m.editProject.Name)
-
-	nameIndicator := "  "
-	if m.editFocus == editFieldName {
-		nameIndicator = "> "
-	}
-	fmt.Fprintf(&b, "%sName: %s\n", nameIndicator, m.editName)
-
-	b.WriteString("\n")
-
-	aliasIndicator := "  "
-	if m.editFocus == editFieldAliases {
-		aliasIndicator = "> "
-	}
-	b.WriteString(aliasIndicator + "Aliases:\n")
-
-	if len(m.editAliases) == 0 {
-		b.WriteString("    (none)\n")
-	} else {
-		for i, a := range m.editAliases {
-			marker := "    "
-			if m.editFocus == editFieldAliases && m.editAliasCursor == i {
-				marker = "  > "
-			}
-			fmt.Fprintf(&b, "%s[x] %s\n", marker, a)
-		}
-	}
-
-	addMarker := "    "
-	if m.editFocus == editFieldAliases && m.editAliasCursor == len(m.editAliases) {
-		addMarker = "  > "
-	}
-	fmt.Fprintf(&b, "%sAdd: %s\n", addMarker, m.editNewAlias)
-
-	if m.editError != "" {
-		fmt.Fprintf(&b, "\n  Error: %s\n", m.editError)
-	}
-
-	b.WriteString("\n  [Enter] Save  [Esc] Cancel  [Tab] Switch field")
-
-	return b.String()
+	return m.edit.View()
 }
@@ -1,13 +1,16 @@
 package ui_test
 
 import (
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/leeovery/portal/internal/gitstatus"
 	"github.com/leeovery/portal/internal/project"
 	"github.com/leeovery/portal/internal/ui"
+	"github.com/leeovery/portal/internal/worktree"
 )
 
 // mockProjectStore implements ui.ProjectStore for testing.
@@ -289,6 +292,9 @@ type mockProjectEditor struct {
 	renamedPath string
 	renamedName string
 	renameErr   error
+	taggedPath  string
+	tags        []string
+	tagsErr     error
 }
 
 func (m *mockProjectEditor) Rename(path, newName string) error {
@@ -297,6 +303,12 @@ func (m *mockProjectEditor) Rename(path, newName string) error {
 	return m.renameErr
 }
 
+func (m *mockProjectEditor) SetTags(path string, tags []string) error {
+	m.taggedPath = path
+	m.tags = tags
+	return m.tagsErr
+}
+
 // mockAliasEditor implements ui.AliasEditor for testing.
 type mockAliasEditor struct {
 	aliases    map[string]string
@@ -444,6 +456,21 @@ func TestProjectPicker_EditMode_TabCyclesBetweenFields(t *testing.T) {
 		t.Errorf("aliases field should have focus indicator after Tab, got: %q", aliasLine)
 	}
 
+	// Tab to tags
+	m = sendKeys(m, keyTab())
+	view = m.View()
+	lines = strings.Split(view, "\n")
+	var tagsLine string
+	for _, line := range lines {
+		if strings.Contains(line, "Tags:") {
+			tagsLine = line
+			break
+		}
+	}
+	if !strings.HasPrefix(tagsLine, "> ") {
+		t.Errorf("tags field should have focus indicator after second Tab, got: %q", tagsLine)
+	}
+
 	// Tab again back to name
 	m = sendKeys(m, keyTab())
 	view = m.View()
@@ -455,7 +482,7 @@ func TestProjectPicker_EditMode_TabCyclesBetweenFields(t *testing.T) {
 		}
 	}
 	if !strings.HasPrefix(nameLine, "> ") {
-		t.Errorf("name field should have focus indicator after second Tab, got: %q", nameLine)
+		t.Errorf("name field should have focus indicator after third Tab, got: %q", nameLine)
 	}
 }
 
@@ -488,6 +515,69 @@ func TestProjectPicker_EditMode_EnterSavesNameChange(t *testing.T) {
 	}
 }
 
+func TestProjectPicker_EditMode_SavesTags(t *testing.T) {
+	store := &mockProjectStore{projects: threeProjects()}
+	editor := &mockProjectEditor{}
+	m := initEditModel(store, editor, newMockAliasEditor(nil))
+
+	// Enter edit mode on "newest" and tab to the tags field
+	m = sendKeys(m, keyRune('e'), keyTab(), keyTab())
+	for _, r := range "#Work" {
+		m = sendKeys(m, keyRune(r))
+	}
+	m = sendKeys(m, keySpace(), keyRune('o'), keyRune('s'), keyRune('s'))
+
+	if view := m.View(); !strings.Contains(view, "> Tags: #Work oss") {
+		t.Errorf("view should show typed tags, got:\n%s", view)
+	}
+
+	if _, cmd := m.Update(keyEnter()); cmd == nil {
+		t.Fatal("expected command from Enter in edit mode, got nil")
+	}
+	if editor.taggedPath != "/code/newest" {
+		t.Errorf("expected tags path %q, got %q", "/code/newest", editor.taggedPath)
+	}
+	if want := []string{"oss", "work"}; !slices.Equal(editor.tags, want) {
+		t.Errorf("expected tags %v, got %v", want, editor.tags)
+	}
+}
+
+func TestProjectPicker_EditMode_UnchangedTagsAreNotSaved(t *testing.T) {
+	projects := threeProjects()
+	projects[0].Tags = []string{"work"}
+	store := &mockProjectStore{projects: projects}
+	editor := &mockProjectEditor{}
+	m := initEditModel(store, editor, newMockAliasEditor(nil))
+
+	m = sendKeys(m, keyRune('e'))
+	if view := m.View(); !strings.Contains(view, "Tags: work") {
+		t.Errorf("view should show current tags, got:\n%s", view)
+	}
+	m.Update(keyEnter())
+
+	if editor.taggedPath != "" {
+		t.Errorf("tags should not be saved when unchanged, got path %q", editor.taggedPath)
+	}
+}
+
+func TestProjectPicker_FilterByTag(t *testing.T) {
+	projects := threeProjects()
+	projects[1].Tags = []string{"client"}
+	store := &mockProjectStore{projects: projects}
+	m := tea.Model(ui.NewProjectPicker(store).WithFilter("#client"))
+	m, _ = m.Update(projectsLoaded(store.projects))
+
+	view := m.View()
+	if !strings.Contains(view, "middle  #client") {
+		t.Errorf("view should list the tagged project with its tags, got:\n%s", view)
+	}
+	for _, name := range []string{"newest", "oldest"} {
+		if strings.Contains(view, name) {
+			t.Errorf("view should not list untagged project %q, got:\n%s", name, view)
+		}
+	}
+}
+
 func TestProjectPicker_EditMode_EscCancelsWithoutSaving(t *testing.T) {
 	store := &mockProjectStore{projects: threeProjects()}
 	editor := &mockProjectEditor{}
@@ -942,3 +1032,151 @@ func TestProjectPicker_Remove_RapidXPressesNoDoubleRemoval(t *testing.T) {
 		t.Error("store.Remove should not have been called during confirmation")
 	}
 }
+
+func TestProjectPicker_FilterAcceptsExtendedSyntax(t *testing.T) {
+	store := &mockProjectStore{projects: []project.Project{
+		{Path: "/code/api", Name: "api"},
+		{Path: "/code/web-api", Name: "web-api"},
+		{Path: "/code/webapp", Name: "webapp"},
+	}}
+	m := sendKeys(initModel(store), keyRune('/'))
+	m = sendKeys(m, keyRune('w'), tea.KeyMsg{Type: tea.KeySpace}, keyRune('a'), keyRune('p'), keyRune('i'), keyRune('$'))
+
+	view := m.View()
+	if !strings.Contains(view, "web-api") || strings.Contains(view, "webapp") || strings.Contains(view, "  api\n") {
+		t.Errorf("expected only web-api to match %q, got:\n%s", "w api$", view)
+	}
+}
+
+// mockGitStatus implements ui.GitStatusSource for testing.
+type mockGitStatus struct {
+	statuses map[string]gitstatus.Status
+	looked   []string
+}
+
+func (m *mockGitStatus) Lookup(dirs []string) map[string]gitstatus.Status {
+	m.looked = dirs
+	return m.statuses
+}
+
+func TestProjectPicker_GitStatus(t *testing.T) {
+	store := &mockProjectStore{projects: threeProjects()}
+	source := &mockGitStatus{statuses: map[string]gitstatus.Status{
+		"/code/newest": {Branch: "main", Dirty: true, Ahead: 1},
+	}}
+	m := ui.NewProjectPicker(store).WithGitStatus(source)
+
+	updated, cmd := m.Update(projectsLoaded(store.projects))
+	if cmd == nil {
+		t.Fatal("expected a git status lookup after projects load")
+	}
+	msg := cmd()
+	if want := []string{"/code/newest", "/code/middle", "/code/oldest"}; !slices.Equal(source.looked, want) {
+		t.Errorf("looked up %v, want %v", source.looked, want)
+	}
+
+	updated, _ = updated.Update(msg)
+	view := updated.View()
+	if !strings.Contains(view, "newest  main* ↑1") {
+		t.Errorf("view missing status:\n%s", view)
+	}
+	if strings.Contains(view, "middle  ") {
+		t.Errorf("project without a status should show none:\n%s", view)
+	}
+}
+
+// mockWorktreeManager implements ui.WorktreeManager for testing.
+type mockWorktreeManager struct {
+	worktrees map[string][]worktree.Worktree
+	removed   []string
+}
+
+func (m *mockWorktreeManager) Lookup(dirs []string) map[string][]worktree.Worktree {
+	return m.worktrees
+}
+
+func (m *mockWorktreeManager) IsWorktree(path string) bool {
+	for _, linked := range m.worktrees {
+		for _, w := range linked {
+			if w.Path == path {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *mockWorktreeManager) Remove(path string) error {
+	m.removed = append(m.removed, path)
+	return nil
+}
+
+func TestProjectPicker_Worktrees(t *testing.T) {
+	projects := []project.Project{
+		{Path: "/code/api", Name: "api"},
+		{Path: "/code/web", Name: "web"},
+		{Path: "/code/api-fix", Name: "api-fix", Tags: []string{"urgent"}},
+	}
+	worktrees := map[string][]worktree.Worktree{"/code/api": {
+		{Path: "/code/api-feature", Branch: "feature"},
+		{Path: "/code/api-fix", Branch: "fix"},
+	}}
+
+	load := func(store *mockProjectStore, manager *mockWorktreeManager) tea.Model {
+		m := ui.NewProjectPicker(store).WithWorktrees(manager)
+		updated, cmd := m.Update(projectsLoaded(store.projects))
+		updated, _ = updated.Update(cmd())
+		return updated
+	}
+
+	t.Run("lists worktrees under their repository", func(t *testing.T) {
+		store := &mockProjectStore{projects: projects}
+		view := load(store, &mockWorktreeManager{worktrees: worktrees}).View()
+
+		api := strings.Index(view, "> api\n")
+		feature := strings.Index(view, "  └ api@feature\n")
+		fix := strings.Index(view, "  └ api-fix  #urgent\n")
+		web := strings.Index(view, "  web\n")
+		if api < 0 || feature < api || fix < feature || web < fix {
+			t.Errorf("expected api, its worktrees, then web:\n%s", view)
+		}
+	})
+
+	t.Run("filtering flattens the list", func(t *testing.T) {
+		store := &mockProjectStore{projects: projects}
+		m := sendKeys(load(store, &mockWorktreeManager{worktrees: worktrees}), keyRune('/'), keyRune('f'), keyRune('e'), keyRune('a'))
+
+		if view := m.View(); !strings.Contains(view, "> api@feature") || strings.Contains(view, "└") {
+			t.Errorf("expected a flat match for api@feature:\n%s", view)
+		}
+	})
+
+	t.Run("removing a worktree offers to delete it", func(t *testing.T) {
+		store := &mockProjectStore{projects: projects}
+		manager := &mockWorktreeManager{worktrees: worktrees}
+		m := sendKeys(load(store, manager), keyDown(), keyRune('x'))
+
+		if view := m.View(); !strings.Contains(view, "Remove project 'api@feature'? (y/n, w to also delete the worktree)") {
+			t.Fatalf("expected worktree removal prompt:\n%s", view)
+		}
+
+		sendKeys(m, keyRune('w'))
+		if !slices.Equal(manager.removed, []string{"/code/api-feature"}) {
+			t.Errorf("removed worktrees %v, want [/code/api-feature]", manager.removed)
+		}
+	})
+
+	t.Run("w does nothing for a plain project", func(t *testing.T) {
+		store := &mockProjectStore{projects: projects}
+		manager := &mockWorktreeManager{worktrees: worktrees}
+		m := sendKeys(load(store, manager), keyRune('x'))
+
+		if view := m.View(); !strings.Contains(view, "Remove project 'api'? (y/n)\n") {
+			t.Fatalf("expected the plain removal prompt:\n%s", view)
+		}
+		sendKeys(m, keyRune('w'))
+		if store.removeCalled || len(manager.removed) > 0 {
+			t.Error("w should not remove a project that is not a worktree")
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+)
+
+// RelativeTime describes t relative to now, e.g. "just now", "5m ago" or
+// "3d ago". Times more than four weeks old are shown as a date, and the zero
+// time as "never".
+func RelativeTime(t, now time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	case d < 7*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	case d < 28*24*time.Hour:
+		return fmt.Sprintf("%dw ago", int(d/(7*24*time.Hour)))
+	default:
+		return t.Format("2006-01-02")
+	}
+}
@@ -0,0 +1,35 @@
+package ui_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/ui"
+)
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want string
+	}{
+		{name: "zero time", t: time.Time{}, want: "never"},
+		{name: "seconds ago", t: now.Add(-30 * time.Second), want: "just now"},
+		{name: "clock skew into the future", t: now.Add(5 * time.Second), want: "just now"},
+		{name: "minutes", t: now.Add(-5 * time.Minute), want: "5m ago"},
+		{name: "hours", t: now.Add(-3*time.Hour - 59*time.Minute), want: "3h ago"},
+		{name: "days", t: now.Add(-2 * 24 * time.Hour), want: "2d ago"},
+		{name: "weeks", t: now.Add(-15 * 24 * time.Hour), want: "2w ago"},
+		{name: "old times show the date", t: time.Date(2024, 12, 1, 9, 0, 0, 0, time.UTC), want: "2024-12-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ui.RelativeTime(tt.t, now); got != tt.want {
+				t.Errorf("RelativeTime() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// SessionPickerModel is a standalone multi-select list of sessions, used by
+// commands that act on several sessions at once such as kill.
+type SessionPickerModel struct {
+	title     string
+	sessions  []tmux.Session
+	selected  map[string]bool
+	cursor    int
+	confirmed bool
+}
+
+// NewSessionPicker creates a picker over sessions with the given title line.
+func NewSessionPicker(title string, sessions []tmux.Session) SessionPickerModel {
+	return SessionPickerModel{
+		title:    title,
+		sessions: sessions,
+		selected: make(map[string]bool),
+	}
+}
+
+// Init implements tea.Model.
+func (m SessionPickerModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles key input for the session picker.
+func (m SessionPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case keyMsg.Type == tea.KeyEsc || keyMsg.Type == tea.KeyCtrlC ||
+		(keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "q"):
+		m.selected = make(map[string]bool)
+		return m, tea.Quit
+
+	case keyMsg.Type == tea.KeyDown || (keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "j"):
+		if m.cursor < len(m.sessions)-1 {
+			m.cursor++
+		}
+
+	case keyMsg.Type == tea.KeyUp || (keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "k"):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case keyMsg.Type == tea.KeySpace:
+		if m.cursor < len(m.sessions) {
+			name := m.sessions[m.cursor].Name
+			m.selected[name] = !m.selected[name]
+		}
+
+	case keyMsg.Type == tea.KeyRunes && string(keyMsg.Runes) == "a":
+		// Select all, or clear the selection when everything is already selected.
+		all := len(m.Selected()) < len(m.sessions)
+		for _, s := range m.sessions {
+			m.selected[s.Name] = all
+		}
+
+	case keyMsg.Type == tea.KeyEnter:
+		m.confirmed = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// Selected returns the names of the selected sessions in list order.
+// It is empty when the picker was cancelled.
+func (m SessionPickerModel) Selected() []string {
+	var names []string
+	for _, s := range m.sessions {
+		if m.selected[s.Name] {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+// Confirmed reports whether the user confirmed the selection with Enter.
+func (m SessionPickerModel) Confirmed() bool {
+	return m.confirmed
+}
+
+// View renders the session picker.
+func (m SessionPickerModel) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n\n", m.title)
+
+	if len(m.sessions) == 0 {
+		b.WriteString("  No sessions.\n")
+	}
+	for i, s := range m.sessions {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		check := "[ ]"
+		if m.selected[s.Name] {
+			check = "[x]"
+		}
+		status := ""
+		if s.Attached {
+			status = "  (attached)"
+		}
+		fmt.Fprintf(&b, "%s%s %s%s\n", cursor, check, s.Name, status)
+	}
+
+	b.WriteString("\n  [Space] Toggle  [a] All  [Enter] Confirm  [Esc] Cancel")
+
+	return b.String()
+}
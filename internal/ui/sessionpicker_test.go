@@ -0,0 +1,82 @@
+package ui_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/leeovery/portal/internal/tmux"
+	"github.com/leeovery/portal/internal/ui"
+)
+
+func pickerSessions() []tmux.Session {
+	return []tmux.Session{
+		{Name: "api", Windows: 1, Attached: true},
+		{Name: "web", Windows: 2},
+		{Name: "db", Windows: 1},
+	}
+}
+
+func pickerKeys(m tea.Model, keys ...tea.KeyMsg) ui.SessionPickerModel {
+	for _, k := range keys {
+		m, _ = m.Update(k)
+	}
+	return m.(ui.SessionPickerModel)
+}
+
+func TestSessionPicker(t *testing.T) {
+	space := tea.KeyMsg{Type: tea.KeySpace}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	t.Run("space toggles and enter confirms in list order", func(t *testing.T) {
+		m := pickerKeys(ui.NewSessionPicker("Kill:", pickerSessions()), down, down, space, tea.KeyMsg{Type: tea.KeyUp}, tea.KeyMsg{Type: tea.KeyUp}, space, enter)
+
+		if !m.Confirmed() {
+			t.Error("Confirmed() = false, want true")
+		}
+		if got := m.Selected(); !reflect.DeepEqual(got, []string{"api", "db"}) {
+			t.Errorf("Selected() = %v, want [api db]", got)
+		}
+	})
+
+	t.Run("space twice deselects", func(t *testing.T) {
+		m := pickerKeys(ui.NewSessionPicker("Kill:", pickerSessions()), space, space)
+		if got := m.Selected(); len(got) != 0 {
+			t.Errorf("Selected() = %v, want none", got)
+		}
+	})
+
+	t.Run("a selects all then clears", func(t *testing.T) {
+		a := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}
+		m := pickerKeys(ui.NewSessionPicker("Kill:", pickerSessions()), a)
+		if got := m.Selected(); len(got) != 3 {
+			t.Errorf("Selected() = %v, want all three", got)
+		}
+		m = pickerKeys(m, a)
+		if got := m.Selected(); len(got) != 0 {
+			t.Errorf("Selected() = %v, want none", got)
+		}
+	})
+
+	t.Run("esc cancels and drops the selection", func(t *testing.T) {
+		m := pickerKeys(ui.NewSessionPicker("Kill:", pickerSessions()), space, tea.KeyMsg{Type: tea.KeyEsc})
+		if m.Confirmed() {
+			t.Error("Confirmed() = true, want false")
+		}
+		if got := m.Selected(); len(got) != 0 {
+			t.Errorf("Selected() = %v, want none", got)
+		}
+	})
+
+	t.Run("view marks cursor, selection and attached sessions", func(t *testing.T) {
+		m := pickerKeys(ui.NewSessionPicker("Kill:", pickerSessions()), space)
+		view := m.View()
+		for _, want := range []string{"Kill:", "> [x] api  (attached)", "  [ ] web"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("view missing %q:\n%s", want, view)
+			}
+		}
+	})
+}
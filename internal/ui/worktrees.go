@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/worktree"
+)
+
+// WorktreeManager lists repositories' linked worktrees and removes them.
+type WorktreeManager interface {
+	// Lookup returns the linked worktrees of each directory that is a
+	// repository's main working tree.
+	Lookup(dirs []string) map[string][]worktree.Worktree
+	IsWorktree(path string) bool
+	Remove(path string) error
+}
+
+// WorktreesLoadedMsg carries linked worktrees looked up in the background,
+// keyed by the main working tree's directory.
+type WorktreesLoadedMsg struct {
+	Worktrees map[string][]worktree.Worktree
+}
+
+// LookupWorktrees returns a command that looks up the linked worktrees of dirs.
+func LookupWorktrees(manager WorktreeManager, dirs []string) tea.Cmd {
+	if manager == nil || len(dirs) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return WorktreesLoadedMsg{Worktrees: manager.Lookup(dirs)}
+	}
+}
+
+// NestWorktrees returns projects with each repository's linked worktrees
+// listed right after it, and the set of paths listed as worktrees. A
+// worktree that is itself a remembered project moves under its repository,
+// keeping its name and tags; others are named repo@branch.
+func NestWorktrees(projects []project.Project, worktrees map[string][]worktree.Worktree) ([]project.Project, map[string]bool) {
+	if len(worktrees) == 0 {
+		return projects, nil
+	}
+
+	children := make(map[string]bool)
+	for _, p := range projects {
+		for _, w := range worktrees[p.Path] {
+			children[w.Path] = true
+		}
+	}
+
+	nested := make([]project.Project, 0, len(projects)+len(children))
+	for _, p := range projects {
+		if children[p.Path] {
+			continue
+		}
+		nested = append(nested, p)
+		for _, w := range worktrees[p.Path] {
+			child := project.Project{Path: w.Path, Name: worktree.QualifiedName(p.Path, w)}
+			if i := slices.IndexFunc(projects, func(p project.Project) bool { return p.Path == w.Path }); i >= 0 {
+				child = projects[i]
+			}
+			nested = append(nested, child)
+		}
+	}
+	return nested, children
+}
@@ -0,0 +1,268 @@
+// Package worktree lists, creates and removes linked git worktrees.
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/leeovery/portal/internal/resolver"
+)
+
+// DefaultWorkers is the number of repositories Lookup lists concurrently.
+const DefaultWorkers = 4
+
+// Worktree is one working tree of a repository, as reported by
+// git worktree list.
+type Worktree struct {
+	// Path is the working tree's directory.
+	Path string
+	// Branch is the checked-out branch, empty when HEAD is detached.
+	Branch string
+	// Main reports the repository's main working tree, which is always
+	// listed first.
+	Main bool
+	// Bare reports a bare repository, which has no working tree.
+	Bare bool
+}
+
+// Name returns how the worktree is shown: its branch, or its directory name
+// when HEAD is detached.
+func (w Worktree) Name() string {
+	if w.Branch != "" {
+		return w.Branch
+	}
+	return filepath.Base(w.Path)
+}
+
+// List returns the worktrees of the repository containing dir, main first.
+func List(dir string, runner resolver.CommandRunner) ([]Worktree, error) {
+	out, err := runner.Run("git", "-C", dir, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	return parseList(out), nil
+}
+
+// parseList parses the output of git worktree list --porcelain: one block of
+// "key value" lines per worktree, separated by blank lines.
+func parseList(out string) []Worktree {
+	var worktrees []Worktree
+	var current *Worktree
+	for line := range strings.Lines(out) {
+		line = strings.TrimRight(line, "\n")
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "worktree":
+			worktrees = append(worktrees, Worktree{Path: value, Main: len(worktrees) == 0})
+			current = &worktrees[len(worktrees)-1]
+		case "branch":
+			if current != nil {
+				current.Branch = strings.TrimPrefix(value, "refs/heads/")
+			}
+		case "bare":
+			if current != nil {
+				current.Bare = true
+			}
+		}
+	}
+	return worktrees
+}
+
+// Linked returns the worktrees other than the main working tree or a bare
+// repository.
+func Linked(worktrees []Worktree) []Worktree {
+	var linked []Worktree
+	for _, w := range worktrees {
+		if !w.Main && !w.Bare {
+			linked = append(linked, w)
+		}
+	}
+	return linked
+}
+
+// Info reports whether dir is the top level of a linked worktree and, if so,
+// the main repository it belongs to. It reads the worktree's .git file rather
+// than running git.
+func Info(dir string) (repo string, ok bool) {
+	data, err := os.ReadFile(filepath.Join(dir, ".git"))
+	if err != nil {
+		return "", false
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return "", false
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+
+	// A linked worktree's git directory is <common>/worktrees/<name>, where
+	// <common> is the main repository's .git or a bare repository.
+	worktreesDir := filepath.Dir(filepath.Clean(gitDir))
+	if filepath.Base(worktreesDir) != "worktrees" {
+		return "", false
+	}
+	common := filepath.Dir(worktreesDir)
+	if filepath.Base(common) == ".git" {
+		return filepath.Dir(common), true
+	}
+	return common, true
+}
+
+// RepoName returns the name of a repository directory, without the .git
+// suffix of bare repositories.
+func RepoName(repo string) string {
+	name := filepath.Base(repo)
+	if trimmed := strings.TrimSuffix(name, ".git"); trimmed != "" {
+		return trimmed
+	}
+	return name
+}
+
+// QualifiedName returns a name for a worktree of repo naming both:
+// repo@branch.
+func QualifiedName(repo string, w Worktree) string {
+	return RepoName(repo) + "@" + w.Name()
+}
+
+// DefaultPath returns where Ensure creates a worktree for branch: a sibling
+// of the main working tree named after it and the branch, with slashes in
+// the branch replaced by hyphens.
+func DefaultPath(main, branch string) string {
+	return filepath.Join(filepath.Dir(main), RepoName(main)+"-"+strings.ReplaceAll(branch, "/", "-"))
+}
+
+// Ensure returns the worktree of the repository containing dir that has
+// branch checked out, creating one at DefaultPath when there is none.
+// created reports whether a worktree was added.
+func Ensure(dir, branch string, runner resolver.CommandRunner) (path string, created bool, err error) {
+	worktrees, err := List(dir, runner)
+	if err != nil {
+		return "", false, err
+	}
+	if len(worktrees) == 0 {
+		return "", false, fmt.Errorf("no worktrees found for %s", dir)
+	}
+	for _, w := range worktrees {
+		if w.Branch == branch && !w.Bare {
+			return w.Path, false, nil
+		}
+	}
+
+	path = DefaultPath(worktrees[0].Path, branch)
+	if err := Add(dir, path, branch, runner); err != nil {
+		return "", false, err
+	}
+	return path, true, nil
+}
+
+// Add creates a worktree at path with branch checked out. An existing local
+// branch is checked out as is, a branch that only exists on a remote is
+// checked out tracking it, and any other branch is created from HEAD.
+func Add(dir, path, branch string, runner resolver.CommandRunner) error {
+	args := []string{"-C", dir, "worktree", "add", path, branch}
+	if !branchExists(dir, branch, runner) {
+		args = []string{"-C", dir, "worktree", "add", "-b", branch, path}
+	}
+	if _, err := runner.Run("git", args...); err != nil {
+		return fmt.Errorf("failed to add worktree for %s: %w", branch, err)
+	}
+	return nil
+}
+
+// branchExists reports whether branch exists locally or on any remote.
+func branchExists(dir, branch string, runner resolver.CommandRunner) bool {
+	if _, err := runner.Run("git", "-C", dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+		return true
+	}
+	out, err := runner.Run("git", "-C", dir, "for-each-ref", "--format=%(refname)", "refs/remotes/*/"+branch)
+	return err == nil && strings.TrimSpace(out) != ""
+}
+
+// Remove deletes the linked worktree at path and prunes its administrative
+// files from the main repository. Worktrees with uncommitted changes are not
+// removed. A path that no longer exists is ignored; git prunes its records
+// the next time it runs git worktree prune or gc.
+func Remove(path string, runner resolver.CommandRunner) error {
+	repo, ok := Info(path)
+	if !ok {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s is not a linked worktree", path)
+		}
+		return nil
+	}
+	if _, err := runner.Run("git", "-C", repo, "worktree", "remove", path); err != nil {
+		return fmt.Errorf("failed to remove worktree %s: %w", path, err)
+	}
+	if _, err := runner.Run("git", "-C", repo, "worktree", "prune"); err != nil {
+		return fmt.Errorf("failed to prune worktrees: %w", err)
+	}
+	return nil
+}
+
+// Manager lists and removes worktrees through a CommandRunner.
+type Manager struct {
+	runner  resolver.CommandRunner
+	workers int
+}
+
+// NewManager creates a Manager that runs git through runner.
+func NewManager(runner resolver.CommandRunner) *Manager {
+	return &Manager{runner: runner, workers: DefaultWorkers}
+}
+
+// Lookup returns the linked worktrees of each directory in dirs that is a
+// repository's main working tree, listed concurrently by a small pool of
+// workers. Directories without linked worktrees are left out.
+func (m *Manager) Lookup(dirs []string) map[string][]Worktree {
+	jobs := make(chan string)
+	var (
+		mu     sync.Mutex
+		linked = make(map[string][]Worktree)
+		wg     sync.WaitGroup
+	)
+
+	for range min(m.workers, len(dirs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for dir := range jobs {
+				worktrees, err := List(dir, m.runner)
+				if err != nil {
+					continue
+				}
+				if l := Linked(worktrees); len(l) > 0 {
+					mu.Lock()
+					linked[dir] = l
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, dir := range dirs {
+		// Only a .git directory marks a main working tree; this also skips
+		// directories outside any repository without running git.
+		if info, err := os.Stat(filepath.Join(dir, ".git")); err == nil && info.IsDir() {
+			jobs <- dir
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return linked
+}
+
+// IsWorktree reports whether path is a linked worktree.
+func (m *Manager) IsWorktree(path string) bool {
+	_, ok := Info(path)
+	return ok
+}
+
+// Remove deletes the linked worktree at path; see Remove.
+func (m *Manager) Remove(path string) error {
+	return Remove(path, m.runner)
+}
@@ -0,0 +1,220 @@
+package worktree_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/leeovery/portal/internal/worktree"
+)
+
+// fakeRunner answers git commands from a table keyed by the arguments after
+// "-C dir", recording every command run.
+type fakeRunner struct {
+	outputs map[string]string
+	ran     []string
+}
+
+func (f *fakeRunner) Run(name string, args ...string) (string, error) {
+	cmd := strings.Join(args[2:], " ")
+	f.ran = append(f.ran, args[1]+": "+cmd)
+	for prefix, out := range f.outputs {
+		if strings.HasPrefix(cmd, prefix) {
+			return out, nil
+		}
+	}
+	if strings.HasPrefix(cmd, "worktree add") || strings.HasPrefix(cmd, "worktree remove") || cmd == "worktree prune" {
+		return "", nil
+	}
+	return "", errors.New("exit status 1")
+}
+
+const listOutput = `worktree /code/api
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /code/api-feature-login
+HEAD 2222222222222222222222222222222222222222
+branch refs/heads/feature/login
+
+worktree /code/api-detached
+HEAD 3333333333333333333333333333333333333333
+detached
+
+`
+
+func TestList(t *testing.T) {
+	got, err := worktree.List("/code/api", &fakeRunner{outputs: map[string]string{"worktree list": listOutput}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []worktree.Worktree{
+		{Path: "/code/api", Branch: "main", Main: true},
+		{Path: "/code/api-feature-login", Branch: "feature/login"},
+		{Path: "/code/api-detached"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("List() = %+v, want %+v", got, want)
+	}
+	if linked := worktree.Linked(got); len(linked) != 2 || linked[0].Name() != "feature/login" || linked[1].Name() != "api-detached" {
+		t.Errorf("Linked() = %+v", linked)
+	}
+	if name := worktree.QualifiedName("/code/api", got[1]); name != "api@feature/login" {
+		t.Errorf("QualifiedName() = %q, want %q", name, "api@feature/login")
+	}
+}
+
+func TestInfo(t *testing.T) {
+	root := t.TempDir()
+	writeGitFile := func(dir, content string) string {
+		t.Helper()
+		path := filepath.Join(root, dir)
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(path, ".git"), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write .git: %v", err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name     string
+		dir      string
+		content  string
+		wantRepo string
+		wantOK   bool
+	}{
+		{"worktree of a repository", "api-feature", "gitdir: /code/api/.git/worktrees/api-feature\n", "/code/api", true},
+		{"worktree of a bare repository", "wt", "gitdir: /code/api.git/worktrees/wt\n", "/code/api.git", true},
+		{"relative git dir", "rel", "gitdir: ../api/.git/worktrees/rel\n", filepath.Join(root, "api"), true},
+		{"submodule", "sub", "gitdir: ../.git/modules/sub\n", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, ok := worktree.Info(writeGitFile(tt.dir, tt.content))
+			if repo != tt.wantRepo || ok != tt.wantOK {
+				t.Errorf("Info() = %q, %v; want %q, %v", repo, ok, tt.wantRepo, tt.wantOK)
+			}
+		})
+	}
+
+	t.Run("main working tree", func(t *testing.T) {
+		if err := os.MkdirAll(filepath.Join(root, "main", ".git"), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if _, ok := worktree.Info(filepath.Join(root, "main")); ok {
+			t.Error("main working tree reported as a linked worktree")
+		}
+	})
+}
+
+func TestEnsure(t *testing.T) {
+	t.Run("returns the existing worktree for a branch", func(t *testing.T) {
+		runner := &fakeRunner{outputs: map[string]string{"worktree list": listOutput}}
+		path, created, err := worktree.Ensure("/code/api", "feature/login", runner)
+		if err != nil || created || path != "/code/api-feature-login" {
+			t.Errorf("Ensure() = %q, %v, %v; want existing worktree", path, created, err)
+		}
+	})
+
+	t.Run("checks out an existing branch in a new worktree", func(t *testing.T) {
+		runner := &fakeRunner{outputs: map[string]string{
+			"worktree list": listOutput,
+			"rev-parse --verify --quiet refs/heads/fix": "abc",
+		}}
+		path, created, err := worktree.Ensure("/code/api", "fix", runner)
+		if err != nil || !created || path != "/code/api-fix" {
+			t.Fatalf("Ensure() = %q, %v, %v; want a created worktree", path, created, err)
+		}
+		if last := runner.ran[len(runner.ran)-1]; last != "/code/api: worktree add /code/api-fix fix" {
+			t.Errorf("ran %q", last)
+		}
+	})
+
+	t.Run("checks out a remote branch", func(t *testing.T) {
+		runner := &fakeRunner{outputs: map[string]string{
+			"worktree list": listOutput,
+			"for-each-ref":  "refs/remotes/origin/remote-only\n",
+		}}
+		if _, _, err := worktree.Ensure("/code/api", "remote-only", runner); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if last := runner.ran[len(runner.ran)-1]; last != "/code/api: worktree add /code/api-remote-only remote-only" {
+			t.Errorf("ran %q", last)
+		}
+	})
+
+	t.Run("creates a new branch", func(t *testing.T) {
+		runner := &fakeRunner{outputs: map[string]string{"worktree list": listOutput}}
+		path, _, err := worktree.Ensure("/code/api", "feature/new", runner)
+		if err != nil || path != "/code/api-feature-new" {
+			t.Fatalf("Ensure() = %q, %v", path, err)
+		}
+		if last := runner.ran[len(runner.ran)-1]; last != "/code/api: worktree add -b feature/new /code/api-feature-new" {
+			t.Errorf("ran %q", last)
+		}
+	})
+
+	t.Run("fails outside a repository", func(t *testing.T) {
+		if _, _, err := worktree.Ensure("/tmp", "main", &fakeRunner{}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestRemove(t *testing.T) {
+	root := t.TempDir()
+	wt := filepath.Join(root, "api-feature")
+	if err := os.MkdirAll(wt, 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: "+root+"/api/.git/worktrees/api-feature\n"), 0o644); err != nil {
+		t.Fatalf("failed to write .git: %v", err)
+	}
+
+	runner := &fakeRunner{}
+	manager := worktree.NewManager(runner)
+	if !manager.IsWorktree(wt) {
+		t.Fatal("expected a linked worktree")
+	}
+	if err := manager.Remove(wt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{root + "/api: worktree remove " + wt, root + "/api: worktree prune"}
+	if !slices.Equal(runner.ran, want) {
+		t.Errorf("ran %v, want %v", runner.ran, want)
+	}
+
+	t.Run("missing directory is a no-op", func(t *testing.T) {
+		if err := worktree.Remove(filepath.Join(root, "gone"), &fakeRunner{}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestLookup(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "api")
+	plain := filepath.Join(root, "notes")
+	for _, dir := range []string{filepath.Join(repo, ".git"), plain} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+	}
+
+	runner := &fakeRunner{outputs: map[string]string{"worktree list": listOutput}}
+	got := worktree.NewManager(runner).Lookup([]string{repo, plain})
+
+	if len(got) != 1 || len(got[repo]) != 2 {
+		t.Errorf("Lookup() = %+v, want two linked worktrees of %s", got, repo)
+	}
+	if len(runner.ran) != 1 {
+		t.Errorf("ran %v, want only the repository listed", runner.ran)
+	}
+}
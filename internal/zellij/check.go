@@ -0,0 +1,16 @@
+package zellij
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// CheckZellijAvailable verifies that zellij is installed and available on PATH.
+// Returns nil if zellij is found, or an error with install instructions if not.
+func CheckZellijAvailable() error {
+	_, err := exec.LookPath("zellij")
+	if err != nil {
+		return errors.New("Portal requires zellij. Install with: brew install zellij") //nolint:staticcheck // user-facing message matches the tmux check
+	}
+	return nil
+}
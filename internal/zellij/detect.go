@@ -0,0 +1,22 @@
+package zellij
+
+import (
+	"errors"
+	"os"
+)
+
+// InsideZellij reports whether Portal is running inside a Zellij session.
+// It checks whether the ZELLIJ environment variable is set and non-empty.
+func InsideZellij() bool {
+	return os.Getenv("ZELLIJ") != ""
+}
+
+// CurrentSessionName returns the name of the Zellij session Portal runs in,
+// from the ZELLIJ_SESSION_NAME environment variable.
+func CurrentSessionName() (string, error) {
+	name := os.Getenv("ZELLIJ_SESSION_NAME")
+	if name == "" {
+		return "", errors.New("failed to get current session name: ZELLIJ_SESSION_NAME is not set")
+	}
+	return name, nil
+}
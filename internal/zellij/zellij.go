@@ -0,0 +1,171 @@
+// Package zellij provides Zellij integration for Portal.
+package zellij
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Session represents a session listed by zellij list-sessions.
+type Session struct {
+	Name string
+	// Created is when the session was created, derived from its reported age.
+	Created time.Time
+	// Current reports the session the calling client is attached to.
+	Current bool
+	// Exited reports a session whose processes have ended; attaching to it
+	// resurrects it.
+	Exited bool
+}
+
+// Commander defines the interface for executing zellij commands.
+type Commander interface {
+	Run(args ...string) (string, error)
+}
+
+// RealCommander executes zellij commands via os/exec.
+type RealCommander struct{}
+
+// Run executes a zellij command with the given arguments and returns its output.
+func (r *RealCommander) Run(args ...string) (string, error) {
+	cmd := exec.Command("zellij", args...)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Client provides Zellij operations using a Commander.
+type Client struct {
+	cmd Commander
+}
+
+// NewClient creates a new Client with the given Commander.
+func NewClient(cmd Commander) *Client {
+	return &Client{cmd: cmd}
+}
+
+// ListSessions returns every session Zellij knows about, including exited ones.
+// Returns an empty slice and nil error when there are no sessions, which
+// zellij reports as a failure.
+func (c *Client) ListSessions() ([]Session, error) {
+	output, err := c.cmd.Run("list-sessions", "--no-formatting")
+	if err != nil || output == "" {
+		return []Session{}, nil
+	}
+
+	now := time.Now()
+	lines := strings.Split(output, "\n")
+	sessions := make([]Session, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		sessions = append(sessions, parseSession(line, now))
+	}
+	return sessions, nil
+}
+
+// createdPattern matches the age zellij prints after a session's name,
+// e.g. "[Created 1day 2h 3m 4s ago]".
+var createdPattern = regexp.MustCompile(` \[Created ([^\]]*) ago\]`)
+
+// agePattern matches one component of a session's age, e.g. "2h" or "3days".
+var agePattern = regexp.MustCompile(`(\d+)\s*(days?|h|m|s)\b`)
+
+// ageUnits maps the units zellij prints to their durations.
+var ageUnits = map[string]time.Duration{
+	"day":  24 * time.Hour,
+	"days": 24 * time.Hour,
+	"h":    time.Hour,
+	"m":    time.Minute,
+	"s":    time.Second,
+}
+
+// parseSession parses one line of list-sessions --no-formatting output:
+// the name, then "[Created <age> ago]", then "(current)" or
+// "(EXITED - attach to resurrect)" when they apply.
+func parseSession(line string, now time.Time) Session {
+	loc := createdPattern.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return Session{Name: line}
+	}
+
+	s := Session{Name: line[:loc[0]]}
+	var age time.Duration
+	for _, m := range agePattern.FindAllStringSubmatch(line[loc[2]:loc[3]], -1) {
+		n, _ := strconv.Atoi(m[1])
+		age += time.Duration(n) * ageUnits[m[2]]
+	}
+	s.Created = now.Add(-age).Truncate(time.Second)
+
+	rest := line[loc[1]:]
+	s.Current = strings.Contains(rest, "(current)")
+	s.Exited = strings.Contains(rest, "EXITED")
+	return s
+}
+
+// HasSession reports whether a session with the given name exists, running
+// or exited.
+func (c *Client) HasSession(name string) bool {
+	sessions, _ := c.ListSessions()
+	for _, s := range sessions {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSession creates a new background session with the given name, starting
+// its panes in dir. When shellCommand is non-empty, it runs in a new pane of
+// the session, since Zellij cannot replace the first pane's shell.
+func (c *Client) NewSession(name, dir, shellCommand string) error {
+	_, err := c.cmd.Run("attach", "--create-background", name, "options", "--default-cwd", dir)
+	if err != nil {
+		return fmt.Errorf("failed to create zellij session %q: %w", name, err)
+	}
+	if shellCommand == "" {
+		return nil
+	}
+	_, err = c.cmd.Run("--session", name, "run", "--cwd", dir, "--", "sh", "-c", shellCommand)
+	if err != nil {
+		return fmt.Errorf("failed to run command in zellij session %q: %w", name, err)
+	}
+	return nil
+}
+
+// KillSession ends the named session and forgets it, so it cannot be
+// resurrected. Exited sessions are forgotten too.
+func (c *Client) KillSession(name string) error {
+	_, err := c.cmd.Run("delete-session", "--force", name)
+	if err != nil {
+		return fmt.Errorf("failed to kill zellij session %q: %w", name, err)
+	}
+	return nil
+}
+
+// RenameSession renames a Zellij session from oldName to newName.
+func (c *Client) RenameSession(oldName, newName string) error {
+	_, err := c.cmd.Run("--session", oldName, "action", "rename-session", newName)
+	if err != nil {
+		return fmt.Errorf("failed to rename zellij session %q to %q: %w", oldName, newName, err)
+	}
+	return nil
+}
+
+// SwitchClient moves the current Zellij client to the named session.
+// Used when Portal is running inside an existing Zellij session.
+func (c *Client) SwitchClient(name string) error {
+	_, err := c.cmd.Run("action", "switch-session", name)
+	if err != nil {
+		return fmt.Errorf("failed to switch to session %q: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package zellij_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/zellij"
+)
+
+// MockCommander implements Commander for testing.
+type MockCommander struct {
+	Output string
+	Err    error
+	// Calls records all invocations.
+	Calls [][]string
+}
+
+// Run records the call and returns the configured output and error.
+func (m *MockCommander) Run(args ...string) (string, error) {
+	m.Calls = append(m.Calls, args)
+	return m.Output, m.Err
+}
+
+func TestListSessions(t *testing.T) {
+	t.Run("parses running, current and exited sessions", func(t *testing.T) {
+		cmd := &MockCommander{Output: "api [Created 1day 2h 3m 4s ago] (current)\n" +
+			"web [Created 45s ago] \n" +
+			"old [Created 3days ago] (EXITED - attach to resurrect)"}
+
+		sessions, err := zellij.NewClient(cmd).ListSessions()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(sessions) != 3 {
+			t.Fatalf("got %d sessions, want 3: %+v", len(sessions), sessions)
+		}
+		wantAges := []time.Duration{26*time.Hour + 3*time.Minute + 4*time.Second, 45 * time.Second, 72 * time.Hour}
+		for i, want := range []zellij.Session{
+			{Name: "api", Current: true},
+			{Name: "web"},
+			{Name: "old", Exited: true},
+		} {
+			got := sessions[i]
+			if got.Name != want.Name || got.Current != want.Current || got.Exited != want.Exited {
+				t.Errorf("session %d = %+v, want %+v", i, got, want)
+			}
+			if age := time.Since(got.Created); age < wantAges[i] || age > wantAges[i]+time.Minute {
+				t.Errorf("session %q created %v ago, want %v", got.Name, age, wantAges[i])
+			}
+		}
+		if !reflect.DeepEqual(cmd.Calls, [][]string{{"list-sessions", "--no-formatting"}}) {
+			t.Errorf("calls = %v", cmd.Calls)
+		}
+	})
+
+	t.Run("returns no sessions when zellij has none", func(t *testing.T) {
+		cmd := &MockCommander{Err: errors.New("exit status 1")}
+
+		sessions, err := zellij.NewClient(cmd).ListSessions()
+		if err != nil || len(sessions) != 0 {
+			t.Errorf("ListSessions() = %v, %v; want no sessions", sessions, err)
+		}
+	})
+
+	t.Run("keeps names without a creation time", func(t *testing.T) {
+		sessions, _ := zellij.NewClient(&MockCommander{Output: "plain"}).ListSessions()
+		if len(sessions) != 1 || sessions[0].Name != "plain" || !sessions[0].Created.IsZero() {
+			t.Errorf("ListSessions() = %+v", sessions)
+		}
+	})
+}
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(c *zellij.Client) error
+		want [][]string
+	}{
+		{
+			name: "new session starts in the directory",
+			run:  func(c *zellij.Client) error { return c.NewSession("api", "/code/api", "") },
+			want: [][]string{{"attach", "--create-background", "api", "options", "--default-cwd", "/code/api"}},
+		},
+		{
+			name: "new session runs the shell command in a pane",
+			run:  func(c *zellij.Client) error { return c.NewSession("api", "/code/api", "claude") },
+			want: [][]string{
+				{"attach", "--create-background", "api", "options", "--default-cwd", "/code/api"},
+				{"--session", "api", "run", "--cwd", "/code/api", "--", "sh", "-c", "claude"},
+			},
+		},
+		{
+			name: "kill deletes the session",
+			run:  func(c *zellij.Client) error { return c.KillSession("api") },
+			want: [][]string{{"delete-session", "--force", "api"}},
+		},
+		{
+			name: "rename targets the session",
+			run:  func(c *zellij.Client) error { return c.RenameSession("api", "backend") },
+			want: [][]string{{"--session", "api", "action", "rename-session", "backend"}},
+		},
+		{
+			name: "switch moves the current client",
+			run:  func(c *zellij.Client) error { return c.SwitchClient("api") },
+			want: [][]string{{"action", "switch-session", "api"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &MockCommander{}
+			if err := tt.run(zellij.NewClient(cmd)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(cmd.Calls, tt.want) {
+				t.Errorf("calls = %v, want %v", cmd.Calls, tt.want)
+			}
+		})
+	}
+
+	t.Run("errors name the session", func(t *testing.T) {
+		err := zellij.NewClient(&MockCommander{Err: errors.New("exit status 1")}).KillSession("api")
+		if err == nil || err.Error() != `failed to kill zellij session "api": exit status 1` {
+			t.Errorf("KillSession() error = %v", err)
+		}
+	})
+}
+
+func TestInsideZellij(t *testing.T) {
+	t.Setenv("ZELLIJ", "0")
+	t.Setenv("ZELLIJ_SESSION_NAME", "api")
+	if !zellij.InsideZellij() {
+		t.Error("InsideZellij() = false with ZELLIJ set")
+	}
+	if name, err := zellij.CurrentSessionName(); err != nil || name != "api" {
+		t.Errorf("CurrentSessionName() = %q, %v; want api", name, err)
+	}
+
+	t.Setenv("ZELLIJ", "")
+	t.Setenv("ZELLIJ_SESSION_NAME", "")
+	if zellij.InsideZellij() {
+		t.Error("InsideZellij() = true without ZELLIJ")
+	}
+	if _, err := zellij.CurrentSessionName(); err == nil {
+		t.Error("expected an error outside zellij")
+	}
+}